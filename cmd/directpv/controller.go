@@ -57,6 +57,9 @@ func startController(ctx context.Context) error {
 	klog.V(3).Infof("Identity server started")
 
 	ctrlServer := controller.NewServer()
+	if err := ctrlServer.StartDriveCache(ctx); err != nil {
+		return err
+	}
 	klog.V(3).Infof("Controller server started")
 
 	errCh := make(chan error)
@@ -19,6 +19,7 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -27,13 +28,19 @@ import (
 	"github.com/minio/directpv/pkg/csi/node"
 	"github.com/minio/directpv/pkg/device"
 	"github.com/minio/directpv/pkg/drive"
+	"github.com/minio/directpv/pkg/snapshot"
 	"github.com/minio/directpv/pkg/sys"
 	"github.com/minio/directpv/pkg/volume"
 	"github.com/spf13/cobra"
 	"k8s.io/klog/v2"
 )
 
-var metricsPort = consts.MetricsPort
+var (
+	metricsPort       = consts.MetricsPort
+	usageSyncInterval = volume.DefaultUsageSyncInterval
+	warningThreshold  = volume.DefaultUsageWarningThreshold
+	criticalThreshold = volume.DefaultUsageCriticalThreshold
+)
 
 var nodeServerCmd = &cobra.Command{
 	Use:           consts.NodeServerName,
@@ -41,6 +48,15 @@ var nodeServerCmd = &cobra.Command{
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	RunE: func(c *cobra.Command, _ []string) error {
+		if warningThreshold < 0 || warningThreshold > 100 {
+			return fmt.Errorf("invalid usage warning threshold %v; value must be in range 0 to 100", warningThreshold)
+		}
+		if criticalThreshold < 0 || criticalThreshold > 100 {
+			return fmt.Errorf("invalid usage critical threshold %v; value must be in range 0 to 100", criticalThreshold)
+		}
+		if warningThreshold != 0 && criticalThreshold != 0 && warningThreshold >= criticalThreshold {
+			return fmt.Errorf("usage warning threshold %v must be less than usage critical threshold %v", warningThreshold, criticalThreshold)
+		}
 		if err := sys.Mkdir(consts.MountRootDir, 0o755); err != nil && !errors.Is(err, os.ErrExist) {
 			return err
 		}
@@ -56,6 +72,9 @@ var nodeServerCmd = &cobra.Command{
 
 func init() {
 	nodeServerCmd.PersistentFlags().IntVar(&metricsPort, "metrics-port", metricsPort, "Metrics port at "+consts.AppPrettyName+" exports metrics data")
+	nodeServerCmd.PersistentFlags().DurationVar(&usageSyncInterval, "usage-sync-interval", usageSyncInterval, "Interval to sync volume usage; 0 disables syncing")
+	nodeServerCmd.PersistentFlags().IntVar(&warningThreshold, "usage-warning-threshold", warningThreshold, "Volume usage percentage to warn volume is nearly full; 0 disables warning")
+	nodeServerCmd.PersistentFlags().IntVar(&criticalThreshold, "usage-critical-threshold", criticalThreshold, "Volume usage percentage to warn volume is critically full; 0 disables warning")
 }
 
 func startNodeServer(ctx context.Context) error {
@@ -80,7 +99,7 @@ func startNodeServer(ctx context.Context) error {
 	errCh := make(chan error)
 
 	go func() {
-		volume.StartController(ctx, nodeID)
+		volume.StartController(ctx, nodeID, usageSyncInterval, warningThreshold, criticalThreshold)
 		errCh <- errors.New("volume controller stopped")
 	}()
 
@@ -89,6 +108,11 @@ func startNodeServer(ctx context.Context) error {
 		errCh <- errors.New("drive controller stopped")
 	}()
 
+	go func() {
+		snapshot.StartController(ctx, nodeID)
+		errCh <- errors.New("snapshot controller stopped")
+	}()
+
 	nodeServer := node.NewServer(
 		ctx,
 		identity,
@@ -100,6 +124,14 @@ func startNodeServer(ctx context.Context) error {
 	)
 	klog.V(3).Infof("Node server started")
 
+	if err := nodeServer.ReconcileMounts(ctx); err != nil {
+		klog.ErrorS(err, "unable to reconcile volume mounts")
+	}
+
+	if err := node.CleanupEphemeralVolumes(ctx, nodeID); err != nil {
+		klog.ErrorS(err, "unable to cleanup ephemeral volumes")
+	}
+
 	go func() {
 		if err := runServers(ctx, csiEndpoint, idServer, nil, nodeServer); err != nil {
 			klog.ErrorS(err, "unable to start GRPC servers")
@@ -31,6 +31,8 @@ import (
 var errInvalidLabel = errors.New("invalid label")
 
 var driveStatusValues = []string{
+	strings.ToLower(string(directpvtypes.DriveStatusDrained)),
+	strings.ToLower(string(directpvtypes.DriveStatusDraining)),
 	strings.ToLower(string(directpvtypes.DriveStatusError)),
 	strings.ToLower(string(directpvtypes.DriveStatusLost)),
 	strings.ToLower(string(directpvtypes.DriveStatusMoving)),
@@ -53,6 +53,8 @@ var (
 	legacyFlag       bool
 	declarativeFlag  bool
 	openshiftFlag    bool
+	snapshotFlag     bool
+	vacFlag          bool
 )
 
 var installCmd = &cobra.Command{
@@ -80,7 +82,13 @@ var installCmd = &cobra.Command{
    $ kubectl {PLUGIN_NAME} install --apparmor-profile directpv
 
 7. Install DirectPV with seccomp profile
-   $ kubectl {PLUGIN_NAME} install --seccomp-profile profiles/seccomp.json`,
+   $ kubectl {PLUGIN_NAME} install --seccomp-profile profiles/seccomp.json
+
+8. Install DirectPV with volume snapshot support
+   $ kubectl {PLUGIN_NAME} install --enable-snapshot
+
+9. Install DirectPV with VolumeAttributesClass support
+   $ kubectl {PLUGIN_NAME} install --enable-volume-attributes-class`,
 		`{PLUGIN_NAME}`,
 		consts.AppName,
 	),
@@ -121,6 +129,8 @@ func init() {
 	installCmd.PersistentFlags().BoolVar(&declarativeFlag, "declarative", declarativeFlag, "Output YAML for declarative installation")
 	installCmd.PersistentFlags().MarkHidden("declarative")
 	installCmd.PersistentFlags().BoolVar(&openshiftFlag, "openshift", openshiftFlag, "Use OpenShift specific installation")
+	installCmd.PersistentFlags().BoolVar(&snapshotFlag, "enable-snapshot", snapshotFlag, "Enable volume snapshot support; requires snapshot CRDs and snapshot controller in the cluster")
+	installCmd.PersistentFlags().BoolVar(&vacFlag, "enable-volume-attributes-class", vacFlag, "Enable volume modification by VolumeAttributesClass; requires VolumeAttributesClass feature enabled in the cluster")
 }
 
 func validateInstallCmd() (err error) {
@@ -184,6 +194,8 @@ func installMain(ctx context.Context) {
 		OutputFormat:     outputFormat,
 		Declarative:      declarativeFlag,
 		Openshift:        openshiftFlag,
+		EnableSnapshot:   snapshotFlag,
+		EnableVAC:        vacFlag,
 	}
 	if file != nil {
 		args.AuditWriter = file
@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"fmt"
 	"os"
 	"strings"
 
@@ -183,6 +184,7 @@ func listVolumesMain(ctx context.Context) {
 	headers := table.Row{
 		"VOLUME",
 		"CAPACITY",
+		"USED",
 		"NODE",
 		"DRIVE",
 		"PODNAME",
@@ -247,9 +249,23 @@ func listVolumesMain(ctx context.Context) {
 			status += ",Suspended"
 		}
 
+		if clone := volume.Status.Clone; clone != nil {
+			switch clone.State {
+			case directpvtypes.CloneStateInProgress:
+				percent := 0
+				if clone.TotalBytes > 0 {
+					percent = int(clone.CopiedBytes * 100 / clone.TotalBytes)
+				}
+				status += fmt.Sprintf(",Cloning(%v%%)", percent)
+			case directpvtypes.CloneStateFailed:
+				status += ",CloneFailed"
+			}
+		}
+
 		row := []interface{}{
 			volume.Name,
 			printableBytes(volume.Status.TotalCapacity),
+			printableBytes(volume.Status.UsedCapacity),
 			volume.GetNodeID(),
 			printableString(string(volume.GetDriveName())),
 			printableString(volume.GetPodName()),
@@ -171,11 +171,14 @@ Use "{{.CommandPath}} [command] --help" for more information about this command.
 	mainCmd.AddCommand(uncordonCmd)
 	mainCmd.AddCommand(migrateCmd)
 	mainCmd.AddCommand(moveCmd)
+	mainCmd.AddCommand(replaceCmd)
+	mainCmd.AddCommand(drainCmd)
 	mainCmd.AddCommand(cleanCmd)
 	mainCmd.AddCommand(suspendCmd)
 	mainCmd.AddCommand(resumeCmd)
 	mainCmd.AddCommand(repairCmd)
 	mainCmd.AddCommand(removeCmd)
+	mainCmd.AddCommand(removeNodeCmd)
 	mainCmd.AddCommand(uninstallCmd)
 	mainCmd.SetHelpCommand(&cobra.Command{
 		Hidden: true,
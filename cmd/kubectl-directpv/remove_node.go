@@ -0,0 +1,88 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/directpv/pkg/admin"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/spf13/cobra"
+)
+
+var removeNodeCmd = &cobra.Command{
+	Use:           "remove-node NODE",
+	Short:         fmt.Sprintf("Forcefully remove all %s resources of a node", consts.AppPrettyName),
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Example: strings.ReplaceAll(
+		`1. Show volumes, persistent volumes, persistent volume claims and pods affected by removing node 'node1'
+   $ kubectl {PLUGIN_NAME} remove-node node1 --dry-run
+
+2. Remove node 'node1' after removing it from node server DaemonSet
+   $ kubectl {PLUGIN_NAME} remove-node node1
+
+3. Remove node 'node1' even if node server is still running on it
+   $ kubectl {PLUGIN_NAME} remove-node node1 --force`,
+		`{PLUGIN_NAME}`,
+		consts.AppName,
+	),
+	Run: func(c *cobra.Command, args []string) {
+		if len(args) != 1 {
+			eprintf(true, "only one node must be provided\n")
+			os.Exit(-1)
+		}
+
+		node := strings.TrimSpace(args[0])
+		if node == "" {
+			eprintf(true, "empty node\n")
+			os.Exit(-1)
+		}
+
+		removeNodeMain(c.Context(), directpvtypes.NodeID(node))
+	},
+}
+
+func init() {
+	setFlagOpts(removeNodeCmd)
+
+	removeNodeCmd.PersistentFlags().BoolVar(&forceFlag, "force", forceFlag, "Remove even if node server is still running on the node")
+	addDryRunFlag(removeNodeCmd, "Run in dry run mode")
+}
+
+func removeNodeMain(ctx context.Context, node directpvtypes.NodeID) {
+	_, err := adminClient.RemoveNode(
+		ctx,
+		admin.RemoveNodeArgs{
+			Node:       node,
+			BackupFile: consts.AppName + "-node-" + string(node) + "-" + time.Now().Format(time.RFC3339) + ".yaml",
+			Force:      forceFlag,
+			DryRun:     dryRunFlag,
+		},
+		logFunc,
+	)
+	if err != nil {
+		eprintf(!errors.Is(err, admin.ErrNoMatchingResourcesFound), "%v\n", err)
+		os.Exit(1)
+	}
+}
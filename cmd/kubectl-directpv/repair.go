@@ -21,7 +21,9 @@ import (
 	"errors"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/jedib0t/go-pretty/v6/table"
 	"github.com/minio/directpv/pkg/admin"
 	"github.com/minio/directpv/pkg/consts"
 	"github.com/spf13/cobra"
@@ -30,16 +32,23 @@ import (
 var (
 	forceFlag           = false
 	disablePrefetchFlag = false
+	repairStatusFlag    = false
 )
 
 var repairCmd = &cobra.Command{
-	Use:           "repair DRIVE ...",
+	Use:           "repair [DRIVE ...]",
 	Short:         "Repair filesystem of drives",
 	SilenceUsage:  true,
 	SilenceErrors: true,
 	Example: strings.ReplaceAll(
 		`1. Repair drives
-   $ kubectl {PLUGIN_NAME} repair 3b562992-f752-4a41-8be4-4e688ae8cd4c`,
+   $ kubectl {PLUGIN_NAME} repair 3b562992-f752-4a41-8be4-4e688ae8cd4c
+
+2. Show last repair result of all drives
+   $ kubectl {PLUGIN_NAME} repair --status
+
+3. Show last repair result of a drive
+   $ kubectl {PLUGIN_NAME} repair --status 3b562992-f752-4a41-8be4-4e688ae8cd4c`,
 		`{PLUGIN_NAME}`,
 		consts.AppName,
 	),
@@ -50,6 +59,11 @@ var repairCmd = &cobra.Command{
 			os.Exit(-1)
 		}
 
+		if repairStatusFlag {
+			repairStatusMain(c.Context())
+			return
+		}
+
 		repairMain(c.Context())
 	},
 }
@@ -60,6 +74,7 @@ func init() {
 	addDryRunFlag(repairCmd, "Repair drives with no modify mode")
 	repairCmd.PersistentFlags().BoolVar(&forceFlag, "force", forceFlag, "Force log zeroing")
 	repairCmd.PersistentFlags().BoolVar(&disablePrefetchFlag, "disable-prefetch", disablePrefetchFlag, "Disable prefetching of inode and directory blocks")
+	repairCmd.PersistentFlags().BoolVar(&repairStatusFlag, "status", repairStatusFlag, "Show last repair result of drives")
 }
 
 func validateRepairCmd() error {
@@ -67,7 +82,7 @@ func validateRepairCmd() error {
 		return err
 	}
 
-	if len(driveIDArgs) == 0 {
+	if len(driveIDArgs) == 0 && !repairStatusFlag {
 		return errors.New("no drive provided to repair")
 	}
 
@@ -90,3 +105,60 @@ func repairMain(ctx context.Context) {
 		os.Exit(1)
 	}
 }
+
+func repairStatusMain(ctx context.Context) {
+	drives, err := adminClient.NewDriveLister().
+		DriveIDSelector(driveIDSelectors).
+		Get(ctx)
+	if err != nil {
+		eprintf(true, "%v\n", err)
+		os.Exit(1)
+	}
+
+	writer := newTableWriter(
+		table.Row{
+			"NODE",
+			"NAME",
+			"DRIVE ID",
+			"RESULT",
+			"REPAIRED AT",
+			"SUMMARY",
+		},
+		[]table.SortBy{
+			{
+				Name: "NODE",
+				Mode: table.Asc,
+			},
+			{
+				Name: "NAME",
+				Mode: table.Asc,
+			},
+		},
+		false,
+	)
+
+	for _, drive := range drives {
+		condition := drive.GetRepairedCondition()
+		if condition == nil {
+			continue
+		}
+		writer.AppendRow(
+			[]interface{}{
+				drive.GetNodeID(),
+				drive.GetDriveName(),
+				drive.GetDriveID(),
+				condition.Reason,
+				condition.LastTransitionTime.Format(time.RFC3339),
+				condition.Message,
+			},
+		)
+	}
+
+	if writer.Length() != 0 {
+		writer.Render()
+		return
+	}
+
+	eprintf(false, "No repair results found\n")
+	os.Exit(1)
+}
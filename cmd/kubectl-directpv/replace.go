@@ -0,0 +1,118 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/minio/directpv/pkg/admin"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/utils"
+	"github.com/spf13/cobra"
+)
+
+var (
+	nodeArg        string           // --node flag
+	replaceTimeout = 24 * time.Hour // --timeout flag
+)
+
+var replaceCmd = &cobra.Command{
+	Use:           "replace SRC-DRIVE DEST-DRIVE",
+	SilenceUsage:  true,
+	SilenceErrors: true,
+	Short:         "Replace source drive by destination drive on a same node",
+	Example: strings.ReplaceAll(
+		`1. Replace drive 'sdb' by drive 'sdc' on node 'node1'
+   $ kubectl {PLUGIN_NAME} replace sdb sdc --node=node1
+
+2. Replace drive af3b8b4c-73b4-4a74-84b7-1ec30492a6f0 by drive 834e8f4c-14f4-49b9-9b77-e8ac854108d5
+   $ kubectl {PLUGIN_NAME} replace af3b8b4c-73b4-4a74-84b7-1ec30492a6f0 834e8f4c-14f4-49b9-9b77-e8ac854108d5
+
+3. Show what would be done to replace drive 'sdb' by drive 'sdc' on node 'node1'
+   $ kubectl {PLUGIN_NAME} replace sdb sdc --node=node1 --dry-run`,
+		`{PLUGIN_NAME}`,
+		consts.AppName,
+	),
+	Run: func(c *cobra.Command, args []string) {
+		if err := validateReplaceCmd(args); err != nil {
+			eprintf(true, "%v\n", err)
+			os.Exit(-1)
+		}
+
+		replaceMain(c.Context(), strings.TrimSpace(args[0]), strings.TrimSpace(args[1]))
+	},
+}
+
+func init() {
+	setFlagOpts(replaceCmd)
+
+	replaceCmd.PersistentFlags().StringVar(&nodeArg, "node", nodeArg, "Node of the drives; must be provided if drive name is used")
+	replaceCmd.PersistentFlags().DurationVar(&replaceTimeout, "timeout", replaceTimeout, "Timeout to wait for volumes to be moved")
+	addDryRunFlag(replaceCmd, "Run in dry run mode")
+}
+
+func validateReplaceCmd(args []string) error {
+	if len(args) != 2 {
+		return errors.New("only one source and one destination drive must be provided")
+	}
+
+	src := strings.TrimSpace(args[0])
+	if src == "" {
+		return errors.New("empty source drive")
+	}
+
+	dest := strings.TrimSpace(args[1])
+	if dest == "" {
+		return errors.New("empty destination drive")
+	}
+
+	if src == dest {
+		return errors.New("source and destination drives are same")
+	}
+
+	nodeArg = strings.TrimSpace(nodeArg)
+	if nodeArg == "" && (!utils.IsUUID(src) || !utils.IsUUID(dest)) {
+		return errors.New("--node must be provided if drive name is used")
+	}
+
+	return nil
+}
+
+func replaceMain(ctx context.Context, src, dest string) {
+	ctx, cancel := context.WithTimeout(ctx, replaceTimeout)
+	defer cancel()
+
+	_, err := adminClient.Replace(
+		ctx,
+		admin.ReplaceArgs{
+			Source:      src,
+			Destination: dest,
+			Node:        directpvtypes.NodeID(nodeArg),
+			DryRun:      dryRunFlag,
+		},
+		logFunc,
+	)
+	if err != nil {
+		eprintf(true, "%v\n", err)
+		os.Exit(1)
+	}
+}
@@ -68,6 +68,7 @@ function main() {
     push_image "quay.io/minio/csi-provisioner:v2.2.0-go1.18"
     push_image "quay.io/minio/livenessprobe:v2.14.0-0"
     push_image "quay.io/minio/csi-resizer:v1.12.0-0"
+    push_image "quay.io/minio/csi-external-health-monitor-controller:v0.13.0-0"
     release=$(curl -sfL "https://api.github.com/repos/minio/directpv/releases/latest" | awk '/tag_name/ { print substr($2, 3, length($2)-4) }')
     push_image "quay.io/minio/directpv:v${release}"
 }
@@ -0,0 +1,152 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin
+
+import (
+	"context"
+	"fmt"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// DrainDriveArgs denotes the args for draining the drive
+type DrainDriveArgs struct {
+	Nodes            []string
+	Drives           []string
+	DriveIDSelectors []directpvtypes.DriveID
+	DryRun           bool
+}
+
+// DrainDriveResult represents the draining drive
+type DrainDriveResult struct {
+	NodeID    directpvtypes.NodeID
+	DriveName directpvtypes.DriveName
+	DriveID   directpvtypes.DriveID
+	Volumes   []string
+}
+
+// DrainDrives marks the drives as draining. Node server moves the unpublished
+// volumes of the drives to other drives on the same node and marks the drives
+// as drained once they are empty.
+func (client *Client) DrainDrives(ctx context.Context, args DrainDriveArgs, log LogFunc) (results []DrainDriveResult, err error) {
+	if log == nil {
+		log = nullLogger
+	}
+
+	var processed bool
+
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+
+	resultCh := client.NewDriveLister().
+		NodeSelector(utils.ToLabelValues(args.Nodes)).
+		DriveNameSelector(utils.ToLabelValues(args.Drives)).
+		DriveIDSelector(args.DriveIDSelectors).
+		List(ctx)
+	for result := range resultCh {
+		if result.Err != nil {
+			err = result.Err
+			return
+		}
+
+		processed = true
+
+		switch result.Drive.Status.Status {
+		case directpvtypes.DriveStatusDraining, directpvtypes.DriveStatusDrained:
+			continue
+		case directpvtypes.DriveStatusReady:
+		default:
+			err = fmt.Errorf("unable to drain drive %v; drive is in %v status", result.Drive.GetDriveID(), result.Drive.Status.Status)
+			return
+		}
+
+		var volumes []string
+		if names := result.Drive.GetVolumes(); len(names) != 0 {
+			for vresult := range client.NewVolumeLister().VolumeNameSelector(names).IgnoreNotFound(true).List(ctx) {
+				if vresult.Err != nil {
+					err = vresult.Err
+					return
+				}
+
+				volumes = append(volumes, vresult.Volume.Name)
+				if vresult.Volume.IsPublished() {
+					log(
+						LogMessage{
+							Type:             InfoLogType,
+							Message:          "volume is published",
+							Values:           map[string]any{"volume": vresult.Volume.Name},
+							FormattedMessage: fmt.Sprintf("Volume %v is published; it will be moved once unpublished\n", vresult.Volume.Name),
+						},
+					)
+				}
+			}
+		}
+
+		driveClient := client.Drive()
+		updateFunc := func() error {
+			drive, err := driveClient.Get(ctx, result.Drive.Name, metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if drive.Status.Status != directpvtypes.DriveStatusReady {
+				return fmt.Errorf("drive is in %v status", drive.Status.Status)
+			}
+			drive.Unschedulable()
+			drive.Status.Status = directpvtypes.DriveStatusDraining
+			if !args.DryRun {
+				if _, err := driveClient.Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()}); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+		if err = retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+			err = fmt.Errorf("unable to drain drive %v; %v", result.Drive.GetDriveID(), err)
+			return
+		}
+
+		log(
+			LogMessage{
+				Type:    InfoLogType,
+				Message: "draining drive",
+				Values: map[string]any{
+					"node":        result.Drive.GetNodeID(),
+					"driveName":   result.Drive.GetDriveName(),
+					"volumeCount": len(volumes),
+				},
+				FormattedMessage: fmt.Sprintf("Draining drive %v/%v having %v volume(s)\n", result.Drive.GetNodeID(), result.Drive.GetDriveName(), len(volumes)),
+			},
+		)
+
+		results = append(results, DrainDriveResult{
+			NodeID:    result.Drive.GetNodeID(),
+			DriveName: result.Drive.GetDriveName(),
+			DriveID:   result.Drive.GetDriveID(),
+			Volumes:   volumes,
+		})
+	}
+
+	if !processed {
+		return nil, ErrNoMatchingResourcesFound
+	}
+
+	return results, nil
+}
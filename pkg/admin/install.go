@@ -69,6 +69,10 @@ type InstallArgs struct {
 	Declarative bool
 	// Openshift when set, runs openshift specific installation
 	Openshift bool
+	// EnableSnapshot when set, deploys the CSI snapshotter sidecar
+	EnableSnapshot bool
+	// EnableVAC when set, enables VolumeAttributesClass feature in the CSI sidecars
+	EnableVAC bool
 	// ProgressCh represents the progress channel
 	ProgressCh chan<- installer.Message
 	// AuditWriter denotes the writer passed to record the audit log
@@ -109,6 +113,8 @@ func (client *Client) Install(ctx context.Context, args InstallArgs, installerTa
 	installerArgs.KubeVersion = args.KubeVersion
 	installerArgs.Legacy = client.isLegacyEnabled(ctx, args)
 	installerArgs.PluginVersion = version
+	installerArgs.EnableSnapshot = args.EnableSnapshot
+	installerArgs.EnableVAC = args.EnableVAC
 	if args.AuditWriter != nil {
 		installerArgs.ObjectWriter = args.AuditWriter
 	}
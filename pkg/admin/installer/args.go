@@ -39,6 +39,10 @@ const (
 	livenessProbeImage = "livenessprobe@sha256:783010e10e4d74b6b2b157a4b52772c5a264fd76bb2ad671054b8c3f706c8324"
 	// csiResizerImage = csi-resizer:v1.12.0-0
 	csiResizerImage = "csi-resizer@sha256:58fa627393f20892b105a137d27e236dfaec233a3a64980f84dcb15f38c21533"
+	// csiSnapshotterImage = csi-snapshotter:v8.0.1-0
+	csiSnapshotterImage = "csi-snapshotter:v8.0.1-0"
+	// csiHealthMonitorImage = csi-external-health-monitor-controller:v0.13.0-0
+	csiHealthMonitorImage = "csi-external-health-monitor-controller:v0.13.0-0"
 
 	// openshiftCSIProvisionerImage = openshift4/ose-csi-external-provisioner-rhel8:v4.12.0-202407151105.p0.g3aa7c52.assembly.stream.el8
 	openshiftCSIProvisionerImage = "registry.redhat.io/openshift4/ose-csi-external-provisioner-rhel8@sha256:8bf8aa8975790e19ba107fd58699f98389e3fb692d192f4df3078fff7f0a4bba"
@@ -73,12 +77,17 @@ type Args struct {
 	ProgressCh       chan<- Message
 	ForceUninstall   bool
 	PluginVersion    string
+	EnableSnapshot   bool
+	EnableVAC        bool
 
 	podSecurityAdmission     bool
+	storageCapacity          bool
 	csiProvisionerImage      string
 	nodeDriverRegistrarImage string
 	livenessProbeImage       string
 	csiResizerImage          string
+	csiSnapshotterImage      string
+	csiHealthMonitorImage    string
 }
 
 // NewArgs creates arguments for DirectPV installation.
@@ -92,6 +101,8 @@ func NewArgs(image string) *Args {
 		nodeDriverRegistrarImage: nodeDriverRegistrarImage,
 		livenessProbeImage:       livenessProbeImage,
 		csiResizerImage:          csiResizerImage,
+		csiSnapshotterImage:      csiSnapshotterImage,
+		csiHealthMonitorImage:    csiHealthMonitorImage,
 	}
 }
 
@@ -173,3 +184,11 @@ func (args *Args) getCSIResizerImage() string {
 	}
 	return path.Join(args.Registry, args.Org, args.csiResizerImage)
 }
+
+func (args *Args) getCSISnapshotterImage() string {
+	return path.Join(args.Registry, args.Org, args.csiSnapshotterImage)
+}
+
+func (args *Args) getCSIHealthMonitorImage() string {
+	return path.Join(args.Registry, args.Org, args.csiHealthMonitorImage)
+}
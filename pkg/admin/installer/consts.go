@@ -30,6 +30,8 @@ const (
 	selectorKey              = "selector." + consts.GroupName
 	kubeNodeNameEnvVarName   = "KUBE_NODE_NAME"
 	csiEndpointEnvVarName    = "CSI_ENDPOINT"
+	namespaceEnvVarName      = "NAMESPACE"
+	podNameEnvVarName        = "POD_NAME"
 	pluginName               = "kubectl-" + consts.AppName
 	selectorValueEnabled     = "enabled"
 	serviceSelector          = "selector." + consts.GroupName + ".service"
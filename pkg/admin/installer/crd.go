@@ -44,6 +44,9 @@ var nodesYAML []byte
 //go:embed directpv.min.io_directpvinitrequests.yaml
 var initrequestsYAML []byte
 
+//go:embed directpv.min.io_directpvsnapshots.yaml
+var snapshotsYAML []byte
+
 type crdTask struct {
 	client *client.Client
 }
@@ -53,7 +56,7 @@ func (crdTask) Name() string {
 }
 
 func (crdTask) Start(ctx context.Context, args *Args) error {
-	if !sendStartMessage(ctx, args.ProgressCh, 4) {
+	if !sendStartMessage(ctx, args.ProgressCh, 5) {
 		return errSendProgress
 	}
 	return nil
@@ -227,7 +230,11 @@ func (t crdTask) createCRDs(ctx context.Context, args *Args) (err error) {
 		return err
 	}
 
-	return register(initrequestsYAML, 4)
+	if err := register(initrequestsYAML, 4); err != nil {
+		return err
+	}
+
+	return register(snapshotsYAML, 5)
 }
 
 func (t crdTask) removeVolumes(ctx context.Context) error {
@@ -335,6 +342,31 @@ func (t crdTask) removeInitRequests(ctx context.Context) error {
 	return nil
 }
 
+func (t crdTask) removeSnapshots(ctx context.Context) error {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+
+	for result := range t.client.NewSnapshotLister().List(ctx) {
+		if result.Err != nil {
+			if apierrors.IsNotFound(result.Err) {
+				break
+			}
+			return result.Err
+		}
+		result.Snapshot.Finalizers = []string{}
+		_, err := t.client.Snapshot().Update(ctx, &result.Snapshot, metav1.UpdateOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+		err = t.client.Snapshot().Delete(ctx, result.Snapshot.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func (t crdTask) deleteCRDs(ctx context.Context, force bool) error {
 	if !force {
 		return nil
@@ -356,6 +388,10 @@ func (t crdTask) deleteCRDs(ctx context.Context, force bool) error {
 		return err
 	}
 
+	if err := t.removeSnapshots(ctx); err != nil {
+		return err
+	}
+
 	driveCRDName := consts.DriveResource + "." + consts.GroupName
 	err := t.client.CRD().Delete(ctx, driveCRDName, metav1.DeleteOptions{})
 	if err != nil && !apierrors.IsNotFound(err) {
@@ -380,5 +416,11 @@ func (t crdTask) deleteCRDs(ctx context.Context, force bool) error {
 		return err
 	}
 
+	snapshotCRDName := consts.SnapshotResource + "." + consts.GroupName
+	err = t.client.CRD().Delete(ctx, snapshotCRDName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
 	return nil
 }
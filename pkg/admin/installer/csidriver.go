@@ -106,9 +106,20 @@ func (t csiDriverTask) doCreateCSIDriver(ctx context.Context, args *Args, versio
 			},
 		}
 
+		if args.storageCapacity && !legacy {
+			storageCapacity := true
+			csiDriver.Spec.StorageCapacity = &storageCapacity
+		}
+
 		if !args.DryRun && !args.Declarative {
 			_, err := t.client.Kube().StorageV1().CSIDrivers().Create(ctx, csiDriver, metav1.CreateOptions{})
-			if err != nil && !apierrors.IsAlreadyExists(err) {
+			switch {
+			case apierrors.IsAlreadyExists(err):
+				// StorageCapacity is the only mutable field; hence it is updated on upgrade.
+				if err := t.updateStorageCapacity(ctx, csiDriver); err != nil {
+					return err
+				}
+			case err != nil:
 				return err
 			}
 		}
@@ -150,6 +161,24 @@ func (t csiDriverTask) doCreateCSIDriver(ctx context.Context, args *Args, versio
 	}
 }
 
+func (t csiDriverTask) updateStorageCapacity(ctx context.Context, csiDriver *storagev1.CSIDriver) error {
+	existingCSIDriver, err := t.client.Kube().StorageV1().CSIDrivers().Get(ctx, csiDriver.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	isEnabled := func(value *bool) bool { return value != nil && *value }
+	if isEnabled(existingCSIDriver.Spec.StorageCapacity) == isEnabled(csiDriver.Spec.StorageCapacity) {
+		return nil
+	}
+
+	existingCSIDriver.Spec.StorageCapacity = csiDriver.Spec.StorageCapacity
+	if _, err = t.client.Kube().StorageV1().CSIDrivers().Update(ctx, existingCSIDriver, metav1.UpdateOptions{}); err != nil {
+		return fmt.Errorf("unable to update storageCapacity of CSIDriver %v; uninstall and install again; %w", csiDriver.Name, err)
+	}
+	return nil
+}
+
 func (t csiDriverTask) createCSIDriver(ctx context.Context, args *Args) (err error) {
 	version := "v1"
 	if args.DryRun {
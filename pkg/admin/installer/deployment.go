@@ -92,6 +92,11 @@ func (t deploymentTask) doCreateDeployment(ctx context.Context, args *Args, lega
 		}...,
 	)
 
+	provisionerFeatureGates := "--feature-gates=Topology=true"
+	if args.EnableVAC && !legacy {
+		provisionerFeatureGates += ",VolumeAttributesClass=true"
+	}
+
 	privileged := true
 	podSpec := corev1.PodSpec{
 		ServiceAccountName: consts.Identity,
@@ -111,8 +116,9 @@ func (t deploymentTask) doCreateDeployment(ctx context.Context, args *Args, lega
 					"--timeout=300s",
 					fmt.Sprintf("--csi-address=$(%s)", csiEndpointEnvVarName),
 					"--leader-election",
-					"--feature-gates=Topology=true",
+					provisionerFeatureGates,
 					"--strict-topology",
+					"--extra-create-metadata",
 				},
 				Env: []corev1.EnvVar{csiEndpointEnvVar},
 				VolumeMounts: []corev1.VolumeMount{
@@ -179,6 +185,63 @@ func (t deploymentTask) doCreateDeployment(ctx context.Context, args *Args, lega
 		},
 	}
 
+	if args.storageCapacity && !legacy {
+		podSpec.Containers[0].Args = append(
+			podSpec.Containers[0].Args,
+			"--enable-capacity",
+			"--capacity-ownerref-level=2",
+		)
+		podSpec.Containers[0].Env = append(podSpec.Containers[0].Env, namespaceEnvVar, podNameEnvVar)
+	}
+
+	if args.EnableVAC && !legacy {
+		podSpec.Containers[1].Args = append(podSpec.Containers[1].Args, "--feature-gates=VolumeAttributesClass=true")
+	}
+
+	if !legacy {
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{
+			Name:  "csi-external-health-monitor-controller",
+			Image: args.getCSIHealthMonitorImage(),
+			Args: []string{
+				fmt.Sprintf("--v=%d", logLevel),
+				"--timeout=300s",
+				fmt.Sprintf("--csi-address=$(%s)", csiEndpointEnvVarName),
+				"--leader-election",
+			},
+			Env: []corev1.EnvVar{csiEndpointEnvVar},
+			VolumeMounts: []corev1.VolumeMount{
+				k8s.NewVolumeMount(csiDirVolumeName, csiDirVolumePath, corev1.MountPropagationNone, false),
+			},
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			TerminationMessagePath:   "/var/log/controller-csi-external-health-monitor-termination-log",
+			SecurityContext: &corev1.SecurityContext{
+				Privileged: &privileged,
+			},
+		})
+	}
+
+	if args.EnableSnapshot && !legacy {
+		podSpec.Containers = append(podSpec.Containers, corev1.Container{
+			Name:  "csi-snapshotter",
+			Image: args.getCSISnapshotterImage(),
+			Args: []string{
+				fmt.Sprintf("--v=%d", logLevel),
+				"--timeout=300s",
+				fmt.Sprintf("--csi-address=$(%s)", csiEndpointEnvVarName),
+				"--leader-election",
+			},
+			Env: []corev1.EnvVar{csiEndpointEnvVar},
+			VolumeMounts: []corev1.VolumeMount{
+				k8s.NewVolumeMount(csiDirVolumeName, csiDirVolumePath, corev1.MountPropagationNone, false),
+			},
+			TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+			TerminationMessagePath:   "/var/log/controller-csi-snapshotter-termination-log",
+			SecurityContext: &corev1.SecurityContext{
+				Privileged: &privileged,
+			},
+		})
+	}
+
 	var selectorValue string
 	if !args.DryRun {
 		deployment, err := t.client.Kube().AppsV1().Deployments(namespace).Get(
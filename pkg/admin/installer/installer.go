@@ -57,6 +57,7 @@ func Install(ctx context.Context, args *Args, tasks []Task) (err error) {
 			args.csiProvisionerImage = csiProvisionerImageV2_2_0
 		}
 		args.podSecurityAdmission = args.KubeVersion.Minor() > 24
+		args.storageCapacity = args.KubeVersion.Minor() >= 24
 	}
 
 	if args.KubeVersion.Major() != 1 ||
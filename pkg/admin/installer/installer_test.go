@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/consts"
 	"github.com/minio/directpv/pkg/k8s"
 	legacyclient "github.com/minio/directpv/pkg/legacy/client"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -140,3 +141,47 @@ func TestInstallUinstall(t *testing.T) {
 		}
 	}
 }
+
+func TestUpgradeStorageCapacity(t *testing.T) {
+	client := client.GetClient()
+	client.K8sClient.DiscoveryClient = k8s.NewFakeDiscovery(getDiscoveryGroupsAndMethods, &version.Info{Major: "1", Minor: "26"})
+	ctx := context.TODO()
+
+	tasks := []Task{rbacTask{client}, csiDriverTask{client}}
+	for _, storageCapacity := range []bool{false, true} {
+		args := &Args{image: "directpv-0.0.0dev0", ObjectWriter: io.Discard, storageCapacity: storageCapacity}
+		for _, task := range tasks {
+			if err := task.Execute(ctx, args); err != nil {
+				t.Fatalf("storageCapacity %v: unexpected error; %v", storageCapacity, err)
+			}
+		}
+	}
+
+	csiDriver, err := client.Kube().StorageV1().CSIDrivers().Get(ctx, consts.Identity, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if csiDriver.Spec.StorageCapacity == nil || !*csiDriver.Spec.StorageCapacity {
+		t.Fatalf("expected storageCapacity enabled in CSIDriver")
+	}
+
+	clusterRole, err := client.Kube().RbacV1().ClusterRoles().Get(ctx, consts.Identity, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, rule := range clusterRole.Rules {
+		for _, resource := range rule.Resources {
+			found = found || resource == "csistoragecapacities"
+		}
+	}
+	if !found {
+		t.Fatalf("expected csistoragecapacities rule in ClusterRole")
+	}
+
+	for _, task := range tasks {
+		if err := task.Delete(ctx, &Args{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
@@ -18,6 +18,7 @@ package installer
 
 import (
 	"context"
+	"reflect"
 
 	"github.com/minio/directpv/pkg/client"
 	"github.com/minio/directpv/pkg/consts"
@@ -159,7 +160,7 @@ func (t rbacTask) createClusterRole(ctx context.Context, args *Args) (err error)
 				createVerb, deleteVerb, getVerb, listVerb, patchVerb, updateVerb, watchVerb,
 			),
 			newPolicyRule(
-				[]string{consts.DriveResource, consts.VolumeResource, consts.NodeResource, consts.InitRequestResource},
+				[]string{consts.DriveResource, consts.VolumeResource, consts.NodeResource, consts.InitRequestResource, consts.SnapshotResource},
 				[]string{consts.GroupName},
 				createVerb, deleteVerb, getVerb, listVerb, updateVerb, watchVerb,
 			),
@@ -169,11 +170,45 @@ func (t rbacTask) createClusterRole(ctx context.Context, args *Args) (err error)
 		AggregationRule: nil,
 	}
 
+	if args.storageCapacity {
+		clusterRole.Rules = append(
+			clusterRole.Rules,
+			newPolicyRule(
+				[]string{"csistoragecapacities"},
+				[]string{"storage.k8s.io"},
+				createVerb, deleteVerb, getVerb, listVerb, patchVerb, updateVerb, watchVerb,
+			),
+			newPolicyRule([]string{"replicasets", "deployments"}, []string{"apps"}, getVerb),
+		)
+	}
+
+	if args.EnableVAC {
+		clusterRole.Rules = append(
+			clusterRole.Rules,
+			newPolicyRule([]string{"volumeattributesclasses"}, []string{"storage.k8s.io"}, getVerb, listVerb, watchVerb),
+		)
+	}
+
+	if args.EnableSnapshot {
+		clusterRole.Rules = append(
+			clusterRole.Rules,
+			newPolicyRule([]string{"volumesnapshotclasses"}, []string{"snapshot.storage.k8s.io"}, getVerb, listVerb, watchVerb),
+			newPolicyRule([]string{"volumesnapshotcontents"}, []string{"snapshot.storage.k8s.io"}, getVerb, listVerb, patchVerb, updateVerb, watchVerb),
+			newPolicyRule([]string{"volumesnapshotcontents/status"}, []string{"snapshot.storage.k8s.io"}, patchVerb, updateVerb),
+		)
+	}
+
 	if !args.DryRun && !args.Declarative {
 		_, err = t.client.Kube().RbacV1().ClusterRoles().Create(
 			ctx, clusterRole, metav1.CreateOptions{},
 		)
-		if err != nil && !apierrors.IsAlreadyExists(err) {
+		switch {
+		case apierrors.IsAlreadyExists(err):
+			// Rules are updated on upgrade for newly enabled features like storage capacity.
+			if err = t.updateClusterRoleRules(ctx, clusterRole); err != nil {
+				return err
+			}
+		case err != nil:
 			return err
 		}
 	}
@@ -181,6 +216,21 @@ func (t rbacTask) createClusterRole(ctx context.Context, args *Args) (err error)
 	return args.writeObject(clusterRole)
 }
 
+func (t rbacTask) updateClusterRoleRules(ctx context.Context, clusterRole *rbacv1.ClusterRole) error {
+	existingClusterRole, err := t.client.Kube().RbacV1().ClusterRoles().Get(ctx, clusterRole.Name, metav1.GetOptions{})
+	if err != nil {
+		return err
+	}
+
+	if reflect.DeepEqual(existingClusterRole.Rules, clusterRole.Rules) {
+		return nil
+	}
+
+	existingClusterRole.Rules = clusterRole.Rules
+	_, err = t.client.Kube().RbacV1().ClusterRoles().Update(ctx, existingClusterRole, metav1.UpdateOptions{})
+	return err
+}
+
 func (t rbacTask) createClusterRoleBinding(ctx context.Context, args *Args) (err error) {
 	if !sendProgressMessage(ctx, args.ProgressCh, "Creating cluster role binding", 3, nil) {
 		return errSendProgress
@@ -47,6 +47,26 @@ var (
 		},
 	}
 
+	namespaceEnvVar = corev1.EnvVar{
+		Name: namespaceEnvVarName,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				APIVersion: "v1",
+				FieldPath:  "metadata.namespace",
+			},
+		},
+	}
+
+	podNameEnvVar = corev1.EnvVar{
+		Name: podNameEnvVarName,
+		ValueFrom: &corev1.EnvVarSource{
+			FieldRef: &corev1.ObjectFieldSelector{
+				APIVersion: "v1",
+				FieldPath:  "metadata.name",
+			},
+		},
+	}
+
 	csiEndpointEnvVar = corev1.EnvVar{
 		Name:  csiEndpointEnvVarName,
 		Value: UnixCSIEndpoint,
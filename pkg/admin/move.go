@@ -33,7 +33,9 @@ type MoveArgs struct {
 	Destination directpvtypes.DriveID
 }
 
-// Move - moves the volume references from source to destination
+// Move - moves the volumes from source to destination. The node server copies
+// the volume data to the destination drive and releases the source drive
+// after verifying the copy.
 func (client *Client) Move(ctx context.Context, args MoveArgs, log LogFunc) error {
 	if log == nil {
 		log = nullLogger
@@ -52,54 +54,17 @@ func (client *Client) Move(ctx context.Context, args MoveArgs, log LogFunc) erro
 		return errors.New("source drive is not cordoned")
 	}
 
-	sourceVolumeNames := srcDrive.GetVolumes()
-	if len(sourceVolumeNames) == 0 {
-		return fmt.Errorf("no volumes found in source drive %v", args.Source)
-	}
-
-	var requiredCapacity int64
-	var volumes []types.Volume
-	for result := range client.NewVolumeLister().VolumeNameSelector(sourceVolumeNames).List(ctx) {
-		if result.Err != nil {
-			return result.Err
-		}
-		if result.Volume.IsPublished() {
-			return fmt.Errorf("cannot move published volume %v", result.Volume.Name)
-		}
-		requiredCapacity += result.Volume.Status.TotalCapacity
-		volumes = append(volumes, result.Volume)
-	}
-
-	if len(volumes) == 0 {
-		return fmt.Errorf("no volumes found in source drive %v", args.Source)
-	}
-
 	destDrive, err := client.Drive().Get(ctx, string(args.Destination), metav1.GetOptions{})
 	if err != nil {
 		return fmt.Errorf("unable to get destination drive %v; %v", args.Destination, err)
 	}
-	if destDrive.GetNodeID() != srcDrive.GetNodeID() {
-		return fmt.Errorf("source and destination drives must be in same node; source node %v; desination node %v",
-			srcDrive.GetNodeID(),
-			destDrive.GetNodeID())
-	}
 	if !destDrive.IsUnschedulable() {
 		return errors.New("destination drive is not cordoned")
 	}
-	if destDrive.Status.Status != directpvtypes.DriveStatusReady {
-		return errors.New("destination drive is not in ready state")
-	}
-
-	if srcDrive.GetAccessTier() != destDrive.GetAccessTier() {
-		return fmt.Errorf("source drive access-tier %v and destination drive access-tier %v differ",
-			srcDrive.GetAccessTier(),
-			destDrive.GetAccessTier())
-	}
 
-	if destDrive.Status.FreeCapacity < requiredCapacity {
-		return fmt.Errorf("insufficient free capacity on destination drive; required=%v free=%v",
-			humanize.IBytes(uint64(requiredCapacity)),
-			humanize.IBytes(uint64(destDrive.Status.FreeCapacity)))
+	volumes, err := client.getMoveVolumes(ctx, srcDrive, destDrive)
+	if err != nil {
+		return err
 	}
 
 	for _, volume := range volumes {
@@ -127,12 +92,54 @@ func (client *Client) Move(ctx context.Context, args MoveArgs, log LogFunc) erro
 		)
 	}
 
-	srcDrive.ResetFinalizers()
-	_, err = client.Drive().Update(
-		ctx, srcDrive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()},
-	)
-	if err != nil {
-		return fmt.Errorf("unable to remove volume references in source drive; %v", err)
-	}
 	return nil
 }
+
+// getMoveVolumes returns the volumes of the source drive after validating
+// they could be moved to the destination drive.
+func (client *Client) getMoveVolumes(ctx context.Context, srcDrive, destDrive *types.Drive) ([]types.Volume, error) {
+	sourceVolumeNames := srcDrive.GetVolumes()
+	if len(sourceVolumeNames) == 0 {
+		return nil, fmt.Errorf("no volumes found in source drive %v", srcDrive.GetDriveID())
+	}
+
+	var requiredCapacity int64
+	var volumes []types.Volume
+	for result := range client.NewVolumeLister().VolumeNameSelector(sourceVolumeNames).List(ctx) {
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		if result.Volume.IsPublished() {
+			return nil, fmt.Errorf("cannot move published volume %v", result.Volume.Name)
+		}
+		requiredCapacity += result.Volume.Status.TotalCapacity
+		volumes = append(volumes, result.Volume)
+	}
+
+	if len(volumes) == 0 {
+		return nil, fmt.Errorf("no volumes found in source drive %v", srcDrive.GetDriveID())
+	}
+
+	if destDrive.GetNodeID() != srcDrive.GetNodeID() {
+		return nil, fmt.Errorf("source and destination drives must be in same node; source node %v; desination node %v",
+			srcDrive.GetNodeID(),
+			destDrive.GetNodeID())
+	}
+	if destDrive.Status.Status != directpvtypes.DriveStatusReady {
+		return nil, errors.New("destination drive is not in ready state")
+	}
+
+	if srcDrive.GetAccessTier() != destDrive.GetAccessTier() {
+		return nil, fmt.Errorf("source drive access-tier %v and destination drive access-tier %v differ",
+			srcDrive.GetAccessTier(),
+			destDrive.GetAccessTier())
+	}
+
+	if destDrive.Status.FreeCapacity < requiredCapacity {
+		return nil, fmt.Errorf("insufficient free capacity on destination drive; required=%v free=%v",
+			humanize.IBytes(uint64(requiredCapacity)),
+			humanize.IBytes(uint64(destDrive.Status.FreeCapacity)))
+	}
+
+	return volumes, nil
+}
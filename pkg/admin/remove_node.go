@@ -0,0 +1,361 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ErrNodeInUse denotes that DirectPV node server is still running on the node
+var ErrNodeInUse = errors.New("node is still in use")
+
+// RemoveNodeArgs represents the args to remove a node
+type RemoveNodeArgs struct {
+	Node       directpvtypes.NodeID
+	BackupFile string
+	Force      bool
+	DryRun     bool
+}
+
+// RemoveNodeResult represents the resources affected by removing a node
+type RemoveNodeResult struct {
+	Volumes      []string
+	Snapshots    []string
+	Drives       []string
+	InitRequests []string
+	PVs          []string
+	PVCs         []string
+	Pods         []string
+}
+
+// isNodeServerRunning checks whether DirectPV node server is running on the
+// node or the node is registered in CSINode.
+func (client *Client) isNodeServerRunning(ctx context.Context, node directpvtypes.NodeID) (bool, error) {
+	daemonSet, err := client.Kube().AppsV1().DaemonSets(consts.AppName).Get(
+		ctx, consts.NodeServerName, metav1.GetOptions{},
+	)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return false, err
+	}
+
+	if err == nil && daemonSet.Spec.Selector != nil {
+		podList, err := client.Kube().CoreV1().Pods(consts.AppName).List(ctx, metav1.ListOptions{
+			LabelSelector: metav1.FormatLabelSelector(daemonSet.Spec.Selector),
+			FieldSelector: "spec.nodeName=" + string(node),
+		})
+		if err != nil {
+			return false, err
+		}
+		for _, pod := range podList.Items {
+			// Field selector is not honored by every client; hence check node name again.
+			if pod.Spec.NodeName != string(node) {
+				continue
+			}
+			if pod.Status.Phase != corev1.PodSucceeded && pod.Status.Phase != corev1.PodFailed {
+				return true, nil
+			}
+		}
+	}
+
+	csiNode, err := client.Kube().StorageV1().CSINodes().Get(ctx, string(node), metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			err = nil
+		}
+		return false, err
+	}
+	for _, driver := range csiNode.Spec.Drivers {
+		if driver.Name == consts.Identity {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+// writeNodeBackup writes the objects in YAML list to the backup file.
+func writeNodeBackup(backupFile string, objects []runtime.Object) error {
+	list := metav1.List{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "List",
+			APIVersion: "v1",
+		},
+	}
+	for _, object := range objects {
+		data, err := json.Marshal(object)
+		if err != nil {
+			return err
+		}
+		list.Items = append(list.Items, runtime.RawExtension{Raw: data})
+	}
+
+	data, err := utils.ToYAML(list)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(backupFile, data, 0o600)
+}
+
+// RemoveNode forcefully removes the volumes, snapshots, drives, init requests and node of
+// DirectPV from the node. The node must not run DirectPV node server unless
+// forced. Removed objects are written to the backup file.
+func (client *Client) RemoveNode(ctx context.Context, args RemoveNodeArgs, log LogFunc) (result *RemoveNodeResult, err error) {
+	if log == nil {
+		log = nullLogger
+	}
+
+	if args.Node == "" {
+		return nil, errors.New("node must be provided")
+	}
+
+	if !args.DryRun && args.BackupFile == "" {
+		return nil, errors.New("backup file should not be empty")
+	}
+
+	nodeSelector := []directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(args.Node))}
+
+	volumes, err := client.NewVolumeLister().NodeSelector(nodeSelector).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get volumes; %w", err)
+	}
+
+	snapshots, err := client.NewSnapshotLister().NodeSelector(nodeSelector).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get snapshots; %w", err)
+	}
+
+	drives, err := client.NewDriveLister().NodeSelector(nodeSelector).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get drives; %w", err)
+	}
+
+	initRequests, err := client.NewInitRequestLister().NodeSelector(nodeSelector).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get init requests; %w", err)
+	}
+
+	node, err := client.Node().Get(ctx, string(args.Node), metav1.GetOptions{})
+	switch {
+	case err == nil:
+	case apierrors.IsNotFound(err):
+		node = nil
+	default:
+		return nil, fmt.Errorf("unable to get node %v; %w", args.Node, err)
+	}
+
+	if len(volumes) == 0 && len(snapshots) == 0 && len(drives) == 0 && len(initRequests) == 0 && node == nil {
+		return nil, ErrNoMatchingResourcesFound
+	}
+
+	result = &RemoveNodeResult{}
+	for _, volume := range volumes {
+		result.Volumes = append(result.Volumes, volume.Name)
+
+		values := map[string]any{"volume": volume.Name}
+		formattedMessage := fmt.Sprintf("Volume %v", volume.Name)
+
+		pv, err := client.Kube().CoreV1().PersistentVolumes().Get(ctx, volume.Name, metav1.GetOptions{})
+		switch {
+		case err == nil:
+			result.PVs = append(result.PVs, pv.Name)
+			values["pv"] = pv.Name
+			formattedMessage += "; PV " + pv.Name
+			if claimRef := pv.Spec.ClaimRef; claimRef != nil {
+				pvc := claimRef.Namespace + "/" + claimRef.Name
+				result.PVCs = append(result.PVCs, pvc)
+				values["pvc"] = pvc
+				formattedMessage += "; PVC " + pvc
+			}
+		case !apierrors.IsNotFound(err):
+			return nil, fmt.Errorf("unable to get persistent volume %v; %w", volume.Name, err)
+		}
+
+		if podName, podNS := volume.GetPodName(), volume.GetPodNS(); podName != "" {
+			pod := podNS + "/" + podName
+			result.Pods = append(result.Pods, pod)
+			values["pod"] = pod
+			formattedMessage += "; pod " + pod
+		}
+
+		log(
+			LogMessage{
+				Type:             InfoLogType,
+				Message:          "affected volume",
+				Values:           values,
+				FormattedMessage: formattedMessage + "\n",
+			},
+		)
+	}
+
+	for _, snapshot := range snapshots {
+		result.Snapshots = append(result.Snapshots, snapshot.Name)
+		log(
+			LogMessage{
+				Type:             InfoLogType,
+				Message:          "affected snapshot",
+				Values:           map[string]any{"snapshot": snapshot.Name, "sourceVolume": snapshot.GetSourceVolume()},
+				FormattedMessage: fmt.Sprintf("Snapshot %v of volume %v\n", snapshot.Name, snapshot.GetSourceVolume()),
+			},
+		)
+	}
+
+	for _, drive := range drives {
+		result.Drives = append(result.Drives, string(drive.GetDriveID()))
+		log(
+			LogMessage{
+				Type:             InfoLogType,
+				Message:          "affected drive",
+				Values:           map[string]any{"node": drive.GetNodeID(), "driveName": drive.GetDriveName(), "driveID": drive.GetDriveID()},
+				FormattedMessage: fmt.Sprintf("Drive %v/%v (%v)\n", drive.GetNodeID(), drive.GetDriveName(), drive.GetDriveID()),
+			},
+		)
+	}
+
+	for _, initRequest := range initRequests {
+		result.InitRequests = append(result.InitRequests, initRequest.Name)
+	}
+
+	running, err := client.isNodeServerRunning(ctx, args.Node)
+	if err != nil {
+		return nil, fmt.Errorf("unable to check node server on node %v; %w", args.Node, err)
+	}
+	if running {
+		if !args.Force {
+			return result, fmt.Errorf(
+				"%w; remove node %v from %v DaemonSet and try again",
+				ErrNodeInUse, args.Node, consts.AppPrettyName,
+			)
+		}
+		log(
+			LogMessage{
+				Type:             InfoLogType,
+				Message:          "forcefully removing node in use",
+				Values:           map[string]any{"node": args.Node},
+				FormattedMessage: fmt.Sprintf("Node %v is still in use; forcefully removing\n", args.Node),
+			},
+		)
+	}
+
+	if args.DryRun {
+		return result, nil
+	}
+
+	var objects []runtime.Object
+	for i := range volumes {
+		volumes[i].TypeMeta = types.NewVolumeTypeMeta()
+		objects = append(objects, &volumes[i])
+	}
+	for i := range snapshots {
+		snapshots[i].TypeMeta = types.NewSnapshotTypeMeta()
+		objects = append(objects, &snapshots[i])
+	}
+	for i := range drives {
+		drives[i].TypeMeta = types.NewDriveTypeMeta()
+		objects = append(objects, &drives[i])
+	}
+	for i := range initRequests {
+		initRequests[i].TypeMeta = types.NewInitRequestTypeMeta()
+		objects = append(objects, &initRequests[i])
+	}
+	if node != nil {
+		node.TypeMeta = types.NewNodeTypeMeta()
+		objects = append(objects, node)
+	}
+	if err = writeNodeBackup(args.BackupFile, objects); err != nil {
+		return nil, fmt.Errorf("unable to write backup file %v; %w", args.BackupFile, err)
+	}
+	log(
+		LogMessage{
+			Type:             InfoLogType,
+			Message:          "objects backed up",
+			Values:           map[string]any{"backupFile": args.BackupFile},
+			FormattedMessage: fmt.Sprintf("Removed objects backed up to %v\n", args.BackupFile),
+		},
+	)
+
+	for i := range volumes {
+		volumes[i].Finalizers = []string{}
+		if _, err = client.Volume().Update(ctx, &volumes[i], metav1.UpdateOptions{TypeMeta: types.NewVolumeTypeMeta()}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to update volume %v; %w", volumes[i].Name, err)
+		}
+		if err = client.Volume().Delete(ctx, volumes[i].Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to remove volume %v; %w", volumes[i].Name, err)
+		}
+	}
+
+	for i := range snapshots {
+		snapshots[i].Finalizers = []string{}
+		if _, err = client.Snapshot().Update(ctx, &snapshots[i], metav1.UpdateOptions{TypeMeta: types.NewSnapshotTypeMeta()}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to update snapshot %v; %w", snapshots[i].Name, err)
+		}
+		if err = client.Snapshot().Delete(ctx, snapshots[i].Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to remove snapshot %v; %w", snapshots[i].Name, err)
+		}
+	}
+
+	for i := range drives {
+		drives[i].Finalizers = []string{}
+		if _, err = client.Drive().Update(ctx, &drives[i], metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to update drive %v; %w", drives[i].Name, err)
+		}
+		if err = client.Drive().Delete(ctx, drives[i].Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to remove drive %v; %w", drives[i].Name, err)
+		}
+	}
+
+	for i := range initRequests {
+		initRequests[i].Finalizers = []string{}
+		if _, err = client.InitRequest().Update(ctx, &initRequests[i], metav1.UpdateOptions{TypeMeta: types.NewInitRequestTypeMeta()}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to update init request %v; %w", initRequests[i].Name, err)
+		}
+		if err = client.InitRequest().Delete(ctx, initRequests[i].Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to remove init request %v; %w", initRequests[i].Name, err)
+		}
+	}
+
+	if node != nil {
+		if err = client.Node().Delete(ctx, node.Name, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("unable to remove node %v; %w", node.Name, err)
+		}
+	}
+
+	log(
+		LogMessage{
+			Type:    InfoLogType,
+			Message: "node removed",
+			Values:  map[string]any{"node": args.Node, "volumes": len(volumes), "snapshots": len(snapshots), "drives": len(drives)},
+			FormattedMessage: fmt.Sprintf("Removed %v volume(s), %v snapshot(s), %v drive(s) and %v init request(s) of node %v\n",
+				len(volumes), len(snapshots), len(drives), len(initRequests), args.Node),
+		},
+	)
+
+	return result, nil
+}
@@ -0,0 +1,337 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package admin
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dustin/go-humanize"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+)
+
+// moveProgressInterval is the interval to check progress of moving volumes.
+const moveProgressInterval = 5 * time.Second
+
+// ReplaceArgs represents the args to replace a drive
+type ReplaceArgs struct {
+	// Source and Destination are either drive IDs or drive names. Node
+	// must be set if a drive name is used.
+	Source      string
+	Destination string
+	Node        directpvtypes.NodeID
+	DryRun      bool
+}
+
+// ReplaceResult represents the replaced drive
+type ReplaceResult struct {
+	NodeID      directpvtypes.NodeID
+	Source      directpvtypes.DriveID
+	Destination directpvtypes.DriveID
+	Volumes     []string
+}
+
+// getDrive returns the drive by drive ID or by drive name on the node.
+func (client *Client) getDrive(ctx context.Context, drive string, node directpvtypes.NodeID) (*types.Drive, error) {
+	if utils.IsUUID(drive) {
+		return client.Drive().Get(ctx, drive, metav1.GetOptions{})
+	}
+
+	if node == "" {
+		return nil, fmt.Errorf("node must be provided for drive name %v", drive)
+	}
+
+	drives, err := client.NewDriveLister().
+		NodeSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(node))}).
+		DriveNameSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(strings.TrimPrefix(drive, "/dev/"))}).
+		Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(drives) {
+	case 0:
+		return nil, fmt.Errorf("drive %v on node %v not found", drive, node)
+	case 1:
+		return &drives[0], nil
+	default:
+		return nil, fmt.Errorf("duplicate drive IDs found for drive %v on node %v", drive, node)
+	}
+}
+
+// Replace moves the volumes from source drive to destination drive on a same
+// node and removes the source drive. It waits until all volumes are moved;
+// the wait is stopped by canceling ctx.
+func (client *Client) Replace(ctx context.Context, args ReplaceArgs, log LogFunc) (result *ReplaceResult, err error) {
+	if log == nil {
+		log = nullLogger
+	}
+
+	srcDrive, err := client.getDrive(ctx, args.Source, args.Node)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get source drive %v; %w", args.Source, err)
+	}
+
+	destDrive, err := client.getDrive(ctx, args.Destination, args.Node)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get destination drive %v; %w", args.Destination, err)
+	}
+
+	if srcDrive.GetDriveID() == destDrive.GetDriveID() {
+		return nil, errors.New("source and destination drives are same")
+	}
+
+	if srcDrive.GetNodeID() != destDrive.GetNodeID() {
+		return nil, fmt.Errorf("source and destination drives must be in same node; source node %v; desination node %v",
+			srcDrive.GetNodeID(),
+			destDrive.GetNodeID())
+	}
+
+	if srcDrive.GetAccessTier() != destDrive.GetAccessTier() {
+		return nil, fmt.Errorf("source drive access-tier %v and destination drive access-tier %v differ",
+			srcDrive.GetAccessTier(),
+			destDrive.GetAccessTier())
+	}
+
+	var volumes []types.Volume
+	if srcDrive.GetVolumeCount() > 0 {
+		if volumes, err = client.getMoveVolumes(ctx, srcDrive, destDrive); err != nil {
+			return nil, err
+		}
+	}
+
+	result = &ReplaceResult{
+		NodeID:      srcDrive.GetNodeID(),
+		Source:      srcDrive.GetDriveID(),
+		Destination: destDrive.GetDriveID(),
+	}
+	for _, volume := range volumes {
+		result.Volumes = append(result.Volumes, volume.Name)
+	}
+
+	log(
+		LogMessage{
+			Type:    InfoLogType,
+			Message: "replacing drive",
+			Values: map[string]any{
+				"node":             srcDrive.GetNodeID(),
+				"sourceDrive":      srcDrive.GetDriveName(),
+				"destinationDrive": destDrive.GetDriveName(),
+				"volumeCount":      len(volumes),
+			},
+			FormattedMessage: fmt.Sprintf("Replacing drive %v/%v (%v) by %v/%v (%v) having %v volume(s)\n",
+				srcDrive.GetNodeID(), srcDrive.GetDriveName(), srcDrive.GetDriveID(),
+				destDrive.GetNodeID(), destDrive.GetDriveName(), destDrive.GetDriveID(),
+				len(volumes)),
+		},
+	)
+
+	if args.DryRun {
+		for _, volume := range volumes {
+			log(
+				LogMessage{
+					Type:             InfoLogType,
+					Message:          "moving volume",
+					Values:           map[string]any{"volume": volume.Name},
+					FormattedMessage: fmt.Sprintf("Moving volume %v\n", volume.Name),
+				},
+			)
+		}
+		log(
+			LogMessage{
+				Type:             InfoLogType,
+				Message:          "removing drive",
+				Values:           map[string]any{"node": srcDrive.GetNodeID(), "driveName": srcDrive.GetDriveName()},
+				FormattedMessage: fmt.Sprintf("Removing %v/%v\n", srcDrive.GetNodeID(), srcDrive.GetDriveName()),
+			},
+		)
+		return result, nil
+	}
+
+	if len(volumes) > 0 {
+		// Volumes are moved only between cordoned drives. The destination
+		// drive is made schedulable again if it was not cordoned before.
+		destCordoned := destDrive.IsUnschedulable()
+		driveIDs := []directpvtypes.DriveID{srcDrive.GetDriveID(), destDrive.GetDriveID()}
+		if _, err = client.Cordon(ctx, CordonArgs{DriveIDs: driveIDs}, log); err != nil {
+			return nil, err
+		}
+
+		if err = client.Move(ctx, MoveArgs{Source: srcDrive.GetDriveID(), Destination: destDrive.GetDriveID()}, log); err != nil {
+			return nil, err
+		}
+
+		if err = client.waitForMove(ctx, srcDrive.GetDriveID(), destDrive.GetDriveID(), result.Volumes, log); err != nil {
+			logRemoveDrive(srcDrive, destDrive, destCordoned, log)
+			return nil, fmt.Errorf("unable to wait for volumes to be moved; %w", err)
+		}
+
+		if !destCordoned {
+			// Node server updates the destination drive while moving volumes.
+			updateFunc := func() error {
+				drive, err := client.Drive().Get(ctx, string(destDrive.GetDriveID()), metav1.GetOptions{})
+				if err != nil {
+					return err
+				}
+				drive.Schedulable()
+				_, err = client.Drive().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+				return err
+			}
+			if err = retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+				return nil, fmt.Errorf("unable to uncordon drive %v; %w", destDrive.GetDriveID(), err)
+			}
+			log(
+				LogMessage{
+					Type:             InfoLogType,
+					Message:          "drive uncordoned",
+					Values:           map[string]any{"node": destDrive.GetNodeID(), "driveName": destDrive.GetDriveName()},
+					FormattedMessage: fmt.Sprintf("Drive %v/%v uncordoned\n", destDrive.GetNodeID(), destDrive.GetDriveName()),
+				},
+			)
+		}
+	}
+
+	updateFunc := func() error {
+		drive, err := client.Drive().Get(ctx, string(srcDrive.GetDriveID()), metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		drive.Status.Status = directpvtypes.DriveStatusRemoved
+		_, err = client.Drive().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+	if err = retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		return nil, fmt.Errorf("unable to remove drive %v; %w", srcDrive.GetDriveID(), err)
+	}
+	log(
+		LogMessage{
+			Type:             InfoLogType,
+			Message:          "removing drive",
+			Values:           map[string]any{"node": srcDrive.GetNodeID(), "driveName": srcDrive.GetDriveName()},
+			FormattedMessage: fmt.Sprintf("Removing %v/%v\n", srcDrive.GetNodeID(), srcDrive.GetDriveName()),
+		},
+	)
+
+	return result, nil
+}
+
+// waitForMove waits until the volumes are moved to the destination drive and
+// the source drive is released. Move progress is logged periodically.
+func (client *Client) waitForMove(ctx context.Context, srcDriveID, destDriveID directpvtypes.DriveID, volumeNames []string, log LogFunc) error {
+	var lastMessage string
+	moveErrors := map[string]string{}
+	for {
+		volumes, err := client.NewVolumeLister().VolumeNameSelector(volumeNames).IgnoreNotFound(true).Get(ctx)
+		if err != nil {
+			return err
+		}
+
+		var movedCount int
+		var copiedBytes, totalBytes int64
+		for _, volume := range volumes {
+			move := volume.Status.Move
+			if volume.GetDriveID() != destDriveID || move == nil {
+				// Volume is yet to be picked up by the node server.
+				continue
+			}
+
+			copiedBytes += move.CopiedBytes
+			totalBytes += move.TotalBytes
+			switch move.State {
+			case directpvtypes.MoveStateCompleted:
+				movedCount++
+			case directpvtypes.MoveStateFailed:
+				if moveErrors[volume.Name] == move.Error {
+					break
+				}
+				moveErrors[volume.Name] = move.Error
+				log(
+					LogMessage{
+						Type:             ErrorLogType,
+						Err:              errors.New(move.Error),
+						Message:          "unable to move volume; retrying",
+						Values:           map[string]any{"volume": volume.Name},
+						FormattedMessage: fmt.Sprintf("Unable to move volume %v; %v; retrying\n", volume.Name, move.Error),
+					},
+				)
+			}
+		}
+		movedCount += len(volumeNames) - len(volumes) // Deleted volumes need no move.
+
+		message := fmt.Sprintf("Moved %v/%v volume(s); copied %v of %v\n",
+			movedCount, len(volumeNames), humanize.IBytes(uint64(copiedBytes)), humanize.IBytes(uint64(totalBytes)))
+		if message != lastMessage {
+			lastMessage = message
+			log(
+				LogMessage{
+					Type:             InfoLogType,
+					Message:          "moving volumes",
+					Values:           map[string]any{"moved": movedCount, "total": len(volumeNames), "copiedBytes": copiedBytes, "totalBytes": totalBytes},
+					FormattedMessage: message,
+				},
+			)
+		}
+
+		if movedCount == len(volumeNames) {
+			drive, err := client.Drive().Get(ctx, string(srcDriveID), metav1.GetOptions{})
+			if err != nil {
+				return err
+			}
+			if drive.GetVolumeCount() == 0 {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(moveProgressInterval):
+		}
+	}
+}
+
+// logRemoveDrive logs how to complete the replacement when waiting for the
+// volumes to be moved is stopped.
+func logRemoveDrive(drive, destDrive *types.Drive, destCordoned bool, log LogFunc) {
+	formattedMessage := fmt.Sprintf(
+		"Volumes are being moved; once completed, remove drive %v/%v by 'kubectl %v remove --drives=%v --nodes=%v'",
+		drive.GetNodeID(), drive.GetDriveName(), consts.AppName, drive.GetDriveName(), drive.GetNodeID(),
+	)
+	if !destCordoned {
+		formattedMessage += fmt.Sprintf(
+			" and uncordon drive %v/%v by 'kubectl %v uncordon --drives=%v --nodes=%v'",
+			destDrive.GetNodeID(), destDrive.GetDriveName(), consts.AppName, destDrive.GetDriveName(), destDrive.GetNodeID(),
+		)
+	}
+	log(
+		LogMessage{
+			Type:             InfoLogType,
+			Message:          "remove drive after moving volumes",
+			Values:           map[string]any{"node": drive.GetNodeID(), "driveName": drive.GetDriveName()},
+			FormattedMessage: formattedMessage + "\n",
+		},
+	)
+}
@@ -88,27 +88,87 @@ const (
 
 	// ClaimIDLabelKey label key to denote the claim id of the volumes
 	ClaimIDLabelKey LabelKey = consts.GroupName + "/claim-id"
+
+	// ReflinkLabelKey denotes if the drive is formatted with reflink support.
+	ReflinkLabelKey LabelKey = consts.GroupName + "/reflink"
+
+	// SourceVolumeLabelKey label key for source volume of a snapshot
+	SourceVolumeLabelKey LabelKey = consts.GroupName + "/source-volume"
+
+	// CloneSpreadLabelKey denotes if a cloned volume may be placed on any drive of the source node.
+	CloneSpreadLabelKey LabelKey = consts.GroupName + "/clone-spread"
+
+	// DriveSelectionPolicyLabelKey denotes the policy to select a drive for a volume.
+	DriveSelectionPolicyLabelKey LabelKey = consts.GroupName + "/drive-selection-policy"
+
+	// BlockVolumeLabelKey denotes how a raw block volume is allocated in a drive.
+	BlockVolumeLabelKey LabelKey = consts.GroupName + "/block-volume"
+
+	// InodeLimitLabelKey denotes the maximum number of inodes of a volume.
+	InodeLimitLabelKey LabelKey = consts.GroupName + "/inode-limit"
+
+	// ReadBytesPerSecLabelKey denotes the maximum read bytes per second of a volume.
+	ReadBytesPerSecLabelKey LabelKey = consts.GroupName + "/read-bytes-per-sec"
+
+	// WriteBytesPerSecLabelKey denotes the maximum write bytes per second of a volume.
+	WriteBytesPerSecLabelKey LabelKey = consts.GroupName + "/write-bytes-per-sec"
+
+	// ReadIOPSLabelKey denotes the maximum read I/O operations per second of a volume.
+	ReadIOPSLabelKey LabelKey = consts.GroupName + "/read-iops"
+
+	// WriteIOPSLabelKey denotes the maximum write I/O operations per second of a volume.
+	WriteIOPSLabelKey LabelKey = consts.GroupName + "/write-iops"
+
+	// UIDLabelKey denotes the owner user ID of a volume root directory.
+	UIDLabelKey LabelKey = consts.GroupName + "/uid"
+
+	// GIDLabelKey denotes the owner group ID of a volume root directory.
+	GIDLabelKey LabelKey = consts.GroupName + "/gid"
+
+	// ModeLabelKey denotes the permission mode of a volume root directory.
+	ModeLabelKey LabelKey = consts.GroupName + "/mode"
+
+	// MountFlagsLabelKey denotes the comma separated extra mount flags of a volume.
+	MountFlagsLabelKey LabelKey = consts.GroupName + "/mount-flags"
+
+	// EphemeralLabelKey denotes the volume is a CSI ephemeral inline volume.
+	EphemeralLabelKey LabelKey = consts.GroupName + "/ephemeral"
 )
 
 var reservedLabelKeys = map[LabelKey]struct{}{
-	NodeLabelKey:           {},
-	DriveNameLabelKey:      {},
-	AccessTierLabelKey:     {},
-	DriveLabelKey:          {},
-	VersionLabelKey:        {},
-	CreatedByLabelKey:      {},
-	PodNameLabelKey:        {},
-	PodNSLabelKey:          {},
-	LatestVersionLabelKey:  {},
-	TopologyDriverIdentity: {},
-	TopologyDriverRack:     {},
-	TopologyDriverZone:     {},
-	TopologyDriverRegion:   {},
-	MigratedLabelKey:       {},
-	RequestIDLabelKey:      {},
-	SuspendLabelKey:        {},
-	VolumeClaimIDLabelKey:  {},
-	ClaimIDLabelKey:        {},
+	NodeLabelKey:                 {},
+	DriveNameLabelKey:            {},
+	AccessTierLabelKey:           {},
+	DriveLabelKey:                {},
+	VersionLabelKey:              {},
+	CreatedByLabelKey:            {},
+	PodNameLabelKey:              {},
+	PodNSLabelKey:                {},
+	LatestVersionLabelKey:        {},
+	TopologyDriverIdentity:       {},
+	TopologyDriverRack:           {},
+	TopologyDriverZone:           {},
+	TopologyDriverRegion:         {},
+	MigratedLabelKey:             {},
+	RequestIDLabelKey:            {},
+	SuspendLabelKey:              {},
+	VolumeClaimIDLabelKey:        {},
+	ClaimIDLabelKey:              {},
+	ReflinkLabelKey:              {},
+	SourceVolumeLabelKey:         {},
+	CloneSpreadLabelKey:          {},
+	DriveSelectionPolicyLabelKey: {},
+	BlockVolumeLabelKey:          {},
+	InodeLimitLabelKey:           {},
+	ReadBytesPerSecLabelKey:      {},
+	WriteBytesPerSecLabelKey:     {},
+	ReadIOPSLabelKey:             {},
+	WriteIOPSLabelKey:            {},
+	UIDLabelKey:                  {},
+	GIDLabelKey:                  {},
+	ModeLabelKey:                 {},
+	MountFlagsLabelKey:           {},
+	EphemeralLabelKey:            {},
 }
 
 // IsReserved returns if the key is a reserved key
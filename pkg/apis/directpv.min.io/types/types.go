@@ -51,13 +51,19 @@ const (
 
 	// DriveStatusRepairing denotes drive is repairing it's filesystem.
 	DriveStatusRepairing DriveStatus = "Repairing"
+
+	// DriveStatusDraining denotes drive is moving out its volumes to other drives.
+	DriveStatusDraining DriveStatus = "Draining"
+
+	// DriveStatusDrained denotes drive has no volumes after draining and is ready for removal.
+	DriveStatusDrained DriveStatus = "Drained"
 )
 
 // ToDriveStatus converts string value to DriveStatus.
 func ToDriveStatus(value string) (status DriveStatus, err error) {
 	status = DriveStatus(strings.Title(value))
 	switch status {
-	case DriveStatusReady, DriveStatusLost, DriveStatusError, DriveStatusRemoved, DriveStatusMoving:
+	case DriveStatusReady, DriveStatusLost, DriveStatusError, DriveStatusRemoved, DriveStatusMoving, DriveStatusDraining, DriveStatusDrained:
 		return status, nil
 	}
 
@@ -86,6 +92,69 @@ func ToVolumeStatus(value string) (status VolumeStatus, err error) {
 	return
 }
 
+// SnapshotStatus represents status of a snapshot.
+type SnapshotStatus string
+
+// Enum of SnapshotStatus type.
+const (
+	SnapshotStatusPending SnapshotStatus = "Pending"
+	SnapshotStatusReady   SnapshotStatus = "Ready"
+	SnapshotStatusError   SnapshotStatus = "Error"
+)
+
+// CloneState represents state of volume cloning.
+type CloneState string
+
+// Enum of CloneState type.
+const (
+	CloneStatePending    CloneState = "Pending"
+	CloneStateInProgress CloneState = "InProgress"
+	CloneStateCompleted  CloneState = "Completed"
+	CloneStateFailed     CloneState = "Failed"
+)
+
+// MoveState represents state of volume data move.
+type MoveState string
+
+// Enum of MoveState type.
+const (
+	MoveStateInProgress MoveState = "InProgress"
+	MoveStateVerified   MoveState = "Verified"
+	MoveStateCompleted  MoveState = "Completed"
+	MoveStateFailed     MoveState = "Failed"
+)
+
+// VolumeMode denotes how a volume is presented to the workload.
+type VolumeMode string
+
+// Enum values of VolumeMode type.
+const (
+	VolumeModeFilesystem VolumeMode = "Filesystem"
+	VolumeModeBlock      VolumeMode = "Block"
+)
+
+// BlockAllocation denotes how the block device of a block volume is allocated in the drive.
+type BlockAllocation string
+
+// Enum values of BlockAllocation type.
+const (
+	// BlockAllocationLoopFile allocates requested capacity of a drive to the
+	// volume by a preallocated file in the drive filesystem which is attached
+	// to a loop device.
+	BlockAllocationLoopFile BlockAllocation = "loop-file"
+)
+
+// ToBlockAllocation converts string value to BlockAllocation.
+func ToBlockAllocation(value string) (BlockAllocation, error) {
+	allocation := BlockAllocation(strings.ToLower(value))
+	switch allocation {
+	case BlockAllocationLoopFile:
+		return allocation, nil
+	default:
+		return "", fmt.Errorf("unknown block allocation %v", value)
+	}
+}
+
 // AccessTier denotes access tier.
 type AccessTier string
 
@@ -123,7 +192,8 @@ type VolumeConditionType string
 
 // Enum value of VolumeConditionType type.
 const (
-	VolumeConditionTypeLost VolumeConditionType = "Lost"
+	VolumeConditionTypeLost       VolumeConditionType = "Lost"
+	VolumeConditionTypeNearlyFull VolumeConditionType = "NearlyFull"
 )
 
 // VolumeConditionReason denotes volume reason. Allows maximum upto 1024 chars.
@@ -131,7 +201,10 @@ type VolumeConditionReason string
 
 // Enum values of VolumeConditionReason type.
 const (
-	VolumeConditionReasonDriveLost VolumeConditionReason = "DriveLost"
+	VolumeConditionReasonDriveLost                   VolumeConditionReason = "DriveLost"
+	VolumeConditionReasonUsageBelowThreshold         VolumeConditionReason = "UsageBelowThreshold"
+	VolumeConditionReasonUsageAboveWarningThreshold  VolumeConditionReason = "UsageAboveWarningThreshold"
+	VolumeConditionReasonUsageAboveCriticalThreshold VolumeConditionReason = "UsageAboveCriticalThreshold"
 )
 
 // VolumeConditionMessage denotes drive message. Allows maximum upto 32768 chars.
@@ -151,6 +224,7 @@ const (
 	DriveConditionTypeMultipleMatches DriveConditionType = "MultipleMatches"
 	DriveConditionTypeIOError         DriveConditionType = "IOError"
 	DriveConditionTypeRelabelError    DriveConditionType = "RelabelError"
+	DriveConditionTypeRepaired        DriveConditionType = "Repaired"
 )
 
 // DriveConditionReason denotes the reason for the drive condition type. Allows maximum upto 1024 chars.
@@ -162,6 +236,13 @@ const (
 	DriveConditionReasonMultipleMatches DriveConditionReason = "DriveHasMultipleMatches"
 	DriveConditionReasonIOError         DriveConditionReason = "DriveHasIOError"
 	DriveConditionReasonRelabelError    DriveConditionReason = "DriveHasRelabelError"
+
+	// Outcome of drive filesystem repair.
+	DriveConditionReasonRepairClean           DriveConditionReason = "Clean"
+	DriveConditionReasonRepairFixed           DriveConditionReason = "Fixed"
+	DriveConditionReasonRepairCorrupted       DriveConditionReason = "Corrupted"
+	DriveConditionReasonRepairNeedsLogZeroing DriveConditionReason = "NeedsLogZeroing"
+	DriveConditionReasonRepairUnrecoverable   DriveConditionReason = "Unrecoverable"
 )
 
 // DriveConditionMessage denotes drive message. Allows maximum upto 32768 chars
@@ -310,9 +310,41 @@ func (drive *DirectPVDrive) setErrorCondition(errType, reason, message string) {
 	}
 }
 
-// GetLatestErrorConditionType returns the latest error condition type set.
-func (drive *DirectPVDrive) GetLatestErrorConditionType() (errType types.DriveConditionType) {
-	var latestCondition *metav1.Condition
+// SetRepairedCondition sets the outcome of the latest filesystem repair to this drive.
+func (drive *DirectPVDrive) SetRepairedCondition(reason types.DriveConditionReason, message string) {
+	status := metav1.ConditionFalse
+	switch reason {
+	case types.DriveConditionReasonRepairClean, types.DriveConditionReasonRepairFixed:
+		status = metav1.ConditionTrue
+	}
+	c := metav1.Condition{
+		Type:               string(types.DriveConditionTypeRepaired),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	for i := range drive.Status.Conditions {
+		if drive.Status.Conditions[i].Type == c.Type {
+			drive.Status.Conditions[i] = c
+			return
+		}
+	}
+	drive.Status.Conditions = append(drive.Status.Conditions, c)
+}
+
+// GetRepairedCondition returns the outcome of the latest filesystem repair.
+func (drive *DirectPVDrive) GetRepairedCondition() *metav1.Condition {
+	for i := range drive.Status.Conditions {
+		if drive.Status.Conditions[i].Type == string(types.DriveConditionTypeRepaired) {
+			return &drive.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// GetLatestErrorCondition returns the latest error condition set.
+func (drive *DirectPVDrive) GetLatestErrorCondition() (latestCondition *metav1.Condition) {
 	for i := range drive.Status.Conditions {
 		switch types.DriveConditionType(drive.Status.Conditions[i].Type) {
 		case types.DriveConditionTypeMountError, types.DriveConditionTypeMultipleMatches, types.DriveConditionTypeIOError, types.DriveConditionTypeRelabelError:
@@ -322,7 +354,12 @@ func (drive *DirectPVDrive) GetLatestErrorConditionType() (errType types.DriveCo
 		}
 	}
 
-	if latestCondition != nil {
+	return
+}
+
+// GetLatestErrorConditionType returns the latest error condition type set.
+func (drive *DirectPVDrive) GetLatestErrorConditionType() (errType types.DriveConditionType) {
+	if latestCondition := drive.GetLatestErrorCondition(); latestCondition != nil {
 		errType = types.DriveConditionType(latestCondition.Type)
 	}
 
@@ -339,6 +376,16 @@ func (drive *DirectPVDrive) IsMigrated() bool {
 	return drive.getLabel(types.MigratedLabelKey) == "true"
 }
 
+// SetReflinkLabel sets whether this drive is formatted with reflink support.
+func (drive *DirectPVDrive) SetReflinkLabel(reflink bool) {
+	drive.SetLabel(types.ReflinkLabelKey, types.LabelValue(strconv.FormatBool(reflink)))
+}
+
+// IsReflinkDisabled returns whether this drive is known to be formatted without reflink support.
+func (drive DirectPVDrive) IsReflinkDisabled() bool {
+	return string(drive.getLabel(types.ReflinkLabelKey)) == strconv.FormatBool(false)
+}
+
 // IsSuspended returns if the drive is suspended.
 func (drive DirectPVDrive) IsSuspended() bool {
 	return string(drive.getLabel(types.SuspendLabelKey)) == strconv.FormatBool(true)
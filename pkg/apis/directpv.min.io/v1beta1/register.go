@@ -65,6 +65,8 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&DirectPVNodeList{},
 		&DirectPVInitRequest{},
 		&DirectPVInitRequestList{},
+		&DirectPVSnapshot{},
+		&DirectPVSnapshotList{},
 	)
 	v1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	return nil
@@ -0,0 +1,150 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package v1beta1
+
+import (
+	"github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/consts"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const snapshotFinalizerPurgeProtection = Group + "/purge-protection"
+
+// SnapshotStatus denotes snapshot information.
+type SnapshotStatus struct {
+	DataPath string               `json:"dataPath"`
+	FSUUID   string               `json:"fsuuid"`
+	Size     int64                `json:"size"`
+	Status   types.SnapshotStatus `json:"status"`
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// +genclient
+// +genclient:nonNamespaced
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:storageversion
+// +k8s:openapi-gen=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DirectPVSnapshot denotes snapshot CRD object.
+type DirectPVSnapshot struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Status SnapshotStatus `json:"status"`
+}
+
+// NewDirectPVSnapshot creates new DirectPV snapshot.
+func NewDirectPVSnapshot(
+	name string,
+	sourceVolume string,
+	fsuuid string,
+	nodeID types.NodeID,
+	driveID types.DriveID,
+	driveName types.DriveName,
+	size int64,
+) *DirectPVSnapshot {
+	return &DirectPVSnapshot{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: Group + "/" + Version,
+			Kind:       consts.SnapshotKind,
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Finalizers: []string{snapshotFinalizerPurgeProtection},
+			Labels: map[string]string{
+				string(types.SourceVolumeLabelKey): sourceVolume,
+				string(types.DriveLabelKey):        string(driveID),
+				string(types.NodeLabelKey):         string(nodeID),
+				string(types.DriveNameLabelKey):    string(driveName),
+				string(types.VersionLabelKey):      Version,
+				string(types.CreatedByLabelKey):    consts.ControllerName,
+			},
+		},
+		Status: SnapshotStatus{
+			FSUUID: fsuuid,
+			Size:   size,
+			Status: types.SnapshotStatusPending,
+		},
+	}
+}
+
+// IsReady returns whether this snapshot is ready to use or not.
+func (snapshot DirectPVSnapshot) IsReady() bool {
+	return snapshot.Status.Status == types.SnapshotStatusReady
+}
+
+// GetLabels overrides the definition to return non-nil map.
+func (snapshot *DirectPVSnapshot) GetLabels() map[string]string {
+	values := snapshot.ObjectMeta.GetLabels()
+	if values == nil {
+		values = map[string]string{}
+		snapshot.SetLabels(values)
+	}
+	return values
+}
+
+func (snapshot DirectPVSnapshot) getLabel(key types.LabelKey) types.LabelValue {
+	values := snapshot.GetLabels()
+	return types.ToLabelValue(values[string(key)])
+}
+
+// GetSourceVolume returns source volume name of this snapshot.
+func (snapshot DirectPVSnapshot) GetSourceVolume() string {
+	return string(snapshot.getLabel(types.SourceVolumeLabelKey))
+}
+
+// GetDriveID returns drive ID of associated drive of this snapshot.
+func (snapshot DirectPVSnapshot) GetDriveID() types.DriveID {
+	return types.DriveID(snapshot.getLabel(types.DriveLabelKey))
+}
+
+// GetDriveName returns drive name of associated drive of this snapshot.
+func (snapshot DirectPVSnapshot) GetDriveName() types.DriveName {
+	return types.DriveName(snapshot.getLabel(types.DriveNameLabelKey))
+}
+
+// GetNodeID returns node ID of associated drive of this snapshot.
+func (snapshot DirectPVSnapshot) GetNodeID() types.NodeID {
+	return types.NodeID(snapshot.getLabel(types.NodeLabelKey))
+}
+
+// RemovePurgeProtection removes purge protection.
+func (snapshot *DirectPVSnapshot) RemovePurgeProtection() {
+	finalizers := []string{}
+	for _, finalizer := range snapshot.Finalizers {
+		if finalizer != snapshotFinalizerPurgeProtection {
+			finalizers = append(finalizers, finalizer)
+		}
+	}
+
+	if len(finalizers) != len(snapshot.Finalizers) {
+		snapshot.Finalizers = finalizers
+	}
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DirectPVSnapshotList denotes list of snapshots.
+type DirectPVSnapshotList struct {
+	metav1.TypeMeta `json:",inline"`
+	// metdata is the standard list metadata.
+	// +optional
+	metav1.ListMeta `json:"metadata"`
+	Items           []DirectPVSnapshot `json:"items"`
+}
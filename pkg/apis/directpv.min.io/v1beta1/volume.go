@@ -21,6 +21,7 @@ import (
 
 	"github.com/minio/directpv/pkg/apis/directpv.min.io/types"
 	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/utils"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -45,6 +46,77 @@ type VolumeStatus struct {
 	// +listType=map
 	// +listMapKey=type
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type" protobuf:"bytes,1,rep,name=conditions"`
+	// +optional
+	Clone *CloneStatus `json:"clone,omitempty"`
+	// +optional
+	VolumeMode types.VolumeMode `json:"volumeMode,omitempty"`
+	// +optional
+	BlockAllocation types.BlockAllocation `json:"blockAllocation,omitempty"`
+	// +optional
+	TargetPaths []string `json:"targetPaths,omitempty"`
+	// +optional
+	InodeLimit int64 `json:"inodeLimit,omitempty"`
+	// +optional
+	ProjectID int64 `json:"projectID,omitempty"`
+	// +optional
+	IOLimits *IOLimits `json:"ioLimits,omitempty"`
+	// +optional
+	Ownership *VolumeOwnership `json:"ownership,omitempty"`
+	// +optional
+	MountFlags []string `json:"mountFlags,omitempty"`
+	// +optional
+	ReadOnlyTargetPaths []string `json:"readOnlyTargetPaths,omitempty"`
+	// +optional
+	Move *MoveStatus `json:"move,omitempty"`
+}
+
+// CloneStatus denotes volume clone information.
+type CloneStatus struct {
+	// +optional
+	SourceVolume string `json:"sourceVolume,omitempty"`
+	// +optional
+	SourceSnapshot string           `json:"sourceSnapshot,omitempty"`
+	SourceFSUUID   string           `json:"sourceFSUUID"`
+	State          types.CloneState `json:"state"`
+	TotalBytes     int64            `json:"totalBytes"`
+	CopiedBytes    int64            `json:"copiedBytes"`
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// MoveStatus denotes volume data move information.
+type MoveStatus struct {
+	SourceDriveID types.DriveID   `json:"sourceDriveID"`
+	SourceFSUUID  string          `json:"sourceFSUUID"`
+	State         types.MoveState `json:"state"`
+	TotalBytes    int64           `json:"totalBytes"`
+	CopiedBytes   int64           `json:"copiedBytes"`
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// IOLimits denotes I/O limits of a volume applied to its pods by cgroup v2 io.max.
+type IOLimits struct {
+	// +optional
+	ReadBytesPerSec int64 `json:"readBytesPerSec,omitempty"`
+	// +optional
+	WriteBytesPerSec int64 `json:"writeBytesPerSec,omitempty"`
+	// +optional
+	ReadIOPS int64 `json:"readIOPS,omitempty"`
+	// +optional
+	WriteIOPS int64 `json:"writeIOPS,omitempty"`
+	// +optional
+	Device string `json:"device,omitempty"`
+}
+
+// VolumeOwnership denotes owner, group and permission of a volume root directory.
+type VolumeOwnership struct {
+	// +optional
+	UID int64 `json:"uid,omitempty"`
+	// +optional
+	GID int64 `json:"gid,omitempty"`
+	// +optional
+	Mode string `json:"mode,omitempty"`
 }
 
 // +genclient
@@ -99,14 +171,120 @@ func NewDirectPVVolume(
 	}
 }
 
+// IsCloned returns whether this volume has no pending clone.
+func (volume DirectPVVolume) IsCloned() bool {
+	return volume.Status.Clone == nil || volume.Status.Clone.State == types.CloneStateCompleted
+}
+
+// IsBlock returns whether this volume is a raw block volume.
+func (volume DirectPVVolume) IsBlock() bool {
+	return volume.Status.VolumeMode == types.VolumeModeBlock
+}
+
+// SetBlock marks this volume as a raw block volume allocated by given allocation. As
+// block device of the volume is preallocated, entire capacity is accounted as used.
+func (volume *DirectPVVolume) SetBlock(allocation types.BlockAllocation) {
+	volume.Status.VolumeMode = types.VolumeModeBlock
+	volume.Status.BlockAllocation = allocation
+	volume.Status.UsedCapacity = volume.Status.TotalCapacity
+	volume.Status.AvailableCapacity = 0
+}
+
 // IsStaged returns whether this volume is staged or not.
 func (volume DirectPVVolume) IsStaged() bool {
 	return volume.Status.StagingTargetPath != ""
 }
 
+// GetProjectID returns XFS project ID of the volume; zero if not assigned.
+func (volume DirectPVVolume) GetProjectID() uint32 {
+	return uint32(volume.Status.ProjectID)
+}
+
+// SetProjectID sets XFS project ID of the volume.
+func (volume *DirectPVVolume) SetProjectID(projectID uint32) {
+	volume.Status.ProjectID = int64(projectID)
+}
+
 // IsPublished returns whether this volume is published or not.
 func (volume DirectPVVolume) IsPublished() bool {
-	return volume.Status.TargetPath != ""
+	return len(volume.GetTargetPaths()) != 0
+}
+
+// GetReadWriteTargetPaths returns target paths where this volume is published as read-write.
+func (volume DirectPVVolume) GetReadWriteTargetPaths() []string {
+	if len(volume.Status.TargetPaths) == 0 && len(volume.Status.ReadOnlyTargetPaths) == 0 && volume.Status.TargetPath != "" {
+		// Volumes published by older versions record only TargetPath.
+		return []string{volume.Status.TargetPath}
+	}
+	return volume.Status.TargetPaths
+}
+
+// GetReadOnlyTargetPaths returns target paths where this volume is published as read-only.
+func (volume DirectPVVolume) GetReadOnlyTargetPaths() []string {
+	return volume.Status.ReadOnlyTargetPaths
+}
+
+// GetTargetPaths returns all target paths where this volume is published.
+func (volume DirectPVVolume) GetTargetPaths() []string {
+	readWriteTargetPaths := volume.GetReadWriteTargetPaths()
+	if len(volume.Status.ReadOnlyTargetPaths) == 0 {
+		return readWriteTargetPaths
+	}
+	return append(append([]string{}, readWriteTargetPaths...), volume.Status.ReadOnlyTargetPaths...)
+}
+
+// IsReadOnlyTargetPath returns whether the target path is published as read-only.
+func (volume DirectPVVolume) IsReadOnlyTargetPath(targetPath string) bool {
+	return utils.Contains(volume.Status.ReadOnlyTargetPaths, targetPath)
+}
+
+// AddTargetPath adds the read-only or read-write target path to this volume.
+// TargetPath holds the first target path for backward compatibility.
+func (volume *DirectPVVolume) AddTargetPath(targetPath string, readOnly bool) bool {
+	if utils.Contains(volume.GetTargetPaths(), targetPath) {
+		if volume.IsReadOnlyTargetPath(targetPath) == readOnly {
+			return false
+		}
+		// Target path is republished with different access.
+		volume.RemoveTargetPath(targetPath)
+	}
+
+	if readOnly {
+		volume.Status.TargetPaths = volume.GetReadWriteTargetPaths()
+		volume.Status.ReadOnlyTargetPaths = append(append([]string{}, volume.Status.ReadOnlyTargetPaths...), targetPath)
+	} else {
+		volume.Status.TargetPaths = append(append([]string{}, volume.GetReadWriteTargetPaths()...), targetPath)
+	}
+	volume.Status.TargetPath = volume.GetTargetPaths()[0]
+	return true
+}
+
+// RemoveTargetPath removes the target path from this volume.
+func (volume *DirectPVVolume) RemoveTargetPath(targetPath string) (found bool) {
+	remove := func(values []string) []string {
+		var result []string
+		for _, value := range values {
+			if value == targetPath {
+				found = true
+			} else {
+				result = append(result, value)
+			}
+		}
+		return result
+	}
+
+	readWriteTargetPaths := remove(volume.GetReadWriteTargetPaths())
+	readOnlyTargetPaths := remove(volume.Status.ReadOnlyTargetPaths)
+	if found {
+		volume.Status.TargetPaths = readWriteTargetPaths
+		volume.Status.ReadOnlyTargetPaths = readOnlyTargetPaths
+		volume.Status.TargetPath = ""
+		if targetPaths := volume.GetTargetPaths(); len(targetPaths) != 0 {
+			volume.Status.TargetPath = targetPaths[0]
+		}
+	}
+
+	return found
 }
 
 // IsDriveLost returns whether associated drive is lost or not.
@@ -145,6 +323,42 @@ func (volume *DirectPVVolume) SetDriveLost() {
 	}
 }
 
+// GetNearlyFullCondition returns nearly full condition of the volume if present.
+func (volume DirectPVVolume) GetNearlyFullCondition() *metav1.Condition {
+	for i := range volume.Status.Conditions {
+		if volume.Status.Conditions[i].Type == string(types.VolumeConditionTypeNearlyFull) {
+			return &volume.Status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// IsNearlyFull returns whether volume usage is above usage threshold or not.
+func (volume DirectPVVolume) IsNearlyFull() bool {
+	condition := volume.GetNearlyFullCondition()
+	return condition != nil && condition.Status == metav1.ConditionTrue
+}
+
+// SetNearlyFull sets nearly full condition of the volume by the reason.
+func (volume *DirectPVVolume) SetNearlyFull(reason types.VolumeConditionReason, message string) {
+	status := metav1.ConditionTrue
+	if reason == types.VolumeConditionReasonUsageBelowThreshold {
+		status = metav1.ConditionFalse
+	}
+	c := metav1.Condition{
+		Type:               string(types.VolumeConditionTypeNearlyFull),
+		Status:             status,
+		Reason:             string(reason),
+		Message:            message,
+		LastTransitionTime: metav1.Now(),
+	}
+	if condition := volume.GetNearlyFullCondition(); condition != nil {
+		*condition = c
+		return
+	}
+	volume.Status.Conditions = append(volume.Status.Conditions, c)
+}
+
 // IsReleased returns whether this volume is released or not.
 func (volume DirectPVVolume) IsReleased() bool {
 	return len(volume.Finalizers) == 1 && volume.Finalizers[0] == volumeFinalizerPurgeProtection
@@ -316,6 +530,16 @@ func (volume *DirectPVVolume) Resume() bool {
 	return volume.RemoveLabel(types.SuspendLabelKey)
 }
 
+// IsEphemeral returns if the volume is a CSI ephemeral inline volume.
+func (volume DirectPVVolume) IsEphemeral() bool {
+	return string(volume.getLabel(types.EphemeralLabelKey)) == strconv.FormatBool(true)
+}
+
+// SetEphemeral marks the volume as a CSI ephemeral inline volume by setting the label `directpv.min.io/ephemeral: true`.
+func (volume *DirectPVVolume) SetEphemeral() bool {
+	return volume.SetLabel(types.EphemeralLabelKey, types.ToLabelValue(strconv.FormatBool(true)))
+}
+
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
 // DirectPVVolumeList denotes list of volumes.
@@ -56,6 +56,11 @@ func InitRequestClient() types.LatestInitRequestInterface {
 	return client.InitRequest()
 }
 
+// SnapshotClient gets latest versioned snapshot interface.
+func SnapshotClient() types.LatestSnapshotInterface {
+	return client.Snapshot()
+}
+
 // NewDriveLister returns the new drive lister
 func NewDriveLister() *DriveLister {
 	return client.NewDriveLister()
@@ -75,3 +80,8 @@ func NewNodeLister() *NodeLister {
 func NewInitRequestLister() *InitRequestLister {
 	return client.NewInitRequestLister()
 }
+
+// NewSnapshotLister returns the new snapshot lister
+func NewSnapshotLister() *SnapshotLister {
+	return client.NewSnapshotLister()
+}
@@ -697,3 +697,119 @@ func (r *latestInitRequestClient) Patch(ctx context.Context, name string, pt api
 	}
 	return toInitRequest(object)
 }
+
+func toSnapshot(object map[string]interface{}) (*types.Snapshot, error) {
+	var snapshot types.Snapshot
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(object, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// latestSnapshotClient is a dynamic snapshot interface.
+type latestSnapshotClient struct {
+	dynamicInterface
+}
+
+// latestSnapshotClientForConfig creates new dynamic snapshot interface.
+func latestSnapshotClientForConfig(k8sClient *k8s.Client) (*latestSnapshotClient, error) {
+	inter, err := dynamicInterfaceForConfig(k8sClient, consts.SnapshotKind, consts.SnapshotResource)
+	if err != nil {
+		return nil, err
+	}
+
+	return &latestSnapshotClient{*inter}, nil
+}
+
+// Create creates a snapshot and returns server's representation of the snapshot or an error on failure.
+func (r *latestSnapshotClient) Create(ctx context.Context, snapshot *types.Snapshot, opts metav1.CreateOptions) (*types.Snapshot, error) {
+	snapshot.TypeMeta = types.NewSnapshotTypeMeta()
+	unstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := r.dynamicInterface.Create(ctx, unstructured, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return toSnapshot(object)
+}
+
+// Update updates a snapshot and returns server's representation of the snapshot or an error on failure.
+func (r *latestSnapshotClient) Update(ctx context.Context, snapshot *types.Snapshot, opts metav1.UpdateOptions) (*types.Snapshot, error) {
+	snapshot.TypeMeta = types.NewSnapshotTypeMeta()
+	unstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	object, err := r.dynamicInterface.Update(ctx, unstructured, opts)
+	if err != nil {
+		return nil, err
+	}
+	return toSnapshot(object)
+}
+
+// UpdateStatus was generated because the type contains a Status member.
+// Add a +genclient:noStatus comment above the type to avoid generating UpdateStatus().
+func (r *latestSnapshotClient) UpdateStatus(ctx context.Context, snapshot *types.Snapshot, opts metav1.UpdateOptions) (*types.Snapshot, error) {
+	snapshot.TypeMeta = types.NewSnapshotTypeMeta()
+	unstructured, err := runtime.DefaultUnstructuredConverter.ToUnstructured(snapshot)
+	if err != nil {
+		return nil, err
+	}
+	object, err := r.dynamicInterface.UpdateStatus(ctx, unstructured, opts)
+	if err != nil {
+		return nil, err
+	}
+	return toSnapshot(object)
+}
+
+// Get returns a snapshot by name or an error on failure.
+func (r *latestSnapshotClient) Get(ctx context.Context, name string, opts metav1.GetOptions) (*types.Snapshot, error) {
+	object, err := r.dynamicInterface.Get(ctx, name, opts)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot types.Snapshot
+	if err = runtime.DefaultUnstructuredConverter.FromUnstructured(object, &snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// List returns list of snapshot filtered by label and field selectors or an error on failure.
+func (r *latestSnapshotClient) List(ctx context.Context, opts metav1.ListOptions) (*types.SnapshotList, error) {
+	object, items, err := r.dynamicInterface.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshotList types.SnapshotList
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(object, &snapshotList)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots := []types.Snapshot{}
+	for i := range items {
+		snapshot, err := toSnapshot(items[i])
+		if err != nil {
+			return nil, err
+		}
+		snapshots = append(snapshots, *snapshot)
+	}
+	snapshotList.Items = snapshots
+
+	return &snapshotList, nil
+}
+
+// Patch patches a snapshot by name and returns patched snapshot or an error on failure.
+func (r *latestSnapshotClient) Patch(ctx context.Context, name string, pt apimachinerytypes.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *types.Snapshot, err error) {
+	object, err := r.dynamicInterface.Patch(ctx, name, pt, data, opts, subresources...)
+	if err != nil {
+		return nil, err
+	}
+	return toSnapshot(object)
+}
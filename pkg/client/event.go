@@ -41,6 +41,7 @@ type EventReason string
 const (
 	EventReasonStageVolume             EventReason = "StageVolume"
 	EventReasonVolumeMoved             EventReason = "VolumeMoved"
+	EventReasonVolumeMoveFailed        EventReason = "VolumeMoveFailed"
 	EventReasonMetrics                 EventReason = "Metrics"
 	EventReasonVolumeProvisioned       EventReason = "VolumeProvisioned"
 	EventReasonVolumeAdded             EventReason = "VolumeAdded"
@@ -51,8 +52,22 @@ const (
 	EventReasonDriveHasMultipleMatches EventReason = "DriveHasMultipleMatches"
 	EventReasonDriveIOError            EventReason = "DriveHasIOError"
 	EventReasonDriveRelabelError       EventReason = "DriveHasRelabelError"
+	EventReasonDriveDrained            EventReason = "DriveDrained"
+	EventReasonDriveRepaired           EventReason = "DriveRepaired"
+	EventReasonDriveRepairFailed       EventReason = "DriveRepairFailed"
+	EventReasonVolumeNotDrained        EventReason = "VolumeNotDrained"
 	EventReasonInitError               EventReason = "InitError"
 	EventReasonDeviceNotFoundError     EventReason = "DeviceNotFoundError"
+	EventReasonSnapshotCreated         EventReason = "SnapshotCreated"
+	EventReasonSnapshotError           EventReason = "SnapshotError"
+	EventReasonVolumeCloned            EventReason = "VolumeCloned"
+	EventReasonVolumeCloneFailed       EventReason = "VolumeCloneFailed"
+	EventReasonVolumeProvisionFailed   EventReason = "VolumeProvisionFailed"
+	EventReasonVolumeNearlyFull        EventReason = "VolumeNearlyFull"
+	EventReasonVolumeUsageNormal       EventReason = "VolumeUsageNormal"
+	EventReasonVolumeIOLimitsFailed    EventReason = "VolumeIOLimitsFailed"
+	EventReasonVolumeMountReconciled   EventReason = "VolumeMountReconciled"
+	EventReasonVolumeReconcileFailed   EventReason = "VolumeReconcileFailed"
 )
 
 var (
@@ -31,6 +31,7 @@ func FakeInit() {
 	volumeClient := clientsetInterface.DirectpvLatest().DirectPVVolumes()
 	nodeClient := clientsetInterface.DirectpvLatest().DirectPVNodes()
 	initRequestClient := clientsetInterface.DirectpvLatest().DirectPVInitRequests()
+	snapshotClient := clientsetInterface.DirectpvLatest().DirectPVSnapshots()
 	restClient := clientsetInterface.DirectpvLatest().RESTClient()
 
 	initEvent(k8sClient.KubeClient)
@@ -42,6 +43,7 @@ func FakeInit() {
 		VolumeClient:       volumeClient,
 		NodeClient:         nodeClient,
 		InitRequestClient:  initRequestClient,
+		SnapshotClient:     snapshotClient,
 	}
 }
 
@@ -68,3 +70,9 @@ func SetNodeInterface(i types.LatestNodeInterface) {
 func SetInitRequestInterface(i types.LatestInitRequestInterface) {
 	client.InitRequestClient = i
 }
+
+// SetSnapshotInterface sets latest snapshot interface.
+// Note: To be used for writing test cases only
+func SetSnapshotInterface(i types.LatestSnapshotInterface) {
+	client.SnapshotClient = i
+}
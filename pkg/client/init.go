@@ -59,6 +59,7 @@ type Client struct {
 	VolumeClient       types.LatestVolumeInterface
 	NodeClient         types.LatestNodeInterface
 	InitRequestClient  types.LatestInitRequestInterface
+	SnapshotClient     types.LatestSnapshotInterface
 	K8sClient          *k8s.Client
 }
 
@@ -87,6 +88,11 @@ func (c Client) InitRequest() types.LatestInitRequestInterface {
 	return c.InitRequestClient
 }
 
+// Snapshot returns the DirectPV Snapshot interface
+func (c Client) Snapshot() types.LatestSnapshotInterface {
+	return c.SnapshotClient
+}
+
 // K8s returns the kubernetes client
 func (c Client) K8s() *k8s.Client {
 	return c.K8sClient
@@ -150,6 +156,10 @@ func newClient(k8sClient *k8s.Client) (*Client, error) {
 	if err != nil {
 		return nil, fmt.Errorf("unable to create new initrequest interface; %v", err)
 	}
+	snapshotClient, err := latestSnapshotClientForConfig(k8sClient)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create new snapshot interface; %v", err)
+	}
 	return &Client{
 		ClientsetInterface: clientsetInterface,
 		RESTClient:         restClient,
@@ -157,6 +167,7 @@ func newClient(k8sClient *k8s.Client) (*Client, error) {
 		VolumeClient:       volumeClient,
 		NodeClient:         nodeClient,
 		InitRequestClient:  initRequestClient,
+		SnapshotClient:     snapshotClient,
 		K8sClient:          k8sClient,
 	}, nil
 }
@@ -0,0 +1,234 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package client
+
+import (
+	"context"
+	"fmt"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/k8s"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// ListSnapshotResult denotes list of snapshot result.
+type ListSnapshotResult struct {
+	Snapshot types.Snapshot
+	Err      error
+}
+
+// SnapshotLister is snapshot lister.
+type SnapshotLister struct {
+	nodes          []directpvtypes.LabelValue
+	sourceVolumes  []directpvtypes.LabelValue
+	snapshotNames  []string
+	maxObjects     int64
+	ignoreNotFound bool
+	snapshotClient types.LatestSnapshotInterface
+}
+
+// NewSnapshotLister creates new snapshot lister.
+func (client Client) NewSnapshotLister() *SnapshotLister {
+	return &SnapshotLister{
+		maxObjects:     k8s.MaxThreadCount,
+		snapshotClient: client.Snapshot(),
+	}
+}
+
+// NodeSelector adds filter listing by nodes.
+func (lister *SnapshotLister) NodeSelector(nodes []directpvtypes.LabelValue) *SnapshotLister {
+	lister.nodes = nodes
+	return lister
+}
+
+// SourceVolumeSelector adds filter listing by source volumes.
+func (lister *SnapshotLister) SourceVolumeSelector(sourceVolumes []directpvtypes.LabelValue) *SnapshotLister {
+	lister.sourceVolumes = sourceVolumes
+	return lister
+}
+
+// SnapshotNameSelector adds filter listing by SnapshotNames.
+func (lister *SnapshotLister) SnapshotNameSelector(snapshotNames []string) *SnapshotLister {
+	lister.snapshotNames = snapshotNames
+	return lister
+}
+
+// MaxObjects controls number of items to be fetched in every iteration.
+func (lister *SnapshotLister) MaxObjects(n int64) *SnapshotLister {
+	lister.maxObjects = n
+	return lister
+}
+
+// IgnoreNotFound controls listing to ignore not found error.
+func (lister *SnapshotLister) IgnoreNotFound(b bool) *SnapshotLister {
+	lister.ignoreNotFound = b
+	return lister
+}
+
+// List returns channel to loop through snapshot items.
+func (lister *SnapshotLister) List(ctx context.Context) <-chan ListSnapshotResult {
+	getOnly := len(lister.nodes) == 0 &&
+		len(lister.sourceVolumes) == 0 &&
+		len(lister.snapshotNames) != 0
+
+	labelMap := map[directpvtypes.LabelKey][]directpvtypes.LabelValue{
+		directpvtypes.NodeLabelKey:         lister.nodes,
+		directpvtypes.SourceVolumeLabelKey: lister.sourceVolumes,
+	}
+	labelSelector := directpvtypes.ToLabelSelector(labelMap)
+
+	resultCh := make(chan ListSnapshotResult)
+	go func() {
+		defer close(resultCh)
+
+		send := func(result ListSnapshotResult) bool {
+			select {
+			case <-ctx.Done():
+				return false
+			case resultCh <- result:
+				return true
+			}
+		}
+
+		if !getOnly {
+			options := metav1.ListOptions{
+				Limit:         lister.maxObjects,
+				LabelSelector: labelSelector,
+			}
+			for {
+				result, err := lister.snapshotClient.List(ctx, options)
+				if err != nil {
+					if apierrors.IsNotFound(err) && lister.ignoreNotFound {
+						break
+					}
+
+					send(ListSnapshotResult{Err: err})
+					return
+				}
+
+				for _, item := range result.Items {
+					var found bool
+					var values []string
+					for i := range lister.snapshotNames {
+						if lister.snapshotNames[i] == item.Name {
+							found = true
+						} else {
+							values = append(values, lister.snapshotNames[i])
+						}
+					}
+					lister.snapshotNames = values
+
+					if found || len(lister.snapshotNames) == 0 {
+						if !send(ListSnapshotResult{Snapshot: item}) {
+							return
+						}
+					}
+				}
+
+				if result.Continue == "" {
+					break
+				}
+
+				options.Continue = result.Continue
+			}
+		}
+
+		for _, snapshotName := range lister.snapshotNames {
+			snapshot, err := lister.snapshotClient.Get(ctx, snapshotName, metav1.GetOptions{})
+			if err != nil {
+				if apierrors.IsNotFound(err) && lister.ignoreNotFound {
+					continue
+				}
+
+				send(ListSnapshotResult{Err: err})
+				return
+			}
+			if !send(ListSnapshotResult{Snapshot: *snapshot}) {
+				return
+			}
+		}
+	}()
+
+	return resultCh
+}
+
+// Get returns list of snapshot.
+func (lister *SnapshotLister) Get(ctx context.Context) ([]types.Snapshot, error) {
+	ctx, cancelFunc := context.WithCancel(ctx)
+	defer cancelFunc()
+
+	snapshotList := []types.Snapshot{}
+	for result := range lister.List(ctx) {
+		if result.Err != nil {
+			return snapshotList, result.Err
+		}
+		snapshotList = append(snapshotList, result.Snapshot)
+	}
+
+	return snapshotList, nil
+}
+
+// Watch looks for changes in SnapshotList and reports them.
+func (lister *SnapshotLister) Watch(ctx context.Context) (<-chan WatchEvent[*types.Snapshot], func(), error) {
+	labelMap := map[directpvtypes.LabelKey][]directpvtypes.LabelValue{
+		directpvtypes.NodeLabelKey:         lister.nodes,
+		directpvtypes.SourceVolumeLabelKey: lister.sourceVolumes,
+	}
+	snapshotWatchInterface, err := lister.snapshotClient.Watch(ctx, metav1.ListOptions{
+		LabelSelector: directpvtypes.ToLabelSelector(labelMap),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	stopFn := snapshotWatchInterface.Stop
+
+	watchCh := make(chan WatchEvent[*types.Snapshot])
+	go func() {
+		defer close(watchCh)
+		resultCh := snapshotWatchInterface.ResultChan()
+		for {
+			result, ok := <-resultCh
+			if !ok {
+				break
+			}
+			unstructured := result.Object.(*unstructured.Unstructured)
+			var snapshot types.Snapshot
+			err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructured.Object, &snapshot)
+			if err != nil {
+				watchCh <- WatchEvent[*types.Snapshot]{
+					Type: result.Type,
+					Err:  fmt.Errorf("unable to convert unstructured object %s; %v", unstructured.GetName(), err),
+				}
+				continue
+			}
+			if len(lister.snapshotNames) > 0 && !utils.Contains(lister.snapshotNames, snapshot.Name) {
+				continue
+			}
+			watchCh <- WatchEvent[*types.Snapshot]{
+				Type: result.Type,
+				Item: &snapshot,
+			}
+		}
+	}()
+
+	return watchCh, stopFn, nil
+}
@@ -31,6 +31,7 @@ type DirectpvV1beta1Interface interface {
 	DirectPVDrivesGetter
 	DirectPVInitRequestsGetter
 	DirectPVNodesGetter
+	DirectPVSnapshotsGetter
 	DirectPVVolumesGetter
 }
 
@@ -51,6 +52,10 @@ func (c *DirectpvV1beta1Client) DirectPVNodes() DirectPVNodeInterface {
 	return newDirectPVNodes(c)
 }
 
+func (c *DirectpvV1beta1Client) DirectPVSnapshots() DirectPVSnapshotInterface {
+	return newDirectPVSnapshots(c)
+}
+
 func (c *DirectpvV1beta1Client) DirectPVVolumes() DirectPVVolumeInterface {
 	return newDirectPVVolumes(c)
 }
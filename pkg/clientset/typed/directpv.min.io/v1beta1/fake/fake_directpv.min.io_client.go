@@ -40,6 +40,10 @@ func (c *FakeDirectpvV1beta1) DirectPVNodes() v1beta1.DirectPVNodeInterface {
 	return &FakeDirectPVNodes{c}
 }
 
+func (c *FakeDirectpvV1beta1) DirectPVSnapshots() v1beta1.DirectPVSnapshotInterface {
+	return &FakeDirectPVSnapshots{c}
+}
+
 func (c *FakeDirectpvV1beta1) DirectPVVolumes() v1beta1.DirectPVVolumeInterface {
 	return &FakeDirectPVVolumes{c}
 }
@@ -24,4 +24,6 @@ type DirectPVInitRequestExpansion interface{}
 
 type DirectPVNodeExpansion interface{}
 
+type DirectPVSnapshotExpansion interface{}
+
 type DirectPVVolumeExpansion interface{}
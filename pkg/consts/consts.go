@@ -58,6 +58,9 @@ const (
 	// InitRequestKind denotes the InitRequest CRD kind.
 	InitRequestKind = AppPrettyName + "InitRequest"
 
+	// SnapshotKind is snapshot CRD kind.
+	SnapshotKind = AppPrettyName + "Snapshot"
+
 	// DriveResource is drive CRD resource.
 	DriveResource = AppName + "drives"
 
@@ -70,6 +73,9 @@ const (
 	// InitRequestResource is initrequest CRD resource.
 	InitRequestResource = AppName + "initrequests"
 
+	// SnapshotResource is snapshot CRD resource.
+	SnapshotResource = AppName + "snapshots"
+
 	// AppRootDir is application root directory.
 	AppRootDir = "/var/lib/" + AppName
 
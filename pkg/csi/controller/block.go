@@ -0,0 +1,54 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"fmt"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+)
+
+// isBlockRequest returns whether raw block volume is requested.
+func isBlockRequest(req *csi.CreateVolumeRequest) bool {
+	for _, vcap := range req.GetVolumeCapabilities() {
+		if vcap.GetBlock() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// getBlockAllocation returns block allocation of raw block volume request.
+// Raw block volumes must be opted-in by the block-volume storage class
+// parameter. Empty value is returned for filesystem volume requests.
+func getBlockAllocation(req *csi.CreateVolumeRequest) (directpvtypes.BlockAllocation, error) {
+	if !isBlockRequest(req) {
+		return "", nil
+	}
+
+	value, found := req.GetParameters()[string(directpvtypes.BlockVolumeLabelKey)]
+	if !found {
+		return "", fmt.Errorf(
+			"block volume mode is not enabled; set storage class parameter %v to %v",
+			directpvtypes.BlockVolumeLabelKey,
+			directpvtypes.BlockAllocationLoopFile,
+		)
+	}
+
+	return directpvtypes.ToBlockAllocation(value)
+}
@@ -0,0 +1,159 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newBlockTestRequest(name string, size int64, allocation string) *csi.CreateVolumeRequest {
+	req := &csi.CreateVolumeRequest{
+		Name:          name,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: size},
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			},
+		},
+	}
+	if allocation != "" {
+		req.Parameters = map[string]string{string(directpvtypes.BlockVolumeLabelKey): allocation}
+	}
+	return req
+}
+
+func TestGetBlockAllocation(t *testing.T) {
+	testCases := []struct {
+		req                *csi.CreateVolumeRequest
+		expectedAllocation directpvtypes.BlockAllocation
+		expectErr          bool
+	}{
+		{newReserveTestRequest("volume-1", MiB), "", false},
+		{newBlockTestRequest("volume-1", MiB, "loop-file"), directpvtypes.BlockAllocationLoopFile, false},
+		{newBlockTestRequest("volume-1", MiB, "Loop-File"), directpvtypes.BlockAllocationLoopFile, false},
+		{newBlockTestRequest("volume-1", MiB, ""), "", true},
+		{newBlockTestRequest("volume-1", MiB, "drive"), "", true},
+		{newBlockTestRequest("volume-1", MiB, "partition"), "", true},
+	}
+
+	for i, testCase := range testCases {
+		allocation, err := getBlockAllocation(testCase.req)
+		if testCase.expectErr != (err != nil) {
+			t.Fatalf("case %v: expectErr: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if allocation != testCase.expectedAllocation {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedAllocation, allocation)
+		}
+	}
+}
+
+func TestMatchDriveBlockVolume(t *testing.T) {
+	testCases := []struct {
+		drive         *types.Drive
+		req           *csi.CreateVolumeRequest
+		expectedMatch bool
+	}{
+		{newReserveTestDrive("drive-1", 50*MiB), newBlockTestRequest("volume-1", MiB, "loop-file"), true},
+		{newReserveTestDrive("drive-1", 50*MiB, "volume-0"), newBlockTestRequest("volume-1", MiB, "loop-file"), true},
+		{newReserveTestDrive("drive-1", 50*MiB), newBlockTestRequest("volume-1", 60*MiB, "loop-file"), false},
+	}
+
+	for i, testCase := range testCases {
+		if match := matchDrive(testCase.drive, testCase.req); match != testCase.expectedMatch {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedMatch, match)
+		}
+	}
+}
+
+func TestCreateBlockVolume(t *testing.T) {
+	setupReserveTest(0, 0, newReserveTestDrive("drive-1", 50*MiB))
+
+	for i := 0; i < 2; i++ { // second call checks idempotency.
+		resp, err := NewServer().CreateVolume(context.TODO(), newBlockTestRequest("volume-1", 30*MiB, "loop-file"))
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		if resp.GetVolume().GetCapacityBytes() != 30*MiB {
+			t.Fatalf("capacity: expected: %v, got: %v", 30*MiB, resp.GetVolume().GetCapacityBytes())
+		}
+	}
+
+	volume, err := client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get volume; %v", err)
+	}
+	if !volume.IsBlock() || volume.Status.BlockAllocation != directpvtypes.BlockAllocationLoopFile {
+		t.Fatalf("unexpected volume status %+v", volume.Status)
+	}
+	if volume.Status.UsedCapacity != 30*MiB || volume.Status.AvailableCapacity != 0 {
+		t.Fatalf("block volume capacity must be used; got: %+v", volume.Status)
+	}
+
+	drive := getTestDrive(t, "drive-1")
+	if drive.Status.FreeCapacity != 20*MiB {
+		t.Fatalf("free capacity: expected: %v, got: %v", 20*MiB, drive.Status.FreeCapacity)
+	}
+}
+
+func TestCreateBlockVolumeErrors(t *testing.T) {
+	testCases := []struct {
+		drive        *types.Drive
+		req          *csi.CreateVolumeRequest
+		expectedCode codes.Code
+	}{
+		{newReserveTestDrive("drive-1", 50*MiB), newBlockTestRequest("volume-1", 30*MiB, ""), codes.InvalidArgument},
+		{newReserveTestDrive("drive-1", 50*MiB), newBlockTestRequest("volume-1", 30*MiB, "disk"), codes.InvalidArgument},
+		{newReserveTestDrive("drive-1", 50*MiB), newBlockTestRequest("volume-1", 30*MiB, "drive"), codes.InvalidArgument},
+		{newReserveTestDrive("drive-1", 50*MiB), newBlockTestRequest("volume-1", 60*MiB, "loop-file"), codes.OutOfRange},
+	}
+
+	for i, testCase := range testCases {
+		setupReserveTest(0, 0, testCase.drive)
+		_, err := NewServer().CreateVolume(context.TODO(), testCase.req)
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedCode, err)
+		}
+	}
+}
+
+func TestControllerExpandBlockVolume(t *testing.T) {
+	volume := types.NewVolume("volume-1", "fsuuid-1", "node-1", "drive-1", "drive-1", 30*MiB)
+	volume.SetBlock(directpvtypes.BlockAllocationLoopFile)
+	setupReserveTest(0, 0, newReserveTestDrive("drive-1", 20*MiB, "volume-1"), volume)
+
+	if _, err := NewServer().ControllerExpandVolume(context.TODO(), &csi.ControllerExpandVolumeRequest{
+		VolumeId:      "volume-1",
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 40 * MiB},
+	}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	drive := getTestDrive(t, "drive-1")
+	if drive.Status.FreeCapacity != 10*MiB {
+		t.Fatalf("free capacity: expected: %v, got: %v", 10*MiB, drive.Status.FreeCapacity)
+	}
+}
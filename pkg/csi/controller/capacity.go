@@ -0,0 +1,72 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+	"k8s.io/klog/v2"
+)
+
+// GetCapacity returns available capacity of drives matching requested topology segment and parameters.
+// reference: https://github.com/container-storage-interface/spec/blob/master/spec.md#getcapacity
+func (c *Server) GetCapacity(ctx context.Context, req *csi.GetCapacityRequest) (*csi.GetCapacityResponse, error) {
+	klog.V(4).InfoS("Get capacity requested", "segments", req.GetAccessibleTopology().GetSegments(), "parameters", req.GetParameters())
+
+	// Reuse drive matching of CreateVolume by converting this request to a
+	// volume request without capacity range.
+	volumeReq := &csi.CreateVolumeRequest{
+		Parameters: req.GetParameters(),
+	}
+	if req.GetAccessibleTopology() != nil {
+		volumeReq.AccessibilityRequirements = &csi.TopologyRequirement{
+			Requisite: []*csi.Topology{req.GetAccessibleTopology()},
+		}
+	}
+
+	indexName, indexValue := driveStatusIndex, string(directpvtypes.DriveStatusReady)
+	if nodeID, found := req.GetAccessibleTopology().GetSegments()[string(directpvtypes.TopologyDriverNode)]; found {
+		indexName, indexValue = driveNodeIndex, nodeID
+	}
+
+	drives, err := c.listDrives(ctx, indexName, indexValue)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to list drives; %v", err)
+	}
+
+	var availableCapacity, maximumVolumeSize int64
+	for i := range drives {
+		if !matchDrive(&drives[i], volumeReq) {
+			continue
+		}
+
+		availableCapacity += drives[i].Status.FreeCapacity
+		if drives[i].Status.FreeCapacity > maximumVolumeSize {
+			maximumVolumeSize = drives[i].Status.FreeCapacity
+		}
+	}
+
+	return &csi.GetCapacityResponse{
+		AvailableCapacity: availableCapacity,
+		MaximumVolumeSize: wrapperspb.Int64(maximumVolumeSize),
+	}, nil
+}
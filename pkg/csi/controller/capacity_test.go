@@ -0,0 +1,121 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestGetCapacity(t *testing.T) {
+	newDrive := func(driveID directpvtypes.DriveID, nodeID directpvtypes.NodeID, accessTier directpvtypes.AccessTier, freeCapacity int64) *types.Drive {
+		return types.NewDrive(
+			driveID,
+			types.DriveStatus{
+				TotalCapacity: 100 * MiB,
+				FreeCapacity:  freeCapacity,
+				Status:        directpvtypes.DriveStatusReady,
+				Topology: map[string]string{
+					"node": string(nodeID),
+					"rack": "rack1",
+					"zone": "zone1",
+				},
+			},
+			nodeID,
+			directpvtypes.DriveName(driveID),
+			accessTier,
+		)
+	}
+
+	unschedulableDrive := newDrive("drive-4", "node-2", directpvtypes.AccessTierDefault, 90*MiB)
+	unschedulableDrive.Unschedulable()
+	errorDrive := newDrive("drive-5", "node-2", directpvtypes.AccessTierDefault, 90*MiB)
+	errorDrive.Status.Status = directpvtypes.DriveStatusError
+	terminatingDrive := newDrive("drive-6", "node-2", directpvtypes.AccessTierDefault, 90*MiB)
+	now := metav1.Now()
+	terminatingDrive.DeletionTimestamp = &now
+
+	objects := []runtime.Object{
+		newDrive("drive-1", "node-1", directpvtypes.AccessTierDefault, 10*MiB),
+		newDrive("drive-2", "node-1", directpvtypes.AccessTierHot, 30*MiB),
+		newDrive("drive-3", "node-2", directpvtypes.AccessTierHot, 20*MiB),
+		unschedulableDrive,
+		errorDrive,
+		terminatingDrive,
+	}
+
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+	accessTierKey := string(directpvtypes.AccessTierLabelKey)
+	testCases := []struct {
+		req                       *csi.GetCapacityRequest
+		expectedAvailableCapacity int64
+		expectedMaximumVolumeSize int64
+	}{
+		{&csi.GetCapacityRequest{}, 60 * MiB, 30 * MiB},
+		{
+			&csi.GetCapacityRequest{AccessibleTopology: &csi.Topology{Segments: map[string]string{"node": "node-1"}}},
+			40 * MiB, 30 * MiB,
+		},
+		{
+			&csi.GetCapacityRequest{AccessibleTopology: &csi.Topology{Segments: map[string]string{"node": "node-2", "rack": "rack1"}}},
+			20 * MiB, 20 * MiB,
+		},
+		{
+			&csi.GetCapacityRequest{AccessibleTopology: &csi.Topology{Segments: map[string]string{"zone": "zone1"}}},
+			60 * MiB, 30 * MiB,
+		},
+		{
+			&csi.GetCapacityRequest{AccessibleTopology: &csi.Topology{Segments: map[string]string{"zone": "zone2"}}},
+			0, 0,
+		},
+		{
+			&csi.GetCapacityRequest{Parameters: map[string]string{accessTierKey: "Hot"}},
+			50 * MiB, 30 * MiB,
+		},
+		{
+			&csi.GetCapacityRequest{
+				AccessibleTopology: &csi.Topology{Segments: map[string]string{"node": "node-1"}},
+				Parameters:         map[string]string{accessTierKey: "Default"},
+			},
+			10 * MiB, 10 * MiB,
+		},
+	}
+
+	for i, testCase := range testCases {
+		result, err := NewServer().GetCapacity(context.TODO(), testCase.req)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if result.AvailableCapacity != testCase.expectedAvailableCapacity {
+			t.Fatalf("case %v: available capacity: expected: %v, got: %v", i+1, testCase.expectedAvailableCapacity, result.AvailableCapacity)
+		}
+		if result.GetMaximumVolumeSize().GetValue() != testCase.expectedMaximumVolumeSize {
+			t.Fatalf("case %v: maximum volume size: expected: %v, got: %v", i+1, testCase.expectedMaximumVolumeSize, result.GetMaximumVolumeSize().GetValue())
+		}
+	}
+}
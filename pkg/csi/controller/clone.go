@@ -0,0 +1,147 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type cloneSource struct {
+	volume   string
+	snapshot string
+	fsuuid   string
+	nodeID   directpvtypes.NodeID
+	driveID  directpvtypes.DriveID
+	size     int64
+	block    bool
+}
+
+func (source cloneSource) toCloneStatus() *types.CloneStatus {
+	return &types.CloneStatus{
+		SourceVolume:   source.volume,
+		SourceSnapshot: source.snapshot,
+		SourceFSUUID:   source.fsuuid,
+		State:          directpvtypes.CloneStatePending,
+	}
+}
+
+func getCloneSource(ctx context.Context, contentSource *csi.VolumeContentSource) (*cloneSource, error) {
+	switch {
+	case contentSource.GetVolume() != nil:
+		volumeID := contentSource.GetVolume().GetVolumeId()
+		volume, err := client.VolumeClient().Get(ctx, volumeID, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, status.Errorf(codes.NotFound, "source volume %v not found", volumeID)
+			}
+			return nil, status.Errorf(codes.Internal, "unable to get source volume %v; %v", volumeID, err)
+		}
+		if !volume.GetDeletionTimestamp().IsZero() {
+			return nil, status.Errorf(codes.NotFound, "source volume %v is being deleted", volumeID)
+		}
+		if !volume.IsCloned() {
+			return nil, status.Errorf(codes.Unavailable, "source volume %v is not yet cloned", volumeID)
+		}
+		return &cloneSource{
+			volume:  volume.Name,
+			fsuuid:  volume.Status.FSUUID,
+			nodeID:  volume.GetNodeID(),
+			driveID: volume.GetDriveID(),
+			size:    volume.Status.TotalCapacity,
+			block:   volume.IsBlock(),
+		}, nil
+
+	case contentSource.GetSnapshot() != nil:
+		snapshotID := contentSource.GetSnapshot().GetSnapshotId()
+		snapshot, err := client.SnapshotClient().Get(ctx, snapshotID, metav1.GetOptions{TypeMeta: types.NewSnapshotTypeMeta()})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return nil, status.Errorf(codes.NotFound, "source snapshot %v not found", snapshotID)
+			}
+			return nil, status.Errorf(codes.Internal, "unable to get source snapshot %v; %v", snapshotID, err)
+		}
+		if !snapshot.GetDeletionTimestamp().IsZero() {
+			return nil, status.Errorf(codes.NotFound, "source snapshot %v is being deleted", snapshotID)
+		}
+		if !snapshot.IsReady() {
+			return nil, status.Errorf(codes.Unavailable, "source snapshot %v is not ready to use", snapshotID)
+		}
+		return &cloneSource{
+			snapshot: snapshot.Name,
+			fsuuid:   snapshot.Status.FSUUID,
+			nodeID:   snapshot.GetNodeID(),
+			driveID:  snapshot.GetDriveID(),
+			size:     snapshot.Status.Size,
+		}, nil
+	}
+
+	return nil, status.Error(codes.InvalidArgument, "unsupported volume content source")
+}
+
+// selectCloneDrive selects the drive of the clone source if it matches the
+// request, else selects a drive of the source node if spreading is allowed.
+func (c *Server) selectCloneDrive(ctx context.Context, req *csi.CreateVolumeRequest, source *cloneSource) (*types.Drive, error) {
+	if req.GetCapacityRange() != nil && req.GetCapacityRange().GetRequiredBytes() < source.size {
+		return nil, status.Errorf(
+			codes.OutOfRange,
+			"requested size %v is less than source size %v for volume %v",
+			req.GetCapacityRange().GetRequiredBytes(), source.size, req.GetName(),
+		)
+	}
+
+	drives, err := c.getFilteredDrives(ctx, req)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	spread, _ := strconv.ParseBool(req.GetParameters()[string(directpvtypes.CloneSpreadLabelKey)])
+
+	var nodeDrives []types.Drive
+	for i := range drives {
+		switch {
+		case drives[i].GetDriveID() == source.driveID:
+			return &drives[i], nil
+		case spread && drives[i].GetNodeID() == source.nodeID:
+			nodeDrives = append(nodeDrives, drives[i])
+		}
+	}
+
+	if len(nodeDrives) == 0 {
+		if spread {
+			return nil, status.Errorf(codes.ResourceExhausted, "no drive found on node %v of clone source for volume %v", source.nodeID, req.GetName())
+		}
+		return nil, status.Errorf(codes.ResourceExhausted, "drive %v of clone source does not satisfy the request for volume %v", source.driveID, req.GetName())
+	}
+
+	selected := &nodeDrives[0]
+	for i := range nodeDrives {
+		if nodeDrives[i].Status.FreeCapacity > selected.Status.FreeCapacity {
+			selected = &nodeDrives[i]
+		}
+	}
+	return selected, nil
+}
@@ -0,0 +1,131 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestCreateVolumeWithContentSource(t *testing.T) {
+	newDrive := func(driveID directpvtypes.DriveID, nodeID directpvtypes.NodeID, freeCapacity int64) *types.Drive {
+		return types.NewDrive(
+			driveID,
+			types.DriveStatus{
+				TotalCapacity: 100 * MiB,
+				FreeCapacity:  freeCapacity,
+				FSUUID:        string(driveID),
+				Status:        directpvtypes.DriveStatusReady,
+				Topology:      map[string]string{"node": string(nodeID)},
+			},
+			nodeID,
+			directpvtypes.DriveName(driveID),
+			directpvtypes.AccessTierDefault,
+		)
+	}
+
+	snapshot := types.NewSnapshot("snapshot-1", "volume-1", "drive-1", "node-1", "drive-1", "drive-1", 10*MiB)
+	snapshot.Status.Status = directpvtypes.SnapshotStatusReady
+	pendingSnapshot := types.NewSnapshot("snapshot-2", "volume-1", "drive-1", "node-1", "drive-1", "drive-1", 10*MiB)
+
+	newRequest := func(name string, size int64, source *csi.VolumeContentSource, spread bool) *csi.CreateVolumeRequest {
+		req := &csi.CreateVolumeRequest{
+			Name:                name,
+			CapacityRange:       &csi.CapacityRange{RequiredBytes: size},
+			VolumeContentSource: source,
+			Parameters:          map[string]string{},
+		}
+		if spread {
+			req.Parameters[string(directpvtypes.CloneSpreadLabelKey)] = "true"
+		}
+		return req
+	}
+	volumeSource := func(volumeID string) *csi.VolumeContentSource {
+		return &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Volume{Volume: &csi.VolumeContentSource_VolumeSource{VolumeId: volumeID}},
+		}
+	}
+	snapshotSource := func(snapshotID string) *csi.VolumeContentSource {
+		return &csi.VolumeContentSource{
+			Type: &csi.VolumeContentSource_Snapshot{Snapshot: &csi.VolumeContentSource_SnapshotSource{SnapshotId: snapshotID}},
+		}
+	}
+
+	testCases := []struct {
+		req             *csi.CreateVolumeRequest
+		expectedCode    codes.Code
+		expectedDriveID directpvtypes.DriveID
+	}{
+		{newRequest("clone-1", 10*MiB, volumeSource("volume-1"), false), codes.OK, "drive-1"},
+		{newRequest("clone-2", 10*MiB, snapshotSource("snapshot-1"), false), codes.OK, "drive-1"},
+		{newRequest("clone-3", 5*MiB, volumeSource("volume-1"), false), codes.OutOfRange, ""},
+		{newRequest("clone-4", 10*MiB, volumeSource("volume-x"), false), codes.NotFound, ""},
+		{newRequest("clone-5", 10*MiB, snapshotSource("snapshot-x"), false), codes.NotFound, ""},
+		{newRequest("clone-6", 10*MiB, snapshotSource("snapshot-2"), false), codes.Unavailable, ""},
+		{newRequest("clone-7", 60*MiB, volumeSource("volume-1"), false), codes.ResourceExhausted, ""},
+		{newRequest("clone-8", 60*MiB, volumeSource("volume-1"), true), codes.OK, "drive-2"},
+		{newRequest("clone-9", 90*MiB, volumeSource("volume-1"), true), codes.ResourceExhausted, ""},
+	}
+
+	ctx := context.TODO()
+	for i, testCase := range testCases {
+		objects := []runtime.Object{
+			newDrive("drive-1", "node-1", 50*MiB),
+			newDrive("drive-2", "node-1", 80*MiB),
+			newDrive("drive-3", "node-2", 100*MiB),
+			types.NewVolume("volume-1", "drive-1", "node-1", "drive-1", "drive-1", 10*MiB),
+			snapshot,
+			pendingSnapshot,
+		}
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
+		client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+		client.SetSnapshotInterface(clientset.DirectpvLatest().DirectPVSnapshots())
+
+		_, err := NewServer().CreateVolume(ctx, testCase.req)
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected code: %v, got: %v; %v", i+1, testCase.expectedCode, code, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		volume, err := client.VolumeClient().Get(ctx, testCase.req.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if volume.GetDriveID() != testCase.expectedDriveID {
+			t.Fatalf("case %v: expected drive: %v, got: %v", i+1, testCase.expectedDriveID, volume.GetDriveID())
+		}
+		if volume.Status.Clone == nil || volume.Status.Clone.State != directpvtypes.CloneStatePending || volume.Status.Clone.SourceFSUUID != "drive-1" {
+			t.Fatalf("case %v: unexpected clone status %+v", i+1, volume.Status.Clone)
+		}
+		if volume.IsCloned() {
+			t.Fatalf("case %v: expected volume not cloned", i+1)
+		}
+	}
+}
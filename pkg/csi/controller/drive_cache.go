@@ -0,0 +1,248 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Drive cache indexes.
+const (
+	driveNodeIndex       = "node"
+	driveAccessTierIndex = "accessTier"
+	driveStatusIndex     = "status"
+	driveVolumeIndex     = "volume"
+)
+
+// driveCache is a shared informer backed cache of drives with in-memory
+// capacity reservations. A reservation is held from drive selection till
+// the informer sees the volume in the drive.
+type driveCache struct {
+	informer     cache.SharedIndexInformer
+	mutex        sync.Mutex
+	reservations map[directpvtypes.DriveID]map[string]int64
+}
+
+func newDriveListerWatcher() cache.ListerWatcher {
+	driveClient := client.DriveClient()
+	return &cache.ListWatch{
+		ListFunc: func(options metav1.ListOptions) (runtime.Object, error) {
+			return driveClient.List(context.Background(), options)
+		},
+		WatchFunc: func(options metav1.ListOptions) (watch.Interface, error) {
+			return driveClient.Watch(context.Background(), options)
+		},
+	}
+}
+
+func newDriveCache(listerWatcher cache.ListerWatcher) *driveCache {
+	informer := cache.NewSharedIndexInformer(
+		listerWatcher,
+		&types.Drive{},
+		0,
+		cache.Indexers{
+			driveNodeIndex: func(obj interface{}) ([]string, error) {
+				return []string{string(obj.(*types.Drive).GetNodeID())}, nil
+			},
+			driveAccessTierIndex: func(obj interface{}) ([]string, error) {
+				return []string{string(obj.(*types.Drive).GetAccessTier())}, nil
+			},
+			driveStatusIndex: func(obj interface{}) ([]string, error) {
+				return []string{string(obj.(*types.Drive).Status.Status)}, nil
+			},
+			driveVolumeIndex: func(obj interface{}) ([]string, error) {
+				return obj.(*types.Drive).GetVolumes(), nil
+			},
+		},
+	)
+
+	driveCache := &driveCache{
+		informer:     informer,
+		reservations: map[directpvtypes.DriveID]map[string]int64{},
+	}
+
+	informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			driveCache.releaseAdded(obj.(*types.Drive))
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			driveCache.releaseAdded(newObj.(*types.Drive))
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if drive, ok := obj.(*types.Drive); ok {
+				driveCache.mutex.Lock()
+				delete(driveCache.reservations, drive.GetDriveID())
+				driveCache.mutex.Unlock()
+			}
+		},
+	})
+
+	return driveCache
+}
+
+// run starts the informer and waits for the cache to be synced.
+func (driveCache *driveCache) run(ctx context.Context) error {
+	go driveCache.informer.Run(ctx.Done())
+	if !cache.WaitForCacheSync(ctx.Done(), driveCache.informer.HasSynced) {
+		return fmt.Errorf("unable to sync drive cache")
+	}
+	klog.V(3).Infof("Drive cache synced")
+	return nil
+}
+
+// releaseAdded releases reservations of volumes already added to the drive.
+func (driveCache *driveCache) releaseAdded(drive *types.Drive) {
+	driveCache.mutex.Lock()
+	defer driveCache.mutex.Unlock()
+
+	for volume := range driveCache.reservations[drive.GetDriveID()] {
+		if drive.VolumeExist(volume) {
+			delete(driveCache.reservations[drive.GetDriveID()], volume)
+		}
+	}
+	if len(driveCache.reservations[drive.GetDriveID()]) == 0 {
+		delete(driveCache.reservations, drive.GetDriveID())
+	}
+}
+
+// getReserved returns capacity reserved in the drive for volumes not yet
+// added to the drive. The mutex must be held by the caller.
+func (driveCache *driveCache) getReserved(drive *types.Drive) (reserved int64) {
+	for volume, size := range driveCache.reservations[drive.GetDriveID()] {
+		if !drive.VolumeExist(volume) {
+			reserved += size
+		}
+	}
+	return reserved
+}
+
+// reserve reserves size in the drive for the volume. It returns false if
+// the drive does not have enough free capacity after reservations.
+func (driveCache *driveCache) reserve(driveID directpvtypes.DriveID, volume string, size int64) (bool, error) {
+	driveCache.mutex.Lock()
+	defer driveCache.mutex.Unlock()
+
+	drive, err := driveCache.getDrive(driveID)
+	if err != nil {
+		return false, err
+	}
+
+	if _, found := driveCache.reservations[driveID][volume]; found || drive.VolumeExist(volume) {
+		return true, nil
+	}
+
+	if drive.Status.FreeCapacity-driveCache.getReserved(drive) < size {
+		return false, nil
+	}
+
+	if driveCache.reservations[driveID] == nil {
+		driveCache.reservations[driveID] = map[string]int64{}
+	}
+	driveCache.reservations[driveID][volume] = size
+	return true, nil
+}
+
+// release releases the reservation of the volume in the drive.
+func (driveCache *driveCache) release(driveID directpvtypes.DriveID, volume string) {
+	driveCache.mutex.Lock()
+	defer driveCache.mutex.Unlock()
+
+	delete(driveCache.reservations[driveID], volume)
+	if len(driveCache.reservations[driveID]) == 0 {
+		delete(driveCache.reservations, driveID)
+	}
+}
+
+func (driveCache *driveCache) getDrive(driveID directpvtypes.DriveID) (*types.Drive, error) {
+	obj, exists, err := driveCache.informer.GetIndexer().GetByKey(string(driveID))
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, fmt.Errorf("drive %v not found in cache", driveID)
+	}
+	return obj.(*types.Drive), nil
+}
+
+// list returns copies of drives by the index value or all drives if index
+// name is empty. Free and allocated capacity of returned drives are adjusted
+// by reservations.
+func (driveCache *driveCache) list(indexName, indexValue string) ([]types.Drive, error) {
+	var objs []interface{}
+	if indexName == "" {
+		objs = driveCache.informer.GetIndexer().List()
+	} else {
+		var err error
+		if objs, err = driveCache.informer.GetIndexer().ByIndex(indexName, indexValue); err != nil {
+			return nil, err
+		}
+	}
+
+	driveCache.mutex.Lock()
+	defer driveCache.mutex.Unlock()
+
+	drives := make([]types.Drive, 0, len(objs))
+	for _, obj := range objs {
+		drive := obj.(*types.Drive).DeepCopy()
+		reserved := driveCache.getReserved(drive)
+		drive.Status.FreeCapacity -= reserved
+		drive.Status.AllocatedCapacity += reserved
+		drives = append(drives, *drive)
+	}
+	return drives, nil
+}
+
+// listByVolume returns copies of drives having the volume or the volume
+// reservation.
+func (driveCache *driveCache) listByVolume(volume string) ([]types.Drive, error) {
+	drives, err := driveCache.list(driveVolumeIndex, volume)
+	if err != nil || len(drives) != 0 {
+		return drives, err
+	}
+
+	driveCache.mutex.Lock()
+	var driveIDs []directpvtypes.DriveID
+	for driveID, reservations := range driveCache.reservations {
+		if _, found := reservations[volume]; found {
+			driveIDs = append(driveIDs, driveID)
+		}
+	}
+	driveCache.mutex.Unlock()
+
+	for _, driveID := range driveIDs {
+		drive, err := driveCache.getDrive(driveID)
+		if err != nil {
+			return nil, err
+		}
+		drives = append(drives, *drive.DeepCopy())
+	}
+	return drives, nil
+}
@@ -0,0 +1,230 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func newTestDriveCache(t *testing.T, objects ...runtime.Object) (*driveCache, context.CancelFunc) {
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+	ctx, cancelFunc := context.WithCancel(context.Background())
+	driveCache := newDriveCache(newDriveListerWatcher())
+	if err := driveCache.run(ctx); err != nil {
+		cancelFunc()
+		t.Fatalf("unable to run drive cache; %v", err)
+	}
+	return driveCache, cancelFunc
+}
+
+func newCacheTestDrive(driveID directpvtypes.DriveID, nodeID directpvtypes.NodeID, accessTier directpvtypes.AccessTier, driveStatus directpvtypes.DriveStatus, volumes ...string) *types.Drive {
+	drive := types.NewDrive(
+		driveID,
+		types.DriveStatus{
+			Status:        driveStatus,
+			TotalCapacity: 100 * MiB,
+			FreeCapacity:  100 * MiB,
+			Topology:      map[string]string{"node": string(nodeID)},
+		},
+		nodeID,
+		directpvtypes.DriveName(driveID),
+		accessTier,
+	)
+	for _, volume := range volumes {
+		drive.AddVolumeFinalizer(volume)
+	}
+	return drive
+}
+
+func TestDriveCacheList(t *testing.T) {
+	driveCache, cancelFunc := newTestDriveCache(
+		t,
+		newCacheTestDrive("drive-1", "node-1", directpvtypes.AccessTierDefault, directpvtypes.DriveStatusReady, "volume-1"),
+		newCacheTestDrive("drive-2", "node-1", directpvtypes.AccessTierHot, directpvtypes.DriveStatusReady),
+		newCacheTestDrive("drive-3", "node-2", directpvtypes.AccessTierHot, directpvtypes.DriveStatusError, "volume-2", "volume-3"),
+	)
+	defer cancelFunc()
+
+	testCases := []struct {
+		indexName        string
+		indexValue       string
+		expectedDriveIDs []directpvtypes.DriveID
+	}{
+		{driveNodeIndex, "node-1", []directpvtypes.DriveID{"drive-1", "drive-2"}},
+		{driveNodeIndex, "node-2", []directpvtypes.DriveID{"drive-3"}},
+		{driveNodeIndex, "node-3", nil},
+		{driveAccessTierIndex, string(directpvtypes.AccessTierHot), []directpvtypes.DriveID{"drive-2", "drive-3"}},
+		{driveStatusIndex, string(directpvtypes.DriveStatusReady), []directpvtypes.DriveID{"drive-1", "drive-2"}},
+		{driveStatusIndex, string(directpvtypes.DriveStatusError), []directpvtypes.DriveID{"drive-3"}},
+		{driveVolumeIndex, "volume-3", []directpvtypes.DriveID{"drive-3"}},
+		{driveVolumeIndex, "volume-4", nil},
+	}
+
+	for i, testCase := range testCases {
+		drives, err := driveCache.list(testCase.indexName, testCase.indexValue)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		driveIDs := map[directpvtypes.DriveID]struct{}{}
+		for _, drive := range drives {
+			driveIDs[drive.GetDriveID()] = struct{}{}
+		}
+		if len(driveIDs) != len(testCase.expectedDriveIDs) {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedDriveIDs, driveIDs)
+		}
+		for _, driveID := range testCase.expectedDriveIDs {
+			if _, found := driveIDs[driveID]; !found {
+				t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedDriveIDs, driveIDs)
+			}
+		}
+	}
+}
+
+func TestDriveCacheReservation(t *testing.T) {
+	driveCache, cancelFunc := newTestDriveCache(
+		t,
+		newCacheTestDrive("drive-1", "node-1", directpvtypes.AccessTierDefault, directpvtypes.DriveStatusReady),
+	)
+	defer cancelFunc()
+
+	getFreeCapacity := func() int64 {
+		drives, err := driveCache.list(driveNodeIndex, "node-1")
+		if err != nil || len(drives) != 1 {
+			t.Fatalf("unable to list drives; drives: %v, err: %v", drives, err)
+		}
+		return drives[0].Status.FreeCapacity
+	}
+
+	testCases := []struct {
+		volume           string
+		size             int64
+		expectedReserved bool
+		expectedFree     int64
+	}{
+		{"volume-1", 60 * MiB, true, 40 * MiB},
+		{"volume-1", 60 * MiB, true, 40 * MiB},
+		{"volume-2", 50 * MiB, false, 40 * MiB},
+		{"volume-3", 40 * MiB, true, 0},
+	}
+	for i, testCase := range testCases {
+		reserved, err := driveCache.reserve("drive-1", testCase.volume, testCase.size)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if reserved != testCase.expectedReserved {
+			t.Fatalf("case %v: reserved: expected: %v, got: %v", i+1, testCase.expectedReserved, reserved)
+		}
+		if free := getFreeCapacity(); free != testCase.expectedFree {
+			t.Fatalf("case %v: free capacity: expected: %v, got: %v", i+1, testCase.expectedFree, free)
+		}
+	}
+
+	if _, err := driveCache.reserve("drive-x", "volume-4", MiB); err == nil {
+		t.Fatalf("expected error for unknown drive; but succeeded")
+	}
+
+	driveCache.release("drive-1", "volume-3")
+	if free := getFreeCapacity(); free != 40*MiB {
+		t.Fatalf("free capacity: expected: %v, got: %v", 40*MiB, free)
+	}
+
+	// Reservation is released once the volume is seen in the drive.
+	drive, err := client.DriveClient().Get(context.TODO(), "drive-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	drive.AddVolumeFinalizer("volume-1")
+	drive.Status.FreeCapacity -= 60 * MiB
+	drive.Status.AllocatedCapacity += 60 * MiB
+	if _, err = client.DriveClient().Update(context.TODO(), drive, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 50; i++ {
+		driveCache.mutex.Lock()
+		_, found := driveCache.reservations["drive-1"]
+		driveCache.mutex.Unlock()
+		if !found {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if free := getFreeCapacity(); free != 40*MiB {
+		t.Fatalf("free capacity: expected: %v, got: %v", 40*MiB, free)
+	}
+	driveCache.mutex.Lock()
+	defer driveCache.mutex.Unlock()
+	if len(driveCache.reservations) != 0 {
+		t.Fatalf("expected no reservations; got: %v", driveCache.reservations)
+	}
+}
+
+func TestCreateVolumeWithDriveCache(t *testing.T) {
+	driveCache, cancelFunc := newTestDriveCache(
+		t,
+		newCacheTestDrive("drive-1", "node-1", directpvtypes.AccessTierDefault, directpvtypes.DriveStatusReady),
+	)
+	defer cancelFunc()
+
+	server := &Server{driveCache: driveCache}
+	newRequest := func(name string) *csi.CreateVolumeRequest {
+		return &csi.CreateVolumeRequest{
+			Name:          name,
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 60 * MiB},
+			VolumeCapabilities: []*csi.VolumeCapability{
+				{
+					AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+					AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+				},
+			},
+		}
+	}
+
+	if _, err := server.CreateVolume(context.TODO(), newRequest("volume-1")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// Drive update may not yet be seen by the cache, but the reservation
+	// must prevent double booking of free capacity.
+	_, err := server.CreateVolume(context.TODO(), newRequest("volume-2"))
+	if err == nil {
+		t.Fatalf("expected error; but succeeded")
+	}
+	if code := status.Code(err); code != codes.OutOfRange {
+		t.Fatalf("expected: %v, got: %v", codes.OutOfRange, code)
+	}
+
+	// Retried request of the same volume must succeed.
+	if _, err := server.CreateVolume(context.TODO(), newRequest("volume-1")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+}
@@ -0,0 +1,276 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/k8s"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// Drive selection policies.
+const (
+	DriveSelectionPolicyMaxFree       = "max-free"
+	DriveSelectionPolicyBinPack       = "bin-pack"
+	DriveSelectionPolicySpread        = "spread"
+	DriveSelectionPolicyRoundRobin    = "round-robin"
+	DriveSelectionPolicyPodColocation = "prefer-pod-colocation"
+)
+
+// Parameters passed by csi-provisioner with --extra-create-metadata flag.
+const (
+	pvcNameParameter      = "csi.storage.k8s.io/pvc/name"
+	pvcNamespaceParameter = "csi.storage.k8s.io/pvc/namespace"
+)
+
+// DriveSelector is the interface to select a drive for a volume.
+type DriveSelector interface {
+	// SelectDrive returns one of the drives matching the request. Drives
+	// are always non-empty.
+	SelectDrive(ctx context.Context, req *csi.CreateVolumeRequest, drives []types.Drive) (*types.Drive, error)
+}
+
+var (
+	driveSelectorsMutex sync.RWMutex
+	driveSelectors      = map[string]DriveSelector{}
+)
+
+func init() {
+	RegisterDriveSelector(DriveSelectionPolicyMaxFree, &maxFreeSelector{})
+	RegisterDriveSelector(DriveSelectionPolicyBinPack, &binPackSelector{})
+	RegisterDriveSelector(DriveSelectionPolicySpread, &spreadSelector{})
+	RegisterDriveSelector(DriveSelectionPolicyRoundRobin, &roundRobinSelector{})
+	RegisterDriveSelector(DriveSelectionPolicyPodColocation, &podColocationSelector{
+		getDriveIDs: getPodColocatedDriveIDs,
+		fallback:    &maxFreeSelector{},
+	})
+}
+
+// RegisterDriveSelector registers drive selector for the policy. Existing
+// drive selector of the policy is replaced.
+func RegisterDriveSelector(policy string, selector DriveSelector) {
+	driveSelectorsMutex.Lock()
+	defer driveSelectorsMutex.Unlock()
+	driveSelectors[policy] = selector
+}
+
+func getDriveSelector(req *csi.CreateVolumeRequest) (DriveSelector, error) {
+	policy, found := req.GetParameters()[string(directpvtypes.DriveSelectionPolicyLabelKey)]
+	if !found || policy == "" {
+		policy = DriveSelectionPolicyMaxFree
+	}
+
+	driveSelectorsMutex.RLock()
+	defer driveSelectorsMutex.RUnlock()
+
+	selector, found := driveSelectors[policy]
+	if !found {
+		return nil, fmt.Errorf("unknown drive selection policy %v", policy)
+	}
+	return selector, nil
+}
+
+func randomDrive(drives []types.Drive) (*types.Drive, error) {
+	if len(drives) == 1 {
+		return &drives[0], nil
+	}
+
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(drives))))
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "random number generation failed; %v", err)
+	}
+
+	return &drives[n.Int64()], nil
+}
+
+// selectBest returns a random drive among drives having the best value. A
+// value is better than other if less is true.
+func selectBest(drives []types.Drive, value func(drive *types.Drive) int64, less func(a, b int64) bool) (*types.Drive, error) {
+	var bestDrives []types.Drive
+	var bestValue int64
+	for i := range drives {
+		v := value(&drives[i])
+		switch {
+		case len(bestDrives) == 0 || less(v, bestValue):
+			bestValue = v
+			bestDrives = []types.Drive{drives[i]}
+		case v == bestValue:
+			bestDrives = append(bestDrives, drives[i])
+		}
+	}
+	return randomDrive(bestDrives)
+}
+
+// maxFreeSelector selects drive with maximum free capacity.
+type maxFreeSelector struct{}
+
+func (maxFreeSelector) SelectDrive(_ context.Context, _ *csi.CreateVolumeRequest, drives []types.Drive) (*types.Drive, error) {
+	return selectBest(
+		drives,
+		func(drive *types.Drive) int64 { return drive.Status.FreeCapacity },
+		func(a, b int64) bool { return a > b },
+	)
+}
+
+// binPackSelector selects drive with least free capacity which fits the volume.
+type binPackSelector struct{}
+
+func (binPackSelector) SelectDrive(_ context.Context, _ *csi.CreateVolumeRequest, drives []types.Drive) (*types.Drive, error) {
+	return selectBest(
+		drives,
+		func(drive *types.Drive) int64 { return drive.Status.FreeCapacity },
+		func(a, b int64) bool { return a < b },
+	)
+}
+
+// spreadSelector selects drive with least number of volumes.
+type spreadSelector struct{}
+
+func (spreadSelector) SelectDrive(_ context.Context, _ *csi.CreateVolumeRequest, drives []types.Drive) (*types.Drive, error) {
+	return selectBest(
+		drives,
+		func(drive *types.Drive) int64 { return int64(drive.GetVolumeCount()) },
+		func(a, b int64) bool { return a < b },
+	)
+}
+
+// roundRobinSelector selects drives in the order of drive ID. The drive next
+// to previously selected drive is selected.
+type roundRobinSelector struct {
+	mutex     sync.Mutex
+	lastDrive directpvtypes.DriveID
+}
+
+func (selector *roundRobinSelector) SelectDrive(_ context.Context, _ *csi.CreateVolumeRequest, drives []types.Drive) (*types.Drive, error) {
+	sort.Slice(drives, func(i, j int) bool { return drives[i].GetDriveID() < drives[j].GetDriveID() })
+
+	selector.mutex.Lock()
+	defer selector.mutex.Unlock()
+
+	index := sort.Search(len(drives), func(i int) bool { return drives[i].GetDriveID() > selector.lastDrive })
+	if index == len(drives) {
+		index = 0
+	}
+	selector.lastDrive = drives[index].GetDriveID()
+	return &drives[index], nil
+}
+
+// podColocationSelector selects drives already hosting volumes of the pods
+// using the requested PVC. If no such drive found, fallback selector is used.
+type podColocationSelector struct {
+	getDriveIDs func(ctx context.Context, namespace, pvcName string) (map[directpvtypes.DriveID]struct{}, error)
+	fallback    DriveSelector
+}
+
+func (selector podColocationSelector) SelectDrive(ctx context.Context, req *csi.CreateVolumeRequest, drives []types.Drive) (*types.Drive, error) {
+	namespace := req.GetParameters()[pvcNamespaceParameter]
+	pvcName := req.GetParameters()[pvcNameParameter]
+	if namespace == "" || pvcName == "" {
+		return selector.fallback.SelectDrive(ctx, req, drives)
+	}
+
+	driveIDs, err := selector.getDriveIDs(ctx, namespace, pvcName)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to get drives of pods using PVC %v/%v; %v", namespace, pvcName, err)
+	}
+
+	var colocatedDrives []types.Drive
+	for _, drive := range drives {
+		if _, found := driveIDs[drive.GetDriveID()]; found {
+			colocatedDrives = append(colocatedDrives, drive)
+		}
+	}
+	if len(colocatedDrives) == 0 {
+		colocatedDrives = drives
+	}
+
+	return selector.fallback.SelectDrive(ctx, req, colocatedDrives)
+}
+
+// getPodColocatedDriveIDs returns IDs of drives hosting other volumes of the
+// pods using the PVC. As the pods wait for the PVC to be provisioned, only
+// pending pods are listed.
+func getPodColocatedDriveIDs(ctx context.Context, namespace, pvcName string) (map[directpvtypes.DriveID]struct{}, error) {
+	podList, err := k8s.KubeClient().CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("status.phase", string(corev1.PodPending)).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	claimNames := map[string]struct{}{}
+	for _, pod := range podList.Items {
+		var names []string
+		found := false
+		for _, volume := range pod.Spec.Volumes {
+			if volume.PersistentVolumeClaim == nil {
+				continue
+			}
+			if volume.PersistentVolumeClaim.ClaimName == pvcName {
+				found = true
+			} else {
+				names = append(names, volume.PersistentVolumeClaim.ClaimName)
+			}
+		}
+		if found {
+			for _, name := range names {
+				claimNames[name] = struct{}{}
+			}
+		}
+	}
+
+	driveIDs := map[directpvtypes.DriveID]struct{}{}
+	for claimName := range claimNames {
+		pvc, err := k8s.KubeClient().CoreV1().PersistentVolumeClaims(namespace).Get(ctx, claimName, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		if pvc.Spec.VolumeName == "" {
+			continue
+		}
+
+		volume, err := client.VolumeClient().Get(ctx, pvc.Spec.VolumeName, metav1.GetOptions{})
+		if err != nil {
+			if errors.IsNotFound(err) {
+				// Volume is not provisioned by DirectPV.
+				continue
+			}
+			return nil, err
+		}
+		driveIDs[volume.GetDriveID()] = struct{}{}
+	}
+
+	return driveIDs, nil
+}
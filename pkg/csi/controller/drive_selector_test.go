@@ -0,0 +1,275 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/k8s"
+	"github.com/minio/directpv/pkg/types"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func newSelectorTestDrive(driveID directpvtypes.DriveID, freeCapacity int64, volumes ...string) types.Drive {
+	drive := types.NewDrive(
+		driveID,
+		types.DriveStatus{
+			Status:       directpvtypes.DriveStatusReady,
+			FreeCapacity: freeCapacity,
+		},
+		"node-1",
+		directpvtypes.DriveName(driveID),
+		directpvtypes.AccessTierDefault,
+	)
+	for _, volume := range volumes {
+		drive.AddVolumeFinalizer(volume)
+	}
+	return *drive
+}
+
+func TestGetDriveSelector(t *testing.T) {
+	policyKey := string(directpvtypes.DriveSelectionPolicyLabelKey)
+	testCases := []struct {
+		parameters    map[string]string
+		expectedType  DriveSelector
+		expectedError bool
+	}{
+		{nil, &maxFreeSelector{}, false},
+		{map[string]string{policyKey: ""}, &maxFreeSelector{}, false},
+		{map[string]string{policyKey: DriveSelectionPolicyMaxFree}, &maxFreeSelector{}, false},
+		{map[string]string{policyKey: DriveSelectionPolicyBinPack}, &binPackSelector{}, false},
+		{map[string]string{policyKey: DriveSelectionPolicySpread}, &spreadSelector{}, false},
+		{map[string]string{policyKey: DriveSelectionPolicyRoundRobin}, &roundRobinSelector{}, false},
+		{map[string]string{policyKey: DriveSelectionPolicyPodColocation}, &podColocationSelector{}, false},
+		{map[string]string{policyKey: "unknown"}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		selector, err := getDriveSelector(&csi.CreateVolumeRequest{Parameters: testCase.parameters})
+		if testCase.expectedError {
+			if err == nil {
+				t.Fatalf("case %v: expected error; but succeeded", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+
+		if reflect.TypeOf(selector) != reflect.TypeOf(testCase.expectedType) {
+			t.Fatalf("case %v: expected: %T, got: %T", i+1, testCase.expectedType, selector)
+		}
+	}
+}
+
+func TestDriveSelectors(t *testing.T) {
+	testCases := []struct {
+		selector        DriveSelector
+		drives          []types.Drive
+		expectedDriveID directpvtypes.DriveID
+	}{
+		{
+			&maxFreeSelector{},
+			[]types.Drive{newSelectorTestDrive("drive-1", 10*GiB), newSelectorTestDrive("drive-2", 30*GiB), newSelectorTestDrive("drive-3", 20*GiB)},
+			"drive-2",
+		},
+		{
+			&binPackSelector{},
+			[]types.Drive{newSelectorTestDrive("drive-1", 10*GiB), newSelectorTestDrive("drive-2", 30*GiB), newSelectorTestDrive("drive-3", 20*GiB)},
+			"drive-1",
+		},
+		{
+			&binPackSelector{},
+			[]types.Drive{newSelectorTestDrive("drive-1", 5*GiB), newSelectorTestDrive("drive-2", 2*GiB)},
+			"drive-2",
+		},
+		{
+			&spreadSelector{},
+			[]types.Drive{
+				newSelectorTestDrive("drive-1", 30*GiB, "volume-1", "volume-2"),
+				newSelectorTestDrive("drive-2", 10*GiB, "volume-3"),
+				newSelectorTestDrive("drive-3", 20*GiB, "volume-4", "volume-5", "volume-6"),
+			},
+			"drive-2",
+		},
+		{
+			&spreadSelector{},
+			[]types.Drive{
+				newSelectorTestDrive("drive-1", 30*GiB, "volume-1"),
+				newSelectorTestDrive("drive-2", 10*GiB),
+			},
+			"drive-2",
+		},
+		{
+			&roundRobinSelector{},
+			[]types.Drive{newSelectorTestDrive("drive-2", 10*GiB), newSelectorTestDrive("drive-1", 10*GiB)},
+			"drive-1",
+		},
+		{
+			&roundRobinSelector{lastDrive: "drive-1"},
+			[]types.Drive{newSelectorTestDrive("drive-3", 10*GiB), newSelectorTestDrive("drive-1", 10*GiB), newSelectorTestDrive("drive-2", 10*GiB)},
+			"drive-2",
+		},
+		{
+			&roundRobinSelector{lastDrive: "drive-3"},
+			[]types.Drive{newSelectorTestDrive("drive-3", 10*GiB), newSelectorTestDrive("drive-1", 10*GiB), newSelectorTestDrive("drive-2", 10*GiB)},
+			"drive-1",
+		},
+		{
+			&roundRobinSelector{lastDrive: "drive-2"},
+			[]types.Drive{newSelectorTestDrive("drive-3", 10*GiB), newSelectorTestDrive("drive-1", 10*GiB)},
+			"drive-3",
+		},
+	}
+
+	for i, testCase := range testCases {
+		drive, err := testCase.selector.SelectDrive(context.TODO(), &csi.CreateVolumeRequest{}, testCase.drives)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if drive.GetDriveID() != testCase.expectedDriveID {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedDriveID, drive.GetDriveID())
+		}
+	}
+}
+
+func TestRoundRobinSelector(t *testing.T) {
+	selector := &roundRobinSelector{}
+	expectedDriveIDs := []directpvtypes.DriveID{"drive-1", "drive-2", "drive-3", "drive-1", "drive-2"}
+	for i, expectedDriveID := range expectedDriveIDs {
+		drives := []types.Drive{newSelectorTestDrive("drive-3", 10*GiB), newSelectorTestDrive("drive-2", 10*GiB), newSelectorTestDrive("drive-1", 10*GiB)}
+		drive, err := selector.SelectDrive(context.TODO(), &csi.CreateVolumeRequest{}, drives)
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if drive.GetDriveID() != expectedDriveID {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, expectedDriveID, drive.GetDriveID())
+		}
+	}
+}
+
+func TestPodColocationSelector(t *testing.T) {
+	getDriveIDs := func(_ context.Context, namespace, pvcName string) (map[directpvtypes.DriveID]struct{}, error) {
+		switch {
+		case namespace == "error":
+			return nil, errors.New("error")
+		case pvcName == "pvc-1":
+			return map[directpvtypes.DriveID]struct{}{"drive-1": {}, "drive-3": {}}, nil
+		case pvcName == "pvc-2":
+			return map[directpvtypes.DriveID]struct{}{"drive-4": {}}, nil
+		}
+		return nil, nil
+	}
+	selector := &podColocationSelector{getDriveIDs: getDriveIDs, fallback: &maxFreeSelector{}}
+
+	newParameters := func(namespace, pvcName string) map[string]string {
+		return map[string]string{pvcNamespaceParameter: namespace, pvcNameParameter: pvcName}
+	}
+
+	testCases := []struct {
+		parameters      map[string]string
+		expectedDriveID directpvtypes.DriveID
+		expectedError   bool
+	}{
+		{nil, "drive-2", false},
+		{newParameters("default", "pvc-1"), "drive-3", false},
+		{newParameters("default", "pvc-2"), "drive-2", false},
+		{newParameters("default", "pvc-3"), "drive-2", false},
+		{newParameters("error", "pvc-1"), "", true},
+	}
+
+	for i, testCase := range testCases {
+		drives := []types.Drive{
+			newSelectorTestDrive("drive-1", 10*GiB),
+			newSelectorTestDrive("drive-2", 30*GiB),
+			newSelectorTestDrive("drive-3", 20*GiB),
+		}
+		drive, err := selector.SelectDrive(context.TODO(), &csi.CreateVolumeRequest{Parameters: testCase.parameters}, drives)
+		if testCase.expectedError {
+			if err == nil {
+				t.Fatalf("case %v: expected error; but succeeded", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if drive.GetDriveID() != testCase.expectedDriveID {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedDriveID, drive.GetDriveID())
+		}
+	}
+}
+
+func TestGetPodColocatedDriveIDs(t *testing.T) {
+	newPod := func(name string, claimNames ...string) *corev1.Pod {
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Status:     corev1.PodStatus{Phase: corev1.PodPending},
+		}
+		for _, claimName := range claimNames {
+			pod.Spec.Volumes = append(pod.Spec.Volumes, corev1.Volume{
+				Name: claimName,
+				VolumeSource: corev1.VolumeSource{
+					PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+				},
+			})
+		}
+		return pod
+	}
+	newPVC := func(name, volumeName string) *corev1.PersistentVolumeClaim {
+		return &corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec:       corev1.PersistentVolumeClaimSpec{VolumeName: volumeName},
+		}
+	}
+
+	k8s.SetKubeInterface(kubernetesfake.NewSimpleClientset(
+		newPod("pod-1", "pvc-1", "pvc-2", "pvc-3"),
+		newPod("pod-2", "pvc-4"),
+		newPod("pod-3", "pvc-1", "pvc-5"),
+		newPVC("pvc-2", "volume-2"),
+		newPVC("pvc-3", ""),
+		newPVC("pvc-4", "volume-4"),
+		newPVC("pvc-5", "volume-5"),
+	))
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset([]runtime.Object{
+		types.NewVolume("volume-2", "volume-2", "node-1", "drive-2", "sdb", 10*GiB),
+		types.NewVolume("volume-4", "volume-4", "node-1", "drive-4", "sdd", 10*GiB),
+	}...))
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+	driveIDs, err := getPodColocatedDriveIDs(context.TODO(), "default", "pvc-1")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(driveIDs) != 1 {
+		t.Fatalf("expected: 1 drive, got: %v", driveIDs)
+	}
+	if _, found := driveIDs["drive-2"]; !found {
+		t.Fatalf("expected: drive-2, got: %v", driveIDs)
+	}
+}
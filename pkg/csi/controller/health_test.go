@@ -0,0 +1,62 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestControllerGetVolume(t *testing.T) {
+	errorDrive := newReserveTestDrive("drive-1", 90*MiB, "volume-1")
+	errorDrive.Status.Status = directpvtypes.DriveStatusError
+	errorDrive.SetIOErrorCondition()
+
+	testCases := []struct {
+		objects          []runtime.Object
+		expectedCode     codes.Code
+		expectedAbnormal bool
+	}{
+		{nil, codes.NotFound, false},
+		{[]runtime.Object{newReserveTestDrive("drive-1", 90*MiB, "volume-1"), newModifyTestVolume(false)}, codes.OK, false},
+		{[]runtime.Object{errorDrive, newModifyTestVolume(false)}, codes.OK, true},
+		{[]runtime.Object{newModifyTestVolume(false)}, codes.OK, true},
+	}
+
+	for i, testCase := range testCases {
+		setupReserveTest(0, 0, testCase.objects...)
+		response, err := NewServer().ControllerGetVolume(context.TODO(), &csi.ControllerGetVolumeRequest{VolumeId: "volume-1"})
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedCode, err)
+		}
+		if err != nil {
+			continue
+		}
+		if response.GetVolume().GetCapacityBytes() != 10*MiB {
+			t.Fatalf("case %v: capacity: expected: %v, got: %v", i+1, 10*MiB, response.GetVolume().GetCapacityBytes())
+		}
+		if abnormal := response.GetStatus().GetVolumeCondition().GetAbnormal(); abnormal != testCase.expectedAbnormal {
+			t.Fatalf("case %v: abnormal: expected: %v, got: %v", i+1, testCase.expectedAbnormal, abnormal)
+		}
+	}
+}
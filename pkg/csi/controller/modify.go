@@ -0,0 +1,294 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// validateMutableParameters checks whether the parameters are modifiable by
+// ControllerModifyVolume i.e. access-tier, volume claim ID and custom drive
+// labels.
+func validateMutableParameters(parameters map[string]string) error {
+	for key, value := range parameters {
+		switch {
+		case key == string(directpvtypes.AccessTierLabelKey):
+			if _, err := directpvtypes.StringsToAccessTiers(value); err != nil {
+				return status.Errorf(codes.InvalidArgument, "unknown access-tier %v; %v", value, err)
+			}
+		case key == string(directpvtypes.VolumeClaimIDLabelKey):
+			if !volumeClaimIDRegex.MatchString(value) {
+				return status.Errorf(codes.InvalidArgument, "invalid volume claim ID %v", value)
+			}
+		case strings.HasPrefix(key, consts.GroupName+"/") && !directpvtypes.LabelKey(key).IsReserved():
+			// Custom drive label.
+		default:
+			return status.Errorf(codes.InvalidArgument, "parameter %v is not modifiable", key)
+		}
+	}
+	return nil
+}
+
+// matchCurrentDrive checks whether the drive of the volume satisfies the
+// mutable parameters.
+func matchCurrentDrive(drive *types.Drive, volume *types.Volume, parameters map[string]string) bool {
+	for key, value := range parameters {
+		switch key {
+		case string(directpvtypes.AccessTierLabelKey):
+			accessTiers, _ := directpvtypes.StringsToAccessTiers(value)
+			if len(accessTiers) > 0 && drive.GetAccessTier() != accessTiers[0] {
+				return false
+			}
+		case string(directpvtypes.VolumeClaimIDLabelKey):
+			if value != volume.GetClaimID() && drive.HasVolumeClaimID(value) {
+				return false
+			}
+		default:
+			if drive.GetLabels()[key] != value {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// updateVolumeClaimID replaces old volume claim ID by new volume claim ID in
+// the drive.
+func updateVolumeClaimID(ctx context.Context, driveID directpvtypes.DriveID, oldClaimID, newClaimID string) error {
+	updateFunc := func() error {
+		drive, err := client.DriveClient().Get(ctx, string(driveID), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return err
+		}
+
+		if newClaimID != oldClaimID && drive.HasVolumeClaimID(newClaimID) {
+			return errDriveNotReservable
+		}
+		drive.RemoveVolumeClaimID(oldClaimID)
+		drive.SetVolumeClaimID(newClaimID)
+
+		_, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, updateFunc)
+}
+
+// removeVolumeClaimID removes the volume claim ID from the drive.
+func removeVolumeClaimID(ctx context.Context, driveID directpvtypes.DriveID, volumeClaimID string) error {
+	if volumeClaimID == "" {
+		return nil
+	}
+
+	updateFunc := func() error {
+		drive, err := client.DriveClient().Get(ctx, string(driveID), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return err
+		}
+
+		if !drive.HasVolumeClaimID(volumeClaimID) {
+			return nil
+		}
+		drive.RemoveVolumeClaimID(volumeClaimID)
+
+		_, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, updateFunc)
+}
+
+// reserveMoveDrive reserves the volume size in the destination drive and sets
+// the drive to moving state. The volume is moved to the drive by the node
+// server of the drive.
+func reserveMoveDrive(ctx context.Context, driveID directpvtypes.DriveID, volume *types.Volume, volumeClaimID string) error {
+	updateFunc := func() error {
+		drive, err := client.DriveClient().Get(ctx, string(driveID), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case !drive.GetDeletionTimestamp().IsZero(),
+			drive.Status.Status != directpvtypes.DriveStatusReady,
+			drive.IsUnschedulable(),
+			drive.Status.FreeCapacity < volume.Status.TotalCapacity,
+			drive.HasVolumeClaimID(volumeClaimID):
+			return errDriveNotReservable
+		}
+
+		drive.AddVolumeFinalizer(volume.Name)
+		drive.SetVolumeClaimID(volumeClaimID)
+		drive.Status.FreeCapacity -= volume.Status.TotalCapacity
+		drive.Status.AllocatedCapacity += volume.Status.TotalCapacity
+		drive.Status.Status = directpvtypes.DriveStatusMoving
+
+		_, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, updateFunc)
+}
+
+// selectMoveDrive selects a drive having maximum free capacity on the node of
+// the volume matching the mutable parameters. Aborted error is returned if
+// the volume is already being moved.
+func (c *Server) selectMoveDrive(ctx context.Context, volume *types.Volume, parameters map[string]string) (*types.Drive, error) {
+	drives, err := c.listDrives(ctx, driveNodeIndex, string(volume.GetNodeID()))
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	req := &csi.CreateVolumeRequest{
+		Name:          volume.Name,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: volume.Status.TotalCapacity},
+		Parameters:    parameters,
+	}
+
+	var selected *types.Drive
+	rejections := map[driveRejection][]string{}
+	for i := range drives {
+		if drives[i].GetNodeID() != volume.GetNodeID() || drives[i].GetDriveID() == volume.GetDriveID() {
+			continue
+		}
+		if drives[i].VolumeExist(volume.Name) {
+			return nil, status.Errorf(codes.Aborted, "volume %v is being moved to drive %v", volume.Name, drives[i].GetDriveID())
+		}
+		if reason := getDriveRejection(&drives[i], req); reason != "" {
+			addDriveRejection(rejections, &drives[i], reason)
+			continue
+		}
+		if selected == nil || drives[i].Status.FreeCapacity > selected.Status.FreeCapacity {
+			selected = &drives[i]
+		}
+	}
+
+	if selected == nil {
+		return nil, status.Errorf(
+			codes.ResourceExhausted,
+			"no drive found on node %v to move volume %v; rejected drives: %v",
+			volume.GetNodeID(), volume.Name, summarizeDriveRejections(rejections),
+		)
+	}
+	return selected, nil
+}
+
+// ControllerModifyVolume modifies mutable parameters of a volume.
+// reference: https://github.com/container-storage-interface/spec/blob/master/spec.md#controllermodifyvolume
+func (c *Server) ControllerModifyVolume(ctx context.Context, req *csi.ControllerModifyVolumeRequest) (*csi.ControllerModifyVolumeResponse, error) {
+	volumeID := req.GetVolumeId()
+	if volumeID == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty volume ID in the request")
+	}
+
+	parameters := req.GetMutableParameters()
+	if err := validateMutableParameters(parameters); err != nil {
+		return nil, err
+	}
+
+	volume, err := client.VolumeClient().Get(ctx, volumeID, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, status.Errorf(codes.NotFound, "volume %v not found", volumeID)
+		}
+		return nil, status.Errorf(codes.Internal, "unable to get volume %v; %v", volumeID, err)
+	}
+
+	drive, err := client.DriveClient().Get(ctx, string(volume.GetDriveID()), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to get drive %v of volume %v; %v", volume.GetDriveID(), volumeID, err)
+	}
+
+	oldClaimID := volume.GetClaimID()
+	newClaimID := oldClaimID
+	if value, found := parameters[string(directpvtypes.VolumeClaimIDLabelKey)]; found {
+		newClaimID = value
+	}
+
+	if matchCurrentDrive(drive, volume, parameters) {
+		if newClaimID == oldClaimID {
+			return &csi.ControllerModifyVolumeResponse{}, nil
+		}
+
+		if err := updateVolumeClaimID(ctx, drive.GetDriveID(), oldClaimID, newClaimID); err != nil {
+			if errors.Is(err, errDriveNotReservable) {
+				return nil, status.Errorf(codes.Aborted, "volume claim ID %v is concurrently added to drive %v", newClaimID, drive.GetDriveID())
+			}
+			return nil, status.Errorf(codes.Internal, "unable to update volume claim ID in drive %v; %v", drive.GetDriveID(), err)
+		}
+	} else {
+		switch {
+		case volume.IsPublished():
+			return nil, status.Errorf(codes.FailedPrecondition, "published volume %v cannot be moved to another drive", volumeID)
+		case volume.IsBlock():
+			return nil, status.Errorf(codes.FailedPrecondition, "block volume %v cannot be moved to another drive", volumeID)
+		}
+
+		destDrive, err := c.selectMoveDrive(ctx, volume, parameters)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := reserveMoveDrive(ctx, destDrive.GetDriveID(), volume, newClaimID); err != nil {
+			if errors.Is(err, errDriveNotReservable) {
+				return nil, status.Errorf(codes.Aborted, "drive %v is concurrently modified for volume %v move", destDrive.GetDriveID(), volumeID)
+			}
+			return nil, status.Errorf(codes.Internal, "unable to reserve drive %v for volume %v move; %v", destDrive.GetDriveID(), volumeID, err)
+		}
+
+		// Source drive is released by the node server after the volume data is moved.
+		if err := removeVolumeClaimID(ctx, drive.GetDriveID(), oldClaimID); err != nil {
+			klog.ErrorS(err, "unable to remove volume claim ID", "drive", drive.GetDriveID(), "volume", volumeID)
+		}
+
+		klog.V(3).InfoS("Volume is being moved",
+			"volume", volumeID,
+			"source", drive.GetDriveID(),
+			"destination", destDrive.GetDriveID())
+	}
+
+	if newClaimID != oldClaimID {
+		updateFunc := func() error {
+			volume, err := client.VolumeClient().Get(ctx, volumeID, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+			if err != nil {
+				return err
+			}
+			volume.SetClaimID(newClaimID)
+			_, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{TypeMeta: types.NewVolumeTypeMeta()})
+			return err
+		}
+		if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to update volume claim ID of volume %v; %v", volumeID, err)
+		}
+	}
+
+	return &csi.ControllerModifyVolumeResponse{}, nil
+}
@@ -0,0 +1,130 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testClaimID = "a1b2c3d4-e5f6-a7b8-c9d0-e1f2a3b4c5d6"
+
+func newModifyTestVolume(published bool) *types.Volume {
+	volume := types.NewVolume("volume-1", "fsuuid-1", "node-1", "drive-1", "drive-1", 10*MiB)
+	if published {
+		volume.Status.TargetPath = "/path/to/target"
+	}
+	return volume
+}
+
+func TestControllerModifyVolume(t *testing.T) {
+	hotDrive := newReserveTestDrive("drive-2", 50*MiB)
+	hotDrive.SetLabel(directpvtypes.AccessTierLabelKey, directpvtypes.LabelValue(directpvtypes.AccessTierHot))
+
+	testCases := []struct {
+		published    bool
+		parameters   map[string]string
+		expectedCode codes.Code
+	}{
+		{false, map[string]string{string(directpvtypes.BlockVolumeLabelKey): "drive"}, codes.InvalidArgument},
+		{false, map[string]string{"tier": "hot"}, codes.InvalidArgument},
+		{false, map[string]string{string(directpvtypes.AccessTierLabelKey): "cold"}, codes.ResourceExhausted},
+		{false, map[string]string{consts.GroupName + "/disk-type": "nvme"}, codes.ResourceExhausted},
+		{true, map[string]string{string(directpvtypes.AccessTierLabelKey): "hot"}, codes.FailedPrecondition},
+	}
+
+	for i, testCase := range testCases {
+		setupReserveTest(0, 0, newReserveTestDrive("drive-1", 90*MiB, "volume-1"), hotDrive.DeepCopy(), newModifyTestVolume(testCase.published))
+		_, err := NewServer().ControllerModifyVolume(context.TODO(), &csi.ControllerModifyVolumeRequest{
+			VolumeId:          "volume-1",
+			MutableParameters: testCase.parameters,
+		})
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedCode, err)
+		}
+	}
+}
+
+func TestControllerModifyVolumeClaimID(t *testing.T) {
+	setupReserveTest(0, 0, newReserveTestDrive("drive-1", 90*MiB, "volume-1"), newModifyTestVolume(false))
+	_, err := NewServer().ControllerModifyVolume(context.TODO(), &csi.ControllerModifyVolumeRequest{
+		VolumeId:          "volume-1",
+		MutableParameters: map[string]string{string(directpvtypes.VolumeClaimIDLabelKey): testClaimID},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	drive := getTestDrive(t, "drive-1")
+	if !drive.HasVolumeClaimID(testClaimID) {
+		t.Fatalf("volume claim ID %v not found in drive", testClaimID)
+	}
+	if !drive.VolumeExist("volume-1") || drive.Status.FreeCapacity != 90*MiB {
+		t.Fatalf("unexpected drive reservation; volumes: %v, free capacity: %v", drive.GetVolumes(), drive.Status.FreeCapacity)
+	}
+
+	volume, err := client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get volume; %v", err)
+	}
+	if volume.GetClaimID() != testClaimID {
+		t.Fatalf("claim ID: expected: %v, got: %v", testClaimID, volume.GetClaimID())
+	}
+}
+
+func TestControllerModifyVolumeMove(t *testing.T) {
+	hotDrive := newReserveTestDrive("drive-2", 50*MiB)
+	hotDrive.SetLabel(directpvtypes.AccessTierLabelKey, directpvtypes.LabelValue(directpvtypes.AccessTierHot))
+	setupReserveTest(0, 0, newReserveTestDrive("drive-1", 90*MiB, "volume-1"), hotDrive, newModifyTestVolume(false))
+
+	request := &csi.ControllerModifyVolumeRequest{
+		VolumeId:          "volume-1",
+		MutableParameters: map[string]string{string(directpvtypes.AccessTierLabelKey): "hot"},
+	}
+	if _, err := NewServer().ControllerModifyVolume(context.TODO(), request); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	srcDrive := getTestDrive(t, "drive-1")
+	// Source drive is released by the node server after moving volume data.
+	if !srcDrive.VolumeExist("volume-1") || srcDrive.Status.FreeCapacity != 90*MiB {
+		t.Fatalf("source drive: unexpected reservation; volumes: %v, free capacity: %v", srcDrive.GetVolumes(), srcDrive.Status.FreeCapacity)
+	}
+
+	destDrive := getTestDrive(t, "drive-2")
+	if !destDrive.VolumeExist("volume-1") || destDrive.Status.FreeCapacity != 40*MiB {
+		t.Fatalf("destination drive: unexpected reservation; volumes: %v, free capacity: %v", destDrive.GetVolumes(), destDrive.Status.FreeCapacity)
+	}
+	if destDrive.Status.Status != directpvtypes.DriveStatusMoving {
+		t.Fatalf("destination drive: status: expected: %v, got: %v", directpvtypes.DriveStatusMoving, destDrive.Status.Status)
+	}
+
+	// Retry before the volume is moved by the node server.
+	_, err := NewServer().ControllerModifyVolume(context.TODO(), request)
+	if code := status.Code(err); code != codes.Aborted {
+		t.Fatalf("expected: %v, got: %v", codes.Aborted, err)
+	}
+}
@@ -0,0 +1,194 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// maxReserveRetries is the maximum number of drive selections done when
+// concurrent requests consume free capacity of the selected drive.
+const maxReserveRetries = 3
+
+var errDriveNotReservable = errors.New("drive does not satisfy the request")
+
+// reserveDrive adds the volume to the drive and reserves the size. On update
+// conflict, the drive is re-read and re-checked whether it still satisfies
+// the request. It returns false if the volume is already in the drive.
+func reserveDrive(ctx context.Context, driveID directpvtypes.DriveID, volume, volumeClaimID string, size int64) (drive *types.Drive, added bool, err error) {
+	updateFunc := func() (err error) {
+		drive, err = client.DriveClient().Get(ctx, string(driveID), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return err
+		}
+
+		if drive.VolumeExist(volume) {
+			added = false
+			return nil
+		}
+
+		switch {
+		case !drive.GetDeletionTimestamp().IsZero(),
+			drive.Status.Status != directpvtypes.DriveStatusReady,
+			drive.IsUnschedulable(),
+			drive.Status.FreeCapacity < size,
+			drive.HasVolumeClaimID(volumeClaimID):
+			return errDriveNotReservable
+		}
+
+		drive.AddVolumeFinalizer(volume)
+		drive.SetVolumeClaimID(volumeClaimID)
+		drive.Status.FreeCapacity -= size
+		drive.Status.AllocatedCapacity += size
+
+		drive, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		added = err == nil
+		return err
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, updateFunc)
+	return drive, added, err
+}
+
+// unreserveDrive removes the volume from the drive and releases the size.
+func unreserveDrive(ctx context.Context, driveID directpvtypes.DriveID, volume, volumeClaimID string, size int64) error {
+	updateFunc := func() error {
+		drive, err := client.DriveClient().Get(ctx, string(driveID), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return err
+		}
+
+		if !drive.RemoveVolumeFinalizer(volume) {
+			return nil
+		}
+		drive.RemoveVolumeClaimID(volumeClaimID)
+		drive.Status.FreeCapacity += size
+		drive.Status.AllocatedCapacity -= size
+
+		_, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, updateFunc)
+}
+
+// expandDrive reserves additional size in the drive of the volume. On update
+// conflict, the drive is re-read and free capacity is re-checked.
+func expandDrive(ctx context.Context, volume *types.Volume, size int64) (drive *types.Drive, err error) {
+	updateFunc := func() (err error) {
+		drive, err = client.DriveClient().Get(ctx, string(volume.GetDriveID()), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return err
+		}
+
+		if size > drive.Status.FreeCapacity {
+			return errDriveNotReservable
+		}
+		drive.Status.FreeCapacity -= size
+		drive.Status.AllocatedCapacity += size
+
+		_, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+
+	switch err = retry.RetryOnConflict(retry.DefaultRetry, updateFunc); {
+	case err == nil:
+		return drive, nil
+	case errors.Is(err, errDriveNotReservable):
+		return nil, status.Errorf(
+			codes.OutOfRange,
+			"required bytes %v is greater than free capacity of drive %v for volume %v expansion",
+			volume.Status.TotalCapacity+size, volume.GetDriveID(), volume.Name,
+		)
+	default:
+		return nil, status.Errorf(
+			codes.Internal,
+			"unable to update reserved drive %v for volume %v expansion; %v",
+			volume.GetDriveID(), volume.Name, err,
+		)
+	}
+}
+
+// selectAndReserveDrive selects a drive for the volume and reserves the size
+// in the drive. If the selected drive no longer satisfies the request due to
+// concurrent requests, another drive is selected.
+func (c *Server) selectAndReserveDrive(ctx context.Context, req *csi.CreateVolumeRequest, source *cloneSource, volumeClaimID string) (drive *types.Drive, size int64, added bool, err error) {
+	name := req.GetName()
+	for i := 0; i < maxReserveRetries; i++ {
+		if source != nil {
+			drive, err = c.selectCloneDrive(ctx, req, source)
+		} else {
+			drive, err = c.selectDrive(ctx, req)
+		}
+		if err != nil {
+			return nil, 0, false, err
+		}
+
+		klog.V(4).InfoS("Selected drive",
+			"drive", drive.GetDriveID(),
+			"node", drive.GetNodeID(),
+			"name", drive.GetDriveName(),
+			"volume", name)
+
+		size = drive.Status.FreeCapacity
+		if req.GetCapacityRange() != nil {
+			size = req.GetCapacityRange().GetRequiredBytes()
+		}
+
+		if c.driveCache != nil {
+			reserved, err := c.driveCache.reserve(drive.GetDriveID(), name, size)
+			if err != nil {
+				return nil, 0, false, status.Errorf(codes.Internal, "unable to reserve drive %v for volume %v; %v", drive.GetDriveID(), name, err)
+			}
+			if !reserved {
+				continue
+			}
+		}
+
+		klog.V(4).InfoS("Reserving drive",
+			"drive", drive.GetDriveID(),
+			"node", drive.GetNodeID(),
+			"name", drive.GetDriveName(),
+			"volume", name)
+
+		driveID := drive.GetDriveID()
+		if drive, added, err = reserveDrive(ctx, driveID, name, volumeClaimID, size); err == nil {
+			return drive, size, added, nil
+		}
+
+		if c.driveCache != nil {
+			c.driveCache.release(driveID, name)
+		}
+
+		if !errors.Is(err, errDriveNotReservable) {
+			return nil, 0, false, status.Errorf(codes.Internal, "unable to update reserved drive %v for volume %v; %v", driveID, name, err)
+		}
+	}
+
+	return nil, 0, false, status.Errorf(codes.Aborted, "unable to reserve drive for volume %v; concurrent requests consumed free capacity", name)
+}
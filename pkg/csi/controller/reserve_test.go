@@ -0,0 +1,211 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var driveGVR = schema.GroupVersionResource{Group: consts.GroupName, Version: consts.LatestAPIVersion, Resource: consts.DriveResource}
+
+func newReserveTestDrive(driveID directpvtypes.DriveID, freeCapacity int64, volumes ...string) *types.Drive {
+	drive := types.NewDrive(
+		driveID,
+		types.DriveStatus{
+			Status:        directpvtypes.DriveStatusReady,
+			TotalCapacity: 100 * MiB,
+			FreeCapacity:  freeCapacity,
+		},
+		"node-1",
+		directpvtypes.DriveName(driveID),
+		directpvtypes.AccessTierDefault,
+	)
+	for _, volume := range volumes {
+		drive.AddVolumeFinalizer(volume)
+	}
+	return drive
+}
+
+// setupReserveTest sets up fake clients. Each of first conflicts drive
+// updates fails with conflict error after setting free capacity of the drive
+// to concurrentFree to simulate a concurrent reservation.
+func setupReserveTest(conflicts int, concurrentFree int64, objects ...runtime.Object) *clientsetfake.Clientset {
+	fakeClientset := clientsetfake.NewSimpleClientset(objects...)
+	fakeClientset.PrependReactor("update", consts.DriveResource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		if conflicts == 0 {
+			return false, nil, nil
+		}
+		conflicts--
+
+		drive := action.(k8stesting.UpdateAction).GetObject().(*types.Drive)
+		obj, err := fakeClientset.Tracker().Get(driveGVR, "", drive.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		current := obj.(*types.Drive)
+		current.Status.FreeCapacity = concurrentFree
+		if err := fakeClientset.Tracker().Update(driveGVR, current, ""); err != nil {
+			return true, nil, err
+		}
+		return true, nil, apierrors.NewConflict(driveGVR.GroupResource(), drive.Name, errors.New("object has been modified"))
+	})
+
+	clientset := types.NewExtFakeClientset(fakeClientset)
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+	return fakeClientset
+}
+
+func getTestDrive(t *testing.T, driveID string) *types.Drive {
+	drive, err := client.DriveClient().Get(context.TODO(), driveID, metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get drive %v; %v", driveID, err)
+	}
+	return drive
+}
+
+func TestReserveDrive(t *testing.T) {
+	testCases := []struct {
+		drive          *types.Drive
+		conflicts      int
+		concurrentFree int64
+		size           int64
+		expectedAdded  bool
+		expectedFree   int64
+		expectedErr    error
+	}{
+		{newReserveTestDrive("drive-1", 50*MiB), 0, 0, 30 * MiB, true, 20 * MiB, nil},
+		{newReserveTestDrive("drive-1", 50*MiB, "volume-1"), 0, 0, 30 * MiB, false, 50 * MiB, nil},
+		{newReserveTestDrive("drive-1", 20*MiB), 0, 0, 30 * MiB, false, 20 * MiB, errDriveNotReservable},
+		{newReserveTestDrive("drive-1", 50*MiB), 2, 40 * MiB, 30 * MiB, true, 10 * MiB, nil},
+		{newReserveTestDrive("drive-1", 50*MiB), 1, 10 * MiB, 30 * MiB, false, 10 * MiB, errDriveNotReservable},
+	}
+
+	for i, testCase := range testCases {
+		setupReserveTest(testCase.conflicts, testCase.concurrentFree, testCase.drive)
+		_, added, err := reserveDrive(context.TODO(), "drive-1", "volume-1", "", testCase.size)
+		if !errors.Is(err, testCase.expectedErr) {
+			t.Fatalf("case %v: error: expected: %v, got: %v", i+1, testCase.expectedErr, err)
+		}
+		if added != testCase.expectedAdded {
+			t.Fatalf("case %v: added: expected: %v, got: %v", i+1, testCase.expectedAdded, added)
+		}
+		drive := getTestDrive(t, "drive-1")
+		if drive.Status.FreeCapacity != testCase.expectedFree {
+			t.Fatalf("case %v: free capacity: expected: %v, got: %v", i+1, testCase.expectedFree, drive.Status.FreeCapacity)
+		}
+		if testCase.expectedErr != nil && drive.VolumeExist("volume-1") {
+			t.Fatalf("case %v: volume must not be added to drive", i+1)
+		}
+	}
+}
+
+func newReserveTestRequest(name string, size int64) *csi.CreateVolumeRequest {
+	return &csi.CreateVolumeRequest{
+		Name:          name,
+		CapacityRange: &csi.CapacityRange{RequiredBytes: size},
+		VolumeCapabilities: []*csi.VolumeCapability{
+			{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			},
+		},
+	}
+}
+
+func TestCreateVolumeReselectsDrive(t *testing.T) {
+	// drive-1 is selected first by maximum free capacity, but a concurrent
+	// reservation consumes its free capacity.
+	setupReserveTest(1, 10*MiB, newReserveTestDrive("drive-1", 50*MiB), newReserveTestDrive("drive-2", 40*MiB))
+
+	if _, err := NewServer().CreateVolume(context.TODO(), newReserveTestRequest("volume-1", 30*MiB)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	volume, err := client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get volume; %v", err)
+	}
+	if volume.GetDriveID() != "drive-2" {
+		t.Fatalf("drive: expected: drive-2, got: %v", volume.GetDriveID())
+	}
+	if drive := getTestDrive(t, "drive-1"); drive.VolumeExist("volume-1") || drive.Status.FreeCapacity != 10*MiB {
+		t.Fatalf("unexpected drive-1 %+v", drive.Status)
+	}
+	if drive := getTestDrive(t, "drive-2"); !drive.VolumeExist("volume-1") || drive.Status.FreeCapacity != 10*MiB {
+		t.Fatalf("unexpected drive-2 %+v", drive.Status)
+	}
+}
+
+func TestCreateVolumeUnreservesDrive(t *testing.T) {
+	fakeClientset := setupReserveTest(0, 0, newReserveTestDrive("drive-1", 50*MiB))
+	fakeClientset.PrependReactor("create", consts.VolumeResource, func(_ k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("create failed")
+	})
+
+	_, err := NewServer().CreateVolume(context.TODO(), newReserveTestRequest("volume-1", 30*MiB))
+	if code := status.Code(err); code != codes.Internal {
+		t.Fatalf("expected: %v, got: %v", codes.Internal, err)
+	}
+
+	if drive := getTestDrive(t, "drive-1"); drive.VolumeExist("volume-1") || drive.Status.FreeCapacity != 50*MiB || drive.Status.AllocatedCapacity != 0 {
+		t.Fatalf("drive must be unreserved; got: finalizers: %v, status: %+v", drive.Finalizers, drive.Status)
+	}
+}
+
+func TestControllerExpandVolumeConflict(t *testing.T) {
+	volume := types.NewVolume("volume-1", "fsuuid-1", "node-1", "drive-1", "drive-1", 20*MiB)
+	testCases := []struct {
+		conflicts      int
+		concurrentFree int64
+		expectedCode   codes.Code
+		expectedFree   int64
+	}{
+		{1, 40 * MiB, codes.OK, 10 * MiB},
+		{1, 20 * MiB, codes.OutOfRange, 20 * MiB},
+	}
+
+	for i, testCase := range testCases {
+		setupReserveTest(testCase.conflicts, testCase.concurrentFree, newReserveTestDrive("drive-1", 50*MiB, "volume-1"), volume.DeepCopy())
+		_, err := NewServer().ControllerExpandVolume(context.TODO(), &csi.ControllerExpandVolumeRequest{
+			VolumeId:      "volume-1",
+			CapacityRange: &csi.CapacityRange{RequiredBytes: 50 * MiB},
+		})
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedCode, err)
+		}
+		if drive := getTestDrive(t, "drive-1"); drive.Status.FreeCapacity != testCase.expectedFree {
+			t.Fatalf("case %v: free capacity: expected: %v, got: %v", i+1, testCase.expectedFree, drive.Status.FreeCapacity)
+		}
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"regexp"
+	"strconv"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	"github.com/dustin/go-humanize"
@@ -35,6 +36,23 @@ import (
 
 var volumeClaimIDRegex = regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{4}-[a-fA-F0-9]{12}$")
 
+// supportedAccessModes are single node access modes. Volumes are published to
+// the node of its drive only.
+var supportedAccessModes = map[csi.VolumeCapability_AccessMode_Mode]struct{}{
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER:        {},
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY:   {},
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER: {},
+	csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER:  {},
+}
+
+func isAccessModeSupported(vcap *csi.VolumeCapability) bool {
+	if vcap.GetAccessMode() == nil {
+		return true
+	}
+	_, found := supportedAccessModes[vcap.GetAccessMode().GetMode()]
+	return found
+}
+
 /*  Volume Lifecycle
  *
  *  Creation
@@ -77,6 +95,8 @@ var volumeClaimIDRegex = regexp.MustCompile("^[a-fA-F0-9]{8}-[a-fA-F0-9]{4}-[a-f
 // Server denotes controller server.
 type Server struct {
 	csi.UnimplementedControllerServer
+
+	driveCache *driveCache
 }
 
 // NewServer creates new controller server.
@@ -84,6 +104,17 @@ func NewServer() *Server {
 	return &Server{}
 }
 
+// StartDriveCache starts shared informer backed drive cache and waits for
+// its sync. Drives are listed from the cache after this call.
+func (c *Server) StartDriveCache(ctx context.Context) error {
+	driveCache := newDriveCache(newDriveListerWatcher())
+	if err := driveCache.run(ctx); err != nil {
+		return err
+	}
+	c.driveCache = driveCache
+	return nil
+}
+
 // ControllerGetCapabilities constructs ControllerGetCapabilitiesResponse
 // reference: https://github.com/container-storage-interface/spec/blob/master/spec.md#controllergetcapabilities
 func (c *Server) ControllerGetCapabilities(_ context.Context, _ *csi.ControllerGetCapabilitiesRequest) (*csi.ControllerGetCapabilitiesResponse, error) {
@@ -99,6 +130,46 @@ func (c *Server) ControllerGetCapabilities(_ context.Context, _ *csi.ControllerG
 					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_EXPAND_VOLUME},
 				},
 			},
+			{
+				Type: &csi.ControllerServiceCapability_Rpc{
+					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_CREATE_DELETE_SNAPSHOT},
+				},
+			},
+			{
+				Type: &csi.ControllerServiceCapability_Rpc{
+					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_LIST_SNAPSHOTS},
+				},
+			},
+			{
+				Type: &csi.ControllerServiceCapability_Rpc{
+					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_CLONE_VOLUME},
+				},
+			},
+			{
+				Type: &csi.ControllerServiceCapability_Rpc{
+					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_GET_CAPACITY},
+				},
+			},
+			{
+				Type: &csi.ControllerServiceCapability_Rpc{
+					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER},
+				},
+			},
+			{
+				Type: &csi.ControllerServiceCapability_Rpc{
+					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_MODIFY_VOLUME},
+				},
+			},
+			{
+				Type: &csi.ControllerServiceCapability_Rpc{
+					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_GET_VOLUME},
+				},
+			},
+			{
+				Type: &csi.ControllerServiceCapability_Rpc{
+					Rpc: &csi.ControllerServiceCapability_RPC{Type: csi.ControllerServiceCapability_RPC_VOLUME_CONDITION},
+				},
+			},
 		},
 	}, nil
 }
@@ -108,7 +179,7 @@ func (c *Server) ControllerGetCapabilities(_ context.Context, _ *csi.ControllerG
 func (c *Server) ValidateVolumeCapabilities(_ context.Context, req *csi.ValidateVolumeCapabilitiesRequest) (*csi.ValidateVolumeCapabilitiesResponse, error) {
 	var message string
 	for _, vcap := range req.GetVolumeCapabilities() {
-		if vcap.GetAccessMode() != nil && vcap.GetAccessMode().GetMode() != csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER {
+		if !isAccessModeSupported(vcap) {
 			message = fmt.Sprintf("unsupported access mode %s", vcap.GetAccessMode().GetMode())
 			break
 		}
@@ -128,7 +199,7 @@ func (c *Server) ValidateVolumeCapabilities(_ context.Context, req *csi.Validate
 
 // CreateVolume - Creates a volume
 // reference: https://github.com/container-storage-interface/spec/blob/master/spec.md#createvolume
-func (c *Server) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (*csi.CreateVolumeResponse, error) {
+func (c *Server) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest) (_ *csi.CreateVolumeResponse, err error) {
 	requiredBytes := int64(-1)
 	if req.GetCapacityRange() != nil {
 		requiredBytes = req.GetCapacityRange().GetRequiredBytes()
@@ -141,16 +212,38 @@ func (c *Server) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 	}
 
 	for _, vcap := range req.GetVolumeCapabilities() {
-		if vcap.GetAccessMode() != nil && vcap.GetAccessMode().GetMode() != csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER {
+		if !isAccessModeSupported(vcap) {
 			return nil, status.Errorf(codes.InvalidArgument, "unsupported access mode %s for volume %v", vcap.GetAccessMode().GetMode(), name)
 		}
 	}
 
-	if len(req.GetVolumeCapabilities()) > 0 && req.GetVolumeCapabilities()[0].GetMount().GetFsType() != "xfs" {
+	if len(req.GetMutableParameters()) != 0 {
+		if err := validateMutableParameters(req.GetMutableParameters()); err != nil {
+			return nil, err
+		}
+
+		// Mutable parameters from VolumeAttributesClass are matched like storage class parameters.
+		parameters := make(map[string]string, len(req.GetParameters())+len(req.GetMutableParameters()))
+		for key, value := range req.GetParameters() {
+			parameters[key] = value
+		}
+		for key, value := range req.GetMutableParameters() {
+			parameters[key] = value
+		}
+		req.Parameters = parameters
+	}
+
+	blockAllocation, err := getBlockAllocation(req)
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported block volume request for volume %v; %v", name, err)
+	}
+
+	if blockAllocation == "" && len(req.GetVolumeCapabilities()) > 0 && req.GetVolumeCapabilities()[0].GetMount().GetFsType() != "xfs" {
 		return nil, status.Errorf(codes.InvalidArgument, "unsupported filesystem type %v for volume %v", req.GetVolumeCapabilities()[0].GetMount().GetFsType(), name)
 	}
 
 	var volumeClaimID string
+	var inodeLimit int64
 	for key, value := range req.GetParameters() {
 		switch key {
 		case string(directpvtypes.AccessTierLabelKey):
@@ -162,23 +255,52 @@ func (c *Server) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 				return nil, status.Errorf(codes.InvalidArgument, "invalid volume claim ID %v; ", value)
 			}
 			volumeClaimID = value
+		case string(directpvtypes.InodeLimitLabelKey):
+			if inodeLimit, err = strconv.ParseInt(value, 10, 64); err != nil || inodeLimit <= 0 {
+				return nil, status.Errorf(codes.InvalidArgument, "invalid inode limit %v for volume %v; value must be a positive integer", value, name)
+			}
 		}
 	}
 
-	drive, err := selectDrive(ctx, req)
+	ioLimits, err := getIOLimits(req.GetParameters())
 	if err != nil {
-		return nil, err
+		return nil, status.Errorf(codes.InvalidArgument, "invalid I/O limits for volume %v; %v", name, err)
 	}
 
-	klog.V(4).InfoS("Selected drive",
-		"drive", drive.GetDriveID(),
-		"node", drive.GetNodeID(),
-		"name", drive.GetDriveName(),
-		"volume", name)
+	ownership, err := getOwnership(req.GetParameters())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid ownership for volume %v; %v", name, err)
+	}
 
-	size := drive.Status.FreeCapacity
-	if req.GetCapacityRange() != nil {
-		size = req.GetCapacityRange().GetRequiredBytes()
+	mountFlags, err := getMountFlags(req.GetParameters())
+	if err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid mount flags for volume %v; %v", name, err)
+	}
+
+	var source *cloneSource
+	if req.GetVolumeContentSource() != nil {
+		if source, err = getCloneSource(ctx, req.GetVolumeContentSource()); err != nil {
+			return nil, err
+		}
+	}
+
+	if source != nil && source.volume != "" && source.block != (blockAllocation != "") {
+		return nil, status.Errorf(codes.InvalidArgument, "volume mode of volume %v does not match with its content source", name)
+	}
+
+	drive, size, added, err := c.selectAndReserveDrive(ctx, req, source, volumeClaimID)
+	if err != nil {
+		return nil, err
+	}
+	if c.driveCache != nil {
+		defer func() {
+			if err != nil {
+				c.driveCache.release(drive.GetDriveID(), name)
+			}
+		}()
+	}
+	if added {
+		client.Eventf(drive, client.EventTypeNormal, client.EventReasonVolumeAdded, "volume %v with size %v is added", name, humanize.Comma(size))
 	}
 
 	newVolume := types.NewVolume(
@@ -190,9 +312,25 @@ func (c *Server) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 		size,
 	)
 	newVolume.SetClaimID(volumeClaimID)
+	newVolume.Status.InodeLimit = inodeLimit
+	newVolume.Status.IOLimits = ioLimits
+	newVolume.Status.Ownership = ownership
+	newVolume.Status.MountFlags = mountFlags
+	if blockAllocation != "" {
+		newVolume.SetBlock(blockAllocation)
+	}
+	if source != nil {
+		newVolume.Status.Clone = source.toCloneStatus()
+	}
 
 	if _, err := client.VolumeClient().Create(ctx, newVolume, metav1.CreateOptions{}); err != nil {
 		if !errors.IsAlreadyExists(err) {
+			if added {
+				// Volume must not be left reserved in the drive without volume.
+				if err := unreserveDrive(ctx, drive.GetDriveID(), name, volumeClaimID, size); err != nil {
+					klog.ErrorS(err, "unable to unreserve drive", "drive", drive.GetDriveID(), "volume", name)
+				}
+			}
 			return nil, status.Errorf(codes.Internal, "unable to create volume %v; %v", name, err)
 		}
 
@@ -218,26 +356,6 @@ func (c *Server) CreateVolume(ctx context.Context, req *csi.CreateVolumeRequest)
 		client.Eventf(newVolume, client.EventTypeNormal, client.EventReasonVolumeProvisioned, "volume is created")
 	}
 
-	if drive.AddVolumeFinalizer(req.GetName()) {
-		drive.SetVolumeClaimID(volumeClaimID)
-		drive.Status.FreeCapacity -= size
-		drive.Status.AllocatedCapacity += size
-
-		klog.V(4).InfoS("Reserving drive",
-			"drive", drive.GetDriveID(),
-			"node", drive.GetNodeID(),
-			"name", drive.GetDriveName(),
-			"volume", name)
-
-		_, err = client.DriveClient().Update(
-			ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()},
-		)
-		if err != nil {
-			return nil, status.Errorf(codes.Internal, "unable to update reserved drive for volume %v; drive=%v, node=%v, name=%v; %v", name, drive.GetDriveID(), drive.GetNodeID(), drive.GetDriveName(), err)
-		}
-		client.Eventf(drive, client.EventTypeNormal, client.EventReasonVolumeAdded, "volume %v with size %v is added", name, humanize.Comma(size))
-	}
-
 	return &csi.CreateVolumeResponse{
 		Volume: &csi.Volume{
 			VolumeId:      name,
@@ -320,36 +438,10 @@ func (c *Server) ControllerExpandVolume(ctx context.Context, req *csi.Controller
 		return &csi.ControllerExpandVolumeResponse{CapacityBytes: requiredBytes}, nil
 	}
 
-	drive, err := client.DriveClient().Get(
-		ctx, string(volume.GetDriveID()), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()},
-	)
-	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			"unable to get drive %v for volume %v; %v",
-			volume.GetDriveID(), volumeID, err,
-		)
-	}
 	size := requiredBytes - volume.Status.TotalCapacity
-	if size > drive.Status.FreeCapacity {
-		return nil, status.Errorf(
-			codes.OutOfRange,
-			"required bytes %v is greater than free capacity of drive %v for volume %v expansion",
-			requiredBytes, volume.GetDriveID(), volumeID,
-		)
-	}
-	drive.Status.FreeCapacity -= size
-	drive.Status.AllocatedCapacity += size
-
-	_, err = client.DriveClient().Update(
-		ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()},
-	)
+	drive, err := expandDrive(ctx, volume, size)
 	if err != nil {
-		return nil, status.Errorf(
-			codes.Internal,
-			"unable to update reserved drive %v for volume %v expansion; %v",
-			volume.GetDriveID(), volumeID, err,
-		)
+		return nil, err
 	}
 	client.Eventf(drive, client.EventTypeNormal, client.EventReasonVolumeExpanded, "volume %v with size %v is expanded", volumeID, humanize.Comma(requiredBytes))
 
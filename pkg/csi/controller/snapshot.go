@@ -0,0 +1,193 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"sort"
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+func toCSISnapshot(snapshot *types.Snapshot) *csi.Snapshot {
+	return &csi.Snapshot{
+		SizeBytes:      snapshot.Status.Size,
+		SnapshotId:     snapshot.Name,
+		SourceVolumeId: snapshot.GetSourceVolume(),
+		CreationTime:   timestamppb.New(snapshot.CreationTimestamp.Time),
+		ReadyToUse:     snapshot.IsReady(),
+	}
+}
+
+// CreateSnapshot implements CreateSnapshot controller RPC
+// reference: https://github.com/container-storage-interface/spec/blob/master/spec.md#createsnapshot
+func (c *Server) CreateSnapshot(ctx context.Context, req *csi.CreateSnapshotRequest) (*csi.CreateSnapshotResponse, error) {
+	klog.V(3).InfoS("Create snapshot requested", "name", req.GetName(), "sourceVolume", req.GetSourceVolumeId())
+
+	name := req.GetName()
+	if name == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty snapshot name in the request")
+	}
+	volumeID := req.GetSourceVolumeId()
+	if volumeID == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "empty source volume ID in the request for snapshot %v", name)
+	}
+
+	snapshot, err := client.SnapshotClient().Get(ctx, name, metav1.GetOptions{TypeMeta: types.NewSnapshotTypeMeta()})
+	switch {
+	case err == nil:
+		if snapshot.GetSourceVolume() != volumeID {
+			return nil, status.Errorf(
+				codes.AlreadyExists,
+				"snapshot %v already exists for different source volume %v",
+				name, snapshot.GetSourceVolume(),
+			)
+		}
+	case errors.IsNotFound(err):
+		volume, err := client.VolumeClient().Get(ctx, volumeID, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		if err != nil {
+			code := codes.Internal
+			if errors.IsNotFound(err) {
+				code = codes.NotFound
+			}
+			return nil, status.Errorf(code, "unable to get source volume %v of snapshot %v; %v", volumeID, name, err)
+		}
+
+		drive, err := client.DriveClient().Get(ctx, string(volume.GetDriveID()), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to get drive %v of volume %v; %v", volume.GetDriveID(), volumeID, err)
+		}
+		if drive.IsReflinkDisabled() {
+			return nil, status.Errorf(
+				codes.FailedPrecondition,
+				"drive %v of volume %v does not support XFS reflink; snapshots are not supported on this drive",
+				drive.GetDriveID(), volumeID,
+			)
+		}
+
+		newSnapshot := types.NewSnapshot(
+			name,
+			volumeID,
+			volume.Status.FSUUID,
+			volume.GetNodeID(),
+			volume.GetDriveID(),
+			volume.GetDriveName(),
+			volume.Status.TotalCapacity,
+		)
+		snapshot, err = client.SnapshotClient().Create(ctx, newSnapshot, metav1.CreateOptions{})
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to create snapshot %v; %v", name, err)
+		}
+	default:
+		return nil, status.Errorf(codes.Internal, "unable to get snapshot %v; %v", name, err)
+	}
+
+	if snapshot.Status.Status == directpvtypes.SnapshotStatusError {
+		return nil, status.Errorf(codes.Internal, "unable to create snapshot %v; %v", name, snapshot.Status.Error)
+	}
+
+	return &csi.CreateSnapshotResponse{Snapshot: toCSISnapshot(snapshot)}, nil
+}
+
+// DeleteSnapshot implements DeleteSnapshot controller RPC
+// reference: https://github.com/container-storage-interface/spec/blob/master/spec.md#deletesnapshot
+func (c *Server) DeleteSnapshot(ctx context.Context, req *csi.DeleteSnapshotRequest) (*csi.DeleteSnapshotResponse, error) {
+	klog.V(3).InfoS("Delete snapshot requested", "name", req.GetSnapshotId())
+	snapshotID := req.GetSnapshotId()
+	if snapshotID == "" {
+		return nil, status.Error(codes.InvalidArgument, "empty snapshot ID in the request")
+	}
+
+	if err := client.SnapshotClient().Delete(ctx, snapshotID, metav1.DeleteOptions{}); err != nil && !errors.IsNotFound(err) {
+		return nil, status.Errorf(codes.Internal, "unable to delete snapshot %v; %v", snapshotID, err)
+	}
+
+	return &csi.DeleteSnapshotResponse{}, nil
+}
+
+// ListSnapshots implements ListSnapshots controller RPC
+// reference: https://github.com/container-storage-interface/spec/blob/master/spec.md#listsnapshots
+func (c *Server) ListSnapshots(ctx context.Context, req *csi.ListSnapshotsRequest) (*csi.ListSnapshotsResponse, error) {
+	klog.V(5).InfoS("List snapshots requested", "snapshotID", req.GetSnapshotId(), "sourceVolume", req.GetSourceVolumeId())
+
+	start := 0
+	if req.GetStartingToken() != "" {
+		var err error
+		if start, err = strconv.Atoi(req.GetStartingToken()); err != nil || start < 0 {
+			return nil, status.Errorf(codes.Aborted, "invalid starting token %v", req.GetStartingToken())
+		}
+	}
+	if req.GetMaxEntries() < 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid max entries %v", req.GetMaxEntries())
+	}
+
+	var snapshots []types.Snapshot
+	if req.GetSnapshotId() != "" {
+		snapshot, err := client.SnapshotClient().Get(ctx, req.GetSnapshotId(), metav1.GetOptions{TypeMeta: types.NewSnapshotTypeMeta()})
+		switch {
+		case err == nil:
+			if req.GetSourceVolumeId() == "" || snapshot.GetSourceVolume() == req.GetSourceVolumeId() {
+				snapshots = append(snapshots, *snapshot)
+			}
+		case !errors.IsNotFound(err):
+			return nil, status.Errorf(codes.Internal, "unable to get snapshot %v; %v", req.GetSnapshotId(), err)
+		}
+	} else {
+		lister := client.NewSnapshotLister().IgnoreNotFound(true)
+		if req.GetSourceVolumeId() != "" {
+			lister = lister.SourceVolumeSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(req.GetSourceVolumeId())})
+		}
+
+		var err error
+		if snapshots, err = lister.Get(ctx); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to list snapshots; %v", err)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Name < snapshots[j].Name })
+
+	if start > len(snapshots) {
+		return nil, status.Errorf(codes.Aborted, "invalid starting token %v", req.GetStartingToken())
+	}
+
+	end := len(snapshots)
+	if req.GetMaxEntries() > 0 && start+int(req.GetMaxEntries()) < end {
+		end = start + int(req.GetMaxEntries())
+	}
+
+	response := &csi.ListSnapshotsResponse{}
+	for i := start; i < end; i++ {
+		response.Entries = append(response.Entries, &csi.ListSnapshotsResponse_Entry{
+			Snapshot: toCSISnapshot(&snapshots[i]),
+		})
+	}
+	if end < len(snapshots) {
+		response.NextToken = strconv.Itoa(end)
+	}
+
+	return response, nil
+}
@@ -0,0 +1,191 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package controller
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func setupSnapshotTest() {
+	newDrive := func(driveID directpvtypes.DriveID, driveName directpvtypes.DriveName, reflink bool) *types.Drive {
+		drive := types.NewDrive(
+			driveID,
+			types.DriveStatus{
+				TotalCapacity: 100 * MiB,
+				FreeCapacity:  50 * MiB,
+				FSUUID:        string(driveID),
+				Status:        directpvtypes.DriveStatusReady,
+			},
+			"node-1",
+			driveName,
+			directpvtypes.AccessTierDefault,
+		)
+		drive.SetReflinkLabel(reflink)
+		return drive
+	}
+
+	objects := []runtime.Object{
+		newDrive("drive-1", "sda", true),
+		newDrive("drive-2", "sdb", false),
+		types.NewVolume("volume-1", "drive-1", "node-1", "drive-1", "sda", 10*MiB),
+		types.NewVolume("volume-2", "drive-2", "node-1", "drive-2", "sdb", 10*MiB),
+		types.NewSnapshot("snapshot-1", "volume-1", "drive-1", "node-1", "drive-1", "sda", 10*MiB),
+		types.NewSnapshot("snapshot-2", "volume-1", "drive-1", "node-1", "drive-1", "sda", 10*MiB),
+		types.NewSnapshot("snapshot-3", "volume-3", "drive-1", "node-1", "drive-1", "sda", 10*MiB),
+	}
+
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+	client.SetSnapshotInterface(clientset.DirectpvLatest().DirectPVSnapshots())
+}
+
+func TestCreateSnapshot(t *testing.T) {
+	setupSnapshotTest()
+
+	testCases := []struct {
+		req          *csi.CreateSnapshotRequest
+		expectedCode codes.Code
+	}{
+		{&csi.CreateSnapshotRequest{SourceVolumeId: "volume-1"}, codes.InvalidArgument},
+		{&csi.CreateSnapshotRequest{Name: "snapshot-4"}, codes.InvalidArgument},
+		{&csi.CreateSnapshotRequest{Name: "snapshot-4", SourceVolumeId: "volume-x"}, codes.NotFound},
+		{&csi.CreateSnapshotRequest{Name: "snapshot-4", SourceVolumeId: "volume-2"}, codes.FailedPrecondition},
+		{&csi.CreateSnapshotRequest{Name: "snapshot-1", SourceVolumeId: "volume-2"}, codes.AlreadyExists},
+		{&csi.CreateSnapshotRequest{Name: "snapshot-1", SourceVolumeId: "volume-1"}, codes.OK},
+		{&csi.CreateSnapshotRequest{Name: "snapshot-4", SourceVolumeId: "volume-1"}, codes.OK},
+	}
+
+	ctx := context.TODO()
+	server := NewServer()
+	for i, testCase := range testCases {
+		result, err := server.CreateSnapshot(ctx, testCase.req)
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected code: %v, got: %v; %v", i+1, testCase.expectedCode, code, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		if result.Snapshot.SnapshotId != testCase.req.Name || result.Snapshot.SourceVolumeId != testCase.req.SourceVolumeId {
+			t.Fatalf("case %v: unexpected snapshot %+v", i+1, result.Snapshot)
+		}
+		if result.Snapshot.SizeBytes != 10*MiB {
+			t.Fatalf("case %v: expected size: %v, got: %v", i+1, 10*MiB, result.Snapshot.SizeBytes)
+		}
+		if result.Snapshot.ReadyToUse {
+			t.Fatalf("case %v: expected snapshot not ready to use", i+1)
+		}
+	}
+
+	snapshot, err := client.SnapshotClient().Get(ctx, "snapshot-4", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.GetNodeID() != "node-1" || snapshot.GetDriveID() != "drive-1" || snapshot.Status.FSUUID != "drive-1" {
+		t.Fatalf("unexpected snapshot %+v", snapshot)
+	}
+
+	snapshot.Status.Status = directpvtypes.SnapshotStatusError
+	snapshot.Status.Error = "reflink not supported"
+	if _, err = client.SnapshotClient().Update(ctx, snapshot, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = server.CreateSnapshot(ctx, &csi.CreateSnapshotRequest{Name: "snapshot-4", SourceVolumeId: "volume-1"})
+	if code := status.Code(err); code != codes.Internal {
+		t.Fatalf("expected code: %v, got: %v; %v", codes.Internal, code, err)
+	}
+}
+
+func TestDeleteSnapshot(t *testing.T) {
+	setupSnapshotTest()
+
+	testCases := []struct {
+		snapshotID   string
+		expectedCode codes.Code
+	}{
+		{"", codes.InvalidArgument},
+		{"snapshot-1", codes.OK},
+		{"snapshot-1", codes.OK},
+		{"snapshot-x", codes.OK},
+	}
+
+	ctx := context.TODO()
+	server := NewServer()
+	for i, testCase := range testCases {
+		_, err := server.DeleteSnapshot(ctx, &csi.DeleteSnapshotRequest{SnapshotId: testCase.snapshotID})
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected code: %v, got: %v; %v", i+1, testCase.expectedCode, code, err)
+		}
+	}
+}
+
+func TestListSnapshots(t *testing.T) {
+	setupSnapshotTest()
+
+	testCases := []struct {
+		req               *csi.ListSnapshotsRequest
+		expectedSnapshots []string
+		expectedNextToken string
+		expectedCode      codes.Code
+	}{
+		{&csi.ListSnapshotsRequest{}, []string{"snapshot-1", "snapshot-2", "snapshot-3"}, "", codes.OK},
+		{&csi.ListSnapshotsRequest{MaxEntries: 2}, []string{"snapshot-1", "snapshot-2"}, "2", codes.OK},
+		{&csi.ListSnapshotsRequest{MaxEntries: 2, StartingToken: "2"}, []string{"snapshot-3"}, "", codes.OK},
+		{&csi.ListSnapshotsRequest{StartingToken: "10"}, nil, "", codes.Aborted},
+		{&csi.ListSnapshotsRequest{StartingToken: "abc"}, nil, "", codes.Aborted},
+		{&csi.ListSnapshotsRequest{SnapshotId: "snapshot-2"}, []string{"snapshot-2"}, "", codes.OK},
+		{&csi.ListSnapshotsRequest{SnapshotId: "snapshot-x"}, nil, "", codes.OK},
+		{&csi.ListSnapshotsRequest{SnapshotId: "snapshot-3", SourceVolumeId: "volume-1"}, nil, "", codes.OK},
+		{&csi.ListSnapshotsRequest{SourceVolumeId: "volume-1"}, []string{"snapshot-1", "snapshot-2"}, "", codes.OK},
+	}
+
+	ctx := context.TODO()
+	server := NewServer()
+	for i, testCase := range testCases {
+		result, err := server.ListSnapshots(ctx, testCase.req)
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected code: %v, got: %v; %v", i+1, testCase.expectedCode, code, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		var snapshots []string
+		for _, entry := range result.Entries {
+			snapshots = append(snapshots, entry.Snapshot.SnapshotId)
+		}
+		if !reflect.DeepEqual(snapshots, testCase.expectedSnapshots) {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedSnapshots, snapshots)
+		}
+		if result.NextToken != testCase.expectedNextToken {
+			t.Fatalf("case %v: expected next token: %v, got: %v", i+1, testCase.expectedNextToken, result.NextToken)
+		}
+	}
+}
@@ -18,39 +18,67 @@ package controller
 
 import (
 	"context"
-	"crypto/rand"
 	"fmt"
-	"math/big"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
 	"github.com/minio/directpv/pkg/client"
 	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/k8s"
 	"github.com/minio/directpv/pkg/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
 )
 
+// driveRejection denotes the reason of a drive not matching the request.
+type driveRejection string
+
+// Enum values of driveRejection type.
+const (
+	driveRejectionTerminating      driveRejection = "terminating"
+	driveRejectionNotReady         driveRejection = "not ready"
+	driveRejectionCordoned         driveRejection = "cordoned"
+	driveRejectionTooSmall         driveRejection = "insufficient free capacity"
+	driveRejectionAccessTier       driveRejection = "access-tier mismatch"
+	driveRejectionVolumeClaimID    driveRejection = "volume claim ID conflict"
+	driveRejectionLabelMismatch    driveRejection = "label mismatch"
+	driveRejectionTopologyMismatch driveRejection = "topology mismatch"
+)
+
+// maxRejectedDriveNames is the maximum number of drive names shown per
+// rejection reason.
+const maxRejectedDriveNames = 3
+
 func matchDrive(drive *types.Drive, req *csi.CreateVolumeRequest) bool {
+	return getDriveRejection(drive, req) == ""
+}
+
+// getDriveRejection returns the reason of the drive not matching the request.
+// Empty value is returned if the drive matches the request.
+func getDriveRejection(drive *types.Drive, req *csi.CreateVolumeRequest) driveRejection {
 	// Skip terminating drives
 	if !drive.GetDeletionTimestamp().IsZero() {
-		return false
+		return driveRejectionTerminating
 	}
 
 	// Skip drives if status is not ready
 	if drive.Status.Status != directpvtypes.DriveStatusReady {
-		return false
+		return driveRejectionNotReady
 	}
 
 	// Skip drives if unschedulable
 	if drive.IsUnschedulable() {
-		return false
+		return driveRejectionCordoned
 	}
 
 	// Match drive if it has requested capacity.
 	if req.GetCapacityRange() != nil && drive.Status.FreeCapacity < req.GetCapacityRange().GetRequiredBytes() {
-		return false
+		return driveRejectionTooSmall
 	}
 
 	// Match drive by access-tier if requested.
@@ -64,16 +92,34 @@ func matchDrive(drive *types.Drive, req *csi.CreateVolumeRequest) bool {
 		case string(directpvtypes.AccessTierLabelKey):
 			accessTiers, _ := directpvtypes.StringsToAccessTiers(value)
 			if len(accessTiers) > 0 && drive.GetAccessTier() != accessTiers[0] {
-				return false
+				return driveRejectionAccessTier
 			}
 		case string(directpvtypes.VolumeClaimIDLabelKey):
 			if drive.HasVolumeClaimID(value) {
 				// Do not allocate another volume with this claim id
-				return false
+				return driveRejectionVolumeClaimID
 			}
+		case string(directpvtypes.CloneSpreadLabelKey):
+			// Handled by clone drive selection.
+		case string(directpvtypes.DriveSelectionPolicyLabelKey):
+			// Handled by drive selector.
+		case string(directpvtypes.InodeLimitLabelKey):
+			// Applied on volume quota.
+		case string(directpvtypes.ReadBytesPerSecLabelKey),
+			string(directpvtypes.WriteBytesPerSecLabelKey),
+			string(directpvtypes.ReadIOPSLabelKey),
+			string(directpvtypes.WriteIOPSLabelKey),
+			string(directpvtypes.MountFlagsLabelKey):
+			// Applied on publishing volume.
+		case string(directpvtypes.UIDLabelKey),
+			string(directpvtypes.GIDLabelKey),
+			string(directpvtypes.ModeLabelKey):
+			// Applied on staging volume.
+		case string(directpvtypes.BlockVolumeLabelKey):
+			// Applied on creating volume.
 		default:
 			if labels[key] != value {
-				return false
+				return driveRejectionLabelMismatch
 			}
 		}
 	}
@@ -91,19 +137,95 @@ func matchDrive(drive *types.Drive, req *csi.CreateVolumeRequest) bool {
 
 	// Match drive by preferred topologies if requested.
 	if len(req.GetAccessibilityRequirements().GetPreferred()) > 0 && matchTopologies(req.GetAccessibilityRequirements().GetPreferred()) {
-		return true
+		return ""
 	}
 
 	// Match drive by requisite topology if requested.
 	if len(req.GetAccessibilityRequirements().GetRequisite()) > 0 && matchTopologies(req.GetAccessibilityRequirements().GetRequisite()) {
-		return true
+		return ""
 	}
 
 	// Match drive if no topology constraints requested.
-	return len(req.GetAccessibilityRequirements().GetPreferred()) == 0 && len(req.GetAccessibilityRequirements().GetRequisite()) == 0
+	if len(req.GetAccessibilityRequirements().GetPreferred()) == 0 && len(req.GetAccessibilityRequirements().GetRequisite()) == 0 {
+		return ""
+	}
+
+	return driveRejectionTopologyMismatch
+}
+
+// getDriveRejections returns drive names grouped by their rejection reason
+// for the request.
+func (c *Server) getDriveRejections(ctx context.Context, req *csi.CreateVolumeRequest) (map[driveRejection][]string, error) {
+	drives, err := c.listDrives(ctx, "", "")
+	if err != nil {
+		return nil, err
+	}
+
+	rejections := map[driveRejection][]string{}
+	for i := range drives {
+		if reason := getDriveRejection(&drives[i], req); reason != "" {
+			addDriveRejection(rejections, &drives[i], reason)
+		}
+	}
+	return rejections, nil
+}
+
+func addDriveRejection(rejections map[driveRejection][]string, drive *types.Drive, reason driveRejection) {
+	rejections[reason] = append(rejections[reason], fmt.Sprintf("%v/%v", drive.GetNodeID(), drive.GetDriveName()))
+}
+
+// summarizeDriveRejections returns a summary of drive rejections like
+// "2 not ready (node-1/sda, node-1/sdb); 1 cordoned (node-2/sda)".
+func summarizeDriveRejections(rejections map[driveRejection][]string) string {
+	if len(rejections) == 0 {
+		return "no drives available"
+	}
+
+	reasons := make([]string, 0, len(rejections))
+	for reason := range rejections {
+		reasons = append(reasons, string(reason))
+	}
+	sort.Strings(reasons)
+
+	var summaries []string
+	for _, reason := range reasons {
+		driveNames := rejections[driveRejection(reason)]
+		sort.Strings(driveNames)
+		names := strings.Join(driveNames, ", ")
+		if len(driveNames) > maxRejectedDriveNames {
+			names = strings.Join(driveNames[:maxRejectedDriveNames], ", ") + ", ..."
+		}
+		summaries = append(summaries, fmt.Sprintf("%v %v (%v)", len(driveNames), reason, names))
+	}
+	return strings.Join(summaries, "; ")
+}
+
+// postProvisionFailedEvent posts a warning event on the PVC of the request.
+// PVC name and namespace are passed by csi-provisioner with
+// --extra-create-metadata flag.
+func postProvisionFailedEvent(ctx context.Context, req *csi.CreateVolumeRequest, message string) {
+	namespace := req.GetParameters()[pvcNamespaceParameter]
+	pvcName := req.GetParameters()[pvcNameParameter]
+	if namespace == "" || pvcName == "" {
+		return
+	}
+
+	pvc, err := k8s.KubeClient().CoreV1().PersistentVolumeClaims(namespace).Get(ctx, pvcName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get PVC", "namespace", namespace, "name", pvcName, "volume", req.GetName())
+		return
+	}
+
+	client.Eventf(pvc, client.EventTypeWarning, client.EventReasonVolumeProvisionFailed, "%v", message)
 }
 
-func getFilteredDrives(ctx context.Context, req *csi.CreateVolumeRequest) (drives []types.Drive, err error) {
+// listDrives returns drives by the index value, or all drives if index name is
+// empty, from drive cache if enabled, otherwise all drives from the API server.
+func (c *Server) listDrives(ctx context.Context, indexName, indexValue string) (drives []types.Drive, err error) {
+	if c.driveCache != nil {
+		return c.driveCache.list(indexName, indexValue)
+	}
+
 	ctx, cancelFunc := context.WithCancel(ctx)
 	defer cancelFunc()
 
@@ -111,27 +233,66 @@ func getFilteredDrives(ctx context.Context, req *csi.CreateVolumeRequest) (drive
 		if result.Err != nil {
 			return nil, result.Err
 		}
+		drives = append(drives, result.Drive)
+	}
+
+	return drives, nil
+}
+
+func (c *Server) getFilteredDrives(ctx context.Context, req *csi.CreateVolumeRequest) (drives []types.Drive, err error) {
+	if c.driveCache != nil {
+		if drives, err = c.driveCache.listByVolume(req.GetName()); err != nil || len(drives) != 0 {
+			return drives, err
+		}
+	}
+
+	indexName, indexValue := driveStatusIndex, string(directpvtypes.DriveStatusReady)
+	accessTiers, _ := directpvtypes.StringsToAccessTiers(req.GetParameters()[string(directpvtypes.AccessTierLabelKey)])
+	if len(accessTiers) > 0 {
+		indexName, indexValue = driveAccessTierIndex, string(accessTiers[0])
+	}
+
+	candidates, err := c.listDrives(ctx, indexName, indexValue)
+	if err != nil {
+		return nil, err
+	}
 
-		if result.Drive.VolumeExist(req.GetName()) {
-			return []types.Drive{result.Drive}, nil
+	for i := range candidates {
+		if candidates[i].VolumeExist(req.GetName()) {
+			return []types.Drive{candidates[i]}, nil
 		}
 
-		if matchDrive(&result.Drive, req) {
-			drives = append(drives, result.Drive)
+		if matchDrive(&candidates[i], req) {
+			drives = append(drives, candidates[i])
 		}
 	}
 
 	return drives, nil
 }
 
-func selectDrive(ctx context.Context, req *csi.CreateVolumeRequest) (*types.Drive, error) {
-	drives, err := getFilteredDrives(ctx, req)
+func (c *Server) selectDrive(ctx context.Context, req *csi.CreateVolumeRequest) (*types.Drive, error) {
+	// Drive selection policy is validated irrespective of number of matching drives.
+	selector, err := getDriveSelector(req)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	drives, err := c.getFilteredDrives(ctx, req)
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
 	if len(drives) == 0 {
-		if len(req.GetAccessibilityRequirements().GetPreferred()) != 0 || len(req.GetAccessibilityRequirements().GetRequisite()) != 0 {
+		rejections, err := c.getDriveRejections(ctx, req)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		summary := summarizeDriveRejections(rejections)
+
+		var code codes.Code
+		var message string
+		switch {
+		case len(req.GetAccessibilityRequirements().GetPreferred()) != 0 || len(req.GetAccessibilityRequirements().GetRequisite()) != 0:
 			requestedSize := "nil"
 			if req.GetCapacityRange() != nil {
 				requestedSize = fmt.Sprintf("%d bytes", req.GetCapacityRange().GetRequiredBytes())
@@ -140,36 +301,26 @@ func selectDrive(ctx context.Context, req *csi.CreateVolumeRequest) (*types.Driv
 			if requestedNodes = getNodeNamesFromTopology(req.AccessibilityRequirements.GetPreferred()); len(requestedNodes) == 0 {
 				requestedNodes = getNodeNamesFromTopology(req.AccessibilityRequirements.GetRequisite())
 			}
-			return nil, status.Errorf(codes.ResourceExhausted, "no drive found for requested topology; requested node(s): %s; requested size: %s", strings.Join(requestedNodes, ","), requestedSize)
-		}
-		if req.GetCapacityRange() != nil {
-			return nil, status.Errorf(codes.OutOfRange, "no drive found for requested size %v", req.GetCapacityRange().GetRequiredBytes())
-		}
-		return nil, status.Error(codes.FailedPrecondition, "no drive found")
-	}
-
-	maxFreeCapacity := int64(-1)
-	var maxFreeCapacityDrives []types.Drive
-	for _, drive := range drives {
-		switch {
-		case drive.Status.FreeCapacity == maxFreeCapacity:
-			maxFreeCapacityDrives = append(maxFreeCapacityDrives, drive)
-		case drive.Status.FreeCapacity > maxFreeCapacity:
-			maxFreeCapacity = drive.Status.FreeCapacity
-			maxFreeCapacityDrives = []types.Drive{drive}
+			code = codes.ResourceExhausted
+			message = fmt.Sprintf("no drive found for requested topology; requested node(s): %s; requested size: %s", strings.Join(requestedNodes, ","), requestedSize)
+		case req.GetCapacityRange() != nil:
+			code = codes.OutOfRange
+			message = fmt.Sprintf("no drive found for requested size %v", req.GetCapacityRange().GetRequiredBytes())
+		default:
+			code = codes.FailedPrecondition
+			message = "no drive found"
 		}
-	}
+		message = fmt.Sprintf("%v; rejected drives: %v", message, summary)
 
-	if len(maxFreeCapacityDrives) == 1 {
-		return &maxFreeCapacityDrives[0], nil
+		postProvisionFailedEvent(ctx, req, message)
+		return nil, status.Error(code, message)
 	}
 
-	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(maxFreeCapacityDrives))))
-	if err != nil {
-		return nil, status.Errorf(codes.Internal, "random number generation failed; %v", err)
+	if len(drives) == 1 {
+		return &drives[0], nil
 	}
 
-	return &maxFreeCapacityDrives[n.Int64()], nil
+	return selector.SelectDrive(ctx, req, drives)
 }
 
 func getNodeNamesFromTopology(topologies []*csi.Topology) (requestedNodes []string) {
@@ -183,3 +334,108 @@ func getNodeNamesFromTopology(topologies []*csi.Topology) (requestedNodes []stri
 	}
 	return
 }
+
+// getIOLimits returns I/O limits from storage class parameters; nil if no limit is set.
+func getIOLimits(parameters map[string]string) (*types.IOLimits, error) {
+	ioLimits := &types.IOLimits{}
+	limits := []struct {
+		key   directpvtypes.LabelKey
+		value *int64
+	}{
+		{directpvtypes.ReadBytesPerSecLabelKey, &ioLimits.ReadBytesPerSec},
+		{directpvtypes.WriteBytesPerSecLabelKey, &ioLimits.WriteBytesPerSec},
+		{directpvtypes.ReadIOPSLabelKey, &ioLimits.ReadIOPS},
+		{directpvtypes.WriteIOPSLabelKey, &ioLimits.WriteIOPS},
+	}
+
+	found := false
+	for _, limit := range limits {
+		value, ok := parameters[string(limit.key)]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid %v value %v; value must be a positive integer", limit.key, value)
+		}
+		*limit.value = n
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return ioLimits, nil
+}
+
+// allowedMountFlags is the list of extra mount flags allowed on a volume.
+var allowedMountFlags = map[string]struct{}{
+	"nosuid":     {},
+	"nodev":      {},
+	"noexec":     {},
+	"noatime":    {},
+	"nodiratime": {},
+}
+
+// getMountFlags returns extra mount flags from storage class parameters.
+func getMountFlags(parameters map[string]string) ([]string, error) {
+	value, found := parameters[string(directpvtypes.MountFlagsLabelKey)]
+	if !found {
+		return nil, nil
+	}
+
+	var flags []string
+	seen := map[string]struct{}{}
+	for _, flag := range strings.Split(value, ",") {
+		flag = strings.TrimSpace(flag)
+		if _, found := allowedMountFlags[flag]; !found {
+			return nil, fmt.Errorf("mount flag %q is not allowed", flag)
+		}
+		if _, found := seen[flag]; found {
+			continue
+		}
+		seen[flag] = struct{}{}
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// getOwnership returns volume root directory ownership from storage class parameters; nil if not set.
+func getOwnership(parameters map[string]string) (*types.VolumeOwnership, error) {
+	ownership := &types.VolumeOwnership{}
+	found := false
+	for _, id := range []struct {
+		key   directpvtypes.LabelKey
+		value *int64
+	}{
+		{directpvtypes.UIDLabelKey, &ownership.UID},
+		{directpvtypes.GIDLabelKey, &ownership.GID},
+	} {
+		value, ok := parameters[string(id.key)]
+		if !ok {
+			continue
+		}
+
+		n, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %v value %v; value must be a non-negative integer", id.key, value)
+		}
+		*id.value = int64(n)
+		found = true
+	}
+
+	if value, ok := parameters[string(directpvtypes.ModeLabelKey)]; ok {
+		mode, err := strconv.ParseUint(value, 8, 32)
+		if err != nil || mode > 0o7777 {
+			return nil, fmt.Errorf("invalid %v value %v; value must be an octal permission mode", directpvtypes.ModeLabelKey, value)
+		}
+		ownership.Mode = fmt.Sprintf("%04o", mode)
+		found = true
+	}
+
+	if !found {
+		return nil, nil
+	}
+	return ownership, nil
+}
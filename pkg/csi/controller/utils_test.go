@@ -28,6 +28,8 @@ import (
 	"github.com/minio/directpv/pkg/consts"
 	"github.com/minio/directpv/pkg/types"
 	"github.com/minio/directpv/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -481,7 +483,7 @@ func TestGetFilteredDrives(t *testing.T) {
 	for i, testCase := range testCases {
 		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(testCase.objects...))
 		client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
-		result, err := getFilteredDrives(context.TODO(), testCase.request)
+		result, err := NewServer().getFilteredDrives(context.TODO(), testCase.request)
 		if err != nil {
 			t.Fatalf("case %v: unexpected error: %v", i+1, err)
 		}
@@ -553,7 +555,7 @@ func TestGetDrive(t *testing.T) {
 		client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
 		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
 
-		result, err := selectDrive(context.TODO(), testCase.request)
+		result, err := NewServer().selectDrive(context.TODO(), testCase.request)
 		if err != nil && !testCase.expectErr {
 			t.Fatalf("case %v: unable to select drive; %v", i+1, err)
 		}
@@ -602,11 +604,206 @@ func TestGetDrive(t *testing.T) {
 
 	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
 	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
-	result, err := selectDrive(context.TODO(), request)
+	result, err := NewServer().selectDrive(context.TODO(), request)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if !utils.Contains([]string{"drive-2", "drive-3"}, result.Name) {
 		t.Fatalf("result: expected: %v, got: %v", []string{"drive-2", "drive-3"}, result.Name)
 	}
+
+	// Unknown drive selection policy is rejected even if only one drive matches.
+	if err = client.DriveClient().Delete(context.TODO(), "drive-3", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	request.Parameters = map[string]string{string(directpvtypes.DriveSelectionPolicyLabelKey): "unknown"}
+	if _, err = NewServer().selectDrive(context.TODO(), request); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected: %v error, got: %v", codes.InvalidArgument, err)
+	}
+}
+
+func TestGetDriveRejection(t *testing.T) {
+	newDrive := func(driveID directpvtypes.DriveID, driveStatus directpvtypes.DriveStatus) *types.Drive {
+		return types.NewDrive(
+			driveID,
+			types.DriveStatus{
+				Status:       driveStatus,
+				FreeCapacity: 4 * GiB,
+				Topology:     map[string]string{"node": "node-1"},
+			},
+			"node-1",
+			directpvtypes.DriveName("sda"),
+			directpvtypes.AccessTierDefault,
+		)
+	}
+
+	cordonedDrive := newDrive("drive-1", directpvtypes.DriveStatusReady)
+	cordonedDrive.Unschedulable()
+	claimedDrive := newDrive("drive-1", directpvtypes.DriveStatusReady)
+	claimedDrive.SetVolumeClaimID("xxx")
+
+	testCases := []struct {
+		drive          *types.Drive
+		req            *csi.CreateVolumeRequest
+		expectedResult driveRejection
+	}{
+		{newDrive("drive-1", directpvtypes.DriveStatusReady), &csi.CreateVolumeRequest{}, ""},
+		{newDrive("drive-1", directpvtypes.DriveStatusError), &csi.CreateVolumeRequest{}, driveRejectionNotReady},
+		{cordonedDrive, &csi.CreateVolumeRequest{}, driveRejectionCordoned},
+		{
+			newDrive("drive-1", directpvtypes.DriveStatusReady),
+			&csi.CreateVolumeRequest{CapacityRange: &csi.CapacityRange{RequiredBytes: 8 * GiB}},
+			driveRejectionTooSmall,
+		},
+		{
+			newDrive("drive-1", directpvtypes.DriveStatusReady),
+			&csi.CreateVolumeRequest{Parameters: map[string]string{string(directpvtypes.AccessTierLabelKey): "hot"}},
+			driveRejectionAccessTier,
+		},
+		{
+			newDrive("drive-1", directpvtypes.DriveStatusReady),
+			&csi.CreateVolumeRequest{Parameters: map[string]string{consts.GroupName + "/rack": "rack-1"}},
+			driveRejectionLabelMismatch,
+		},
+		{
+			claimedDrive,
+			&csi.CreateVolumeRequest{Parameters: map[string]string{string(directpvtypes.VolumeClaimIDLabelKey): "xxx"}},
+			driveRejectionVolumeClaimID,
+		},
+		{
+			newDrive("drive-1", directpvtypes.DriveStatusReady),
+			&csi.CreateVolumeRequest{
+				AccessibilityRequirements: &csi.TopologyRequirement{
+					Requisite: []*csi.Topology{{Segments: map[string]string{"node": "node-2"}}},
+				},
+			},
+			driveRejectionTopologyMismatch,
+		},
+	}
+
+	for i, testCase := range testCases {
+		if result := getDriveRejection(testCase.drive, testCase.req); result != testCase.expectedResult {
+			t.Fatalf("case %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestSelectDriveRejections(t *testing.T) {
+	newDrive := func(driveID directpvtypes.DriveID, driveName directpvtypes.DriveName, driveStatus directpvtypes.DriveStatus, freeCapacity int64) *types.Drive {
+		return types.NewDrive(
+			driveID,
+			types.DriveStatus{Status: driveStatus, FreeCapacity: freeCapacity},
+			"node-1",
+			driveName,
+			directpvtypes.AccessTierDefault,
+		)
+	}
+
+	objects := []runtime.Object{
+		newDrive("drive-1", "sda", directpvtypes.DriveStatusError, 4*GiB),
+		newDrive("drive-2", "sdb", directpvtypes.DriveStatusReady, GiB),
+		newDrive("drive-3", "sdc", directpvtypes.DriveStatusReady, GiB),
+	}
+	request := &csi.CreateVolumeRequest{Name: "volume-1", CapacityRange: &csi.CapacityRange{RequiredBytes: 2 * GiB}}
+
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+	_, err := NewServer().selectDrive(context.TODO(), request)
+	if status.Code(err) != codes.OutOfRange {
+		t.Fatalf("expected: %v, got: %v", codes.OutOfRange, err)
+	}
+
+	expectedMessage := "no drive found for requested size 2147483648; rejected drives: 2 insufficient free capacity (node-1/sdb, node-1/sdc); 1 not ready (node-1/sda)"
+	if message := status.Convert(err).Message(); message != expectedMessage {
+		t.Fatalf("message: expected: %v, got: %v", expectedMessage, message)
+	}
+}
+
+func TestSummarizeDriveRejections(t *testing.T) {
+	testCases := []struct {
+		rejections     map[driveRejection][]string
+		expectedResult string
+	}{
+		{nil, "no drives available"},
+		{
+			map[driveRejection][]string{driveRejectionCordoned: {"node-2/sda"}},
+			"1 cordoned (node-2/sda)",
+		},
+		{
+			map[driveRejection][]string{
+				driveRejectionNotReady: {"node-1/sdd", "node-1/sdc", "node-1/sdb", "node-1/sda"},
+				driveRejectionCordoned: {"node-2/sda"},
+			},
+			"1 cordoned (node-2/sda); 4 not ready (node-1/sda, node-1/sdb, node-1/sdc, ...)",
+		},
+	}
+
+	for i, testCase := range testCases {
+		if result := summarizeDriveRejections(testCase.rejections); result != testCase.expectedResult {
+			t.Fatalf("case %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+	}
+}
+
+func TestGetMountFlags(t *testing.T) {
+	testCases := []struct {
+		value         string
+		expectedFlags []string
+		expectErr     bool
+	}{
+		{"", nil, true},
+		{"nosuid", []string{"nosuid"}, false},
+		{"nosuid, nodev,noexec,nosuid", []string{"nosuid", "nodev", "noexec"}, false},
+		{"nosuid,rw", nil, true},
+		{"bind", nil, true},
+	}
+
+	if flags, err := getMountFlags(map[string]string{}); err != nil || flags != nil {
+		t.Fatalf("no parameter: expected: <nil>, got: %v, %v", flags, err)
+	}
+
+	for i, testCase := range testCases {
+		flags, err := getMountFlags(map[string]string{string(directpvtypes.MountFlagsLabelKey): testCase.value})
+		if testCase.expectErr != (err != nil) {
+			t.Fatalf("case %v: expected error: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if !reflect.DeepEqual(flags, testCase.expectedFlags) {
+			t.Fatalf("case %v: flags: expected: %v, got: %v", i+1, testCase.expectedFlags, flags)
+		}
+	}
+}
+
+func TestGetOwnership(t *testing.T) {
+	testCases := []struct {
+		parameters        map[string]string
+		expectedOwnership *types.VolumeOwnership
+		expectErr         bool
+	}{
+		{map[string]string{}, nil, false},
+		{map[string]string{string(directpvtypes.UIDLabelKey): "1000"}, &types.VolumeOwnership{UID: 1000}, false},
+		{
+			map[string]string{
+				string(directpvtypes.UIDLabelKey):  "1000",
+				string(directpvtypes.GIDLabelKey):  "2000",
+				string(directpvtypes.ModeLabelKey): "750",
+			},
+			&types.VolumeOwnership{UID: 1000, GID: 2000, Mode: "0750"},
+			false,
+		},
+		{map[string]string{string(directpvtypes.ModeLabelKey): "2775"}, &types.VolumeOwnership{Mode: "2775"}, false},
+		{map[string]string{string(directpvtypes.UIDLabelKey): "-1"}, nil, true},
+		{map[string]string{string(directpvtypes.GIDLabelKey): "abc"}, nil, true},
+		{map[string]string{string(directpvtypes.ModeLabelKey): "0799"}, nil, true},
+		{map[string]string{string(directpvtypes.ModeLabelKey): "17777"}, nil, true},
+	}
+
+	for i, testCase := range testCases {
+		ownership, err := getOwnership(testCase.parameters)
+		if testCase.expectErr != (err != nil) {
+			t.Fatalf("case %v: expected error: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if !reflect.DeepEqual(ownership, testCase.expectedOwnership) {
+			t.Fatalf("case %v: ownership: expected: %+v, got: %+v", i+1, testCase.expectedOwnership, ownership)
+		}
+	}
 }
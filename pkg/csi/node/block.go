@@ -0,0 +1,119 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"syscall"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"k8s.io/klog/v2"
+)
+
+func createFile(name string) error {
+	file, err := os.OpenFile(name, os.O_RDONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	return file.Close()
+}
+
+// fillVolume fills the volume directory from the content source if any and
+// sets up the block device of raw block volume.
+func (server *Server) fillVolume(ctx context.Context, volume *types.Volume, volumeDir string) (codes.Code, error) {
+	if code, err := server.cloneVolume(ctx, volume, volumeDir); err != nil {
+		return code, err
+	}
+
+	if volume.IsBlock() {
+		return server.stageBlockVolume(volume)
+	}
+
+	return codes.OK, nil
+}
+
+// stageBlockVolume preallocates the backing file of raw block volume in the
+// volume directory and attaches it to a loop device.
+func (server *Server) stageBlockVolume(volume *types.Volume) (codes.Code, error) {
+	blockFile := types.GetVolumeBlockFile(volume.Status.FSUUID, volume.Name)
+	if err := server.allocateFile(blockFile, volume.Status.TotalCapacity); err != nil {
+		klog.ErrorS(err, "unable to allocate block file", "volume", volume.Name, "blockFile", blockFile)
+		code := codes.Internal
+		if errors.Is(err, syscall.ENOSPC) {
+			code = codes.ResourceExhausted
+		}
+		return code, fmt.Errorf("unable to allocate block file %v; %w", blockFile, err)
+	}
+
+	device, err := server.attachLoopDevice(blockFile)
+	if err != nil {
+		klog.ErrorS(err, "unable to attach loop device", "volume", volume.Name, "blockFile", blockFile)
+		return codes.Internal, fmt.Errorf("unable to attach loop device to block file %v; %w", blockFile, err)
+	}
+
+	klog.V(5).InfoS("Block volume is attached to loop device", "volume", volume.Name, "device", device)
+	return codes.OK, nil
+}
+
+// publishBlockVolume bind-mounts the loop device of raw block volume to the target path file.
+func (server *Server) publishBlockVolume(req *csi.NodePublishVolumeRequest, volume *types.Volume, readOnly bool) error {
+	blockFile := types.GetVolumeBlockFile(volume.Status.FSUUID, volume.Name)
+	device, err := server.getLoopDevice(blockFile)
+	if err != nil {
+		return fmt.Errorf("unable to find loop device of block file %v; %w", blockFile, err)
+	}
+
+	targetPath := req.GetTargetPath()
+	if err := server.mkdir(path.Dir(targetPath)); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("unable to create parent directory of target path; %w", err)
+	}
+	if err := server.createFile(targetPath); err != nil {
+		return fmt.Errorf("unable to create target path; %w", err)
+	}
+
+	mountPointMap, _, err := server.getMounts()
+	if err != nil {
+		return err
+	}
+	if _, found := mountPointMap[targetPath]; found {
+		klog.V(5).InfoS("loop device is already bind-mounted to targetPath", "device", device, "targetPath", targetPath)
+		return nil
+	}
+
+	if err := server.bindMount(device, targetPath, readOnly, nil); err != nil {
+		return fmt.Errorf("unable to bind mount loop device %v to target path; %w", device, err)
+	}
+	return nil
+}
+
+// expandBlockVolume extends the backing file of raw block volume and updates its loop device size.
+func (server *Server) expandBlockVolume(volume *types.Volume, size int64) error {
+	blockFile := types.GetVolumeBlockFile(volume.Status.FSUUID, volume.Name)
+	if err := server.allocateFile(blockFile, size); err != nil {
+		return fmt.Errorf("unable to allocate block file %v; %w", blockFile, err)
+	}
+	if err := server.resizeLoopDevice(blockFile); err != nil {
+		return fmt.Errorf("unable to resize loop device of block file %v; %w", blockFile, err)
+	}
+	return nil
+}
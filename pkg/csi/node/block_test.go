@@ -0,0 +1,148 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	"github.com/minio/directpv/pkg/xfs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestBlockVolume(t *testing.T) {
+	volume := types.NewVolume("volume-1", "fsuuid-1", testNodeName, "drive-1", "sda", 20*MiB)
+	volume.SetBlock(directpvtypes.BlockAllocationLoopFile)
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(volume))
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+	blockFile := types.GetVolumeBlockFile("fsuuid-1", "volume-1")
+	blockCapability := &csi.VolumeCapability{
+		AccessType: &csi.VolumeCapability_Block{Block: &csi.VolumeCapability_BlockVolume{}},
+		AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+	}
+
+	var allocated map[string]int64
+	var attached, bindMounts map[string]string
+	ctx := context.TODO()
+	ns := createFakeServer()
+	ns.setQuota = func(_ context.Context, _, _ string, _ uint32, _ xfs.Quota, _ bool) error {
+		t.Fatalf("quota must not be set on block volume")
+		return nil
+	}
+	ns.allocateFile = func(name string, size int64) error {
+		allocated[name] = size
+		return nil
+	}
+	ns.attachLoopDevice = func(backingFile string) (string, error) {
+		attached[backingFile] = "/dev/loop1"
+		return "/dev/loop1", nil
+	}
+	ns.getLoopDevice = func(backingFile string) (string, error) {
+		return attached[backingFile], nil
+	}
+	ns.detachLoopDevice = func(backingFile string) error {
+		delete(attached, backingFile)
+		return nil
+	}
+	ns.bindMount = func(source, target string, _ bool, _ []string) error {
+		bindMounts[target] = source
+		return nil
+	}
+	allocated, attached, bindMounts = map[string]int64{}, map[string]string{}, map[string]string{}
+
+	if _, err := ns.NodeStageVolume(ctx, &csi.NodeStageVolumeRequest{
+		VolumeId:          "volume-1",
+		StagingTargetPath: "/path/to/staging",
+		VolumeCapability:  blockCapability,
+	}); err != nil {
+		t.Fatalf("unable to stage volume; %v", err)
+	}
+	if allocated[blockFile] != 20*MiB || attached[blockFile] != "/dev/loop1" {
+		t.Fatalf("block file is not allocated and attached; allocated: %v, attached: %v", allocated, attached)
+	}
+	if len(bindMounts) != 0 {
+		t.Fatalf("staging target path must not be mounted; got: %v", bindMounts)
+	}
+
+	ns.getMounts = func() (map[string]utils.StringSet, map[string]utils.StringSet, error) {
+		return map[string]utils.StringSet{}, map[string]utils.StringSet{}, nil
+	}
+	publishRequest := &csi.NodePublishVolumeRequest{
+		VolumeId:          "volume-1",
+		StagingTargetPath: "/path/to/staging",
+		TargetPath:        "/path/to/target/volume-1",
+		VolumeCapability: &csi.VolumeCapability{
+			AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+			AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+		},
+	}
+	if _, err := ns.NodePublishVolume(ctx, publishRequest); status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("expected: %v, got: %v", codes.InvalidArgument, err)
+	}
+
+	publishRequest.VolumeCapability = blockCapability
+	if _, err := ns.NodePublishVolume(ctx, publishRequest); err != nil {
+		t.Fatalf("unable to publish volume; %v", err)
+	}
+	if bindMounts["/path/to/target/volume-1"] != "/dev/loop1" {
+		t.Fatalf("loop device is not bind-mounted to target path; got: %v", bindMounts)
+	}
+
+	if _, err := ns.NodeExpandVolume(ctx, &csi.NodeExpandVolumeRequest{
+		VolumeId:      "volume-1",
+		VolumePath:    "/path/to/target/volume-1",
+		CapacityRange: &csi.CapacityRange{RequiredBytes: 30 * MiB},
+	}); err != nil {
+		t.Fatalf("unable to expand volume; %v", err)
+	}
+	if allocated[blockFile] != 30*MiB {
+		t.Fatalf("block file is not expanded; got: %v", allocated[blockFile])
+	}
+
+	if _, err := ns.NodeUnpublishVolume(ctx, &csi.NodeUnpublishVolumeRequest{
+		VolumeId:   "volume-1",
+		TargetPath: "/path/to/target/volume-1",
+	}); err != nil {
+		t.Fatalf("unable to unpublish volume; %v", err)
+	}
+	if _, err := ns.NodeUnstageVolume(ctx, &csi.NodeUnstageVolumeRequest{
+		VolumeId:          "volume-1",
+		StagingTargetPath: "/path/to/staging",
+	}); err != nil {
+		t.Fatalf("unable to unstage volume; %v", err)
+	}
+	if len(attached) != 0 {
+		t.Fatalf("loop device is not detached; got: %v", attached)
+	}
+
+	volume, err := client.VolumeClient().Get(ctx, "volume-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get volume; %v", err)
+	}
+	if volume.Status.TotalCapacity != 30*MiB || volume.Status.UsedCapacity != 30*MiB || volume.Status.AvailableCapacity != 0 {
+		t.Fatalf("unexpected volume capacity %+v", volume.Status)
+	}
+}
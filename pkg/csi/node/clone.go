@@ -0,0 +1,188 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const cloneProgressInterval = 5 * time.Second
+
+type cloneJob struct {
+	doneCh chan struct{}
+	err    error
+}
+
+// cloneJobs tracks running clones to avoid duplicate clones on retried stage requests.
+type cloneJobs struct {
+	mutex sync.Mutex
+	jobs  map[string]*cloneJob
+}
+
+func newCloneJobs() *cloneJobs {
+	return &cloneJobs{jobs: map[string]*cloneJob{}}
+}
+
+func (c *cloneJobs) start(volumeName string, cloneFunc func() error) *cloneJob {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	if job, found := c.jobs[volumeName]; found {
+		return job
+	}
+
+	job := &cloneJob{doneCh: make(chan struct{})}
+	c.jobs[volumeName] = job
+	go func() {
+		job.err = cloneFunc()
+
+		c.mutex.Lock()
+		delete(c.jobs, volumeName)
+		c.mutex.Unlock()
+
+		close(job.doneCh)
+	}()
+
+	return job
+}
+
+func updateCloneStatus(ctx context.Context, volumeName string, updateFunc func(clone *types.CloneStatus)) (*types.Volume, error) {
+	var volume *types.Volume
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+		volume, err = client.VolumeClient().Get(ctx, volumeName, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		if err != nil {
+			return err
+		}
+		if volume.Status.Clone == nil {
+			return fmt.Errorf("volume %v has no clone source", volumeName)
+		}
+		updateFunc(volume.Status.Clone)
+		volume, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		return err
+	})
+	return volume, err
+}
+
+// runClone fills volume directory from clone source and records the progress in volume status.
+func (server *Server) runClone(volume *types.Volume, volumeDir string) error {
+	ctx := context.Background()
+	clone := volume.Status.Clone
+
+	source := types.GetVolumeDir(clone.SourceFSUUID, clone.SourceVolume)
+	if clone.SourceSnapshot != "" {
+		source = types.GetSnapshotDir(clone.SourceFSUUID, clone.SourceSnapshot)
+	}
+	reflink := clone.SourceFSUUID == volume.Status.FSUUID
+
+	klog.V(3).InfoS("Cloning volume", "volume", volume.Name, "source", source, "reflink", reflink)
+
+	_, err := updateCloneStatus(ctx, volume.Name, func(clone *types.CloneStatus) {
+		clone.State = directpvtypes.CloneStateInProgress
+		clone.CopiedBytes = 0
+		clone.Error = ""
+	})
+	if err != nil {
+		return err
+	}
+
+	var lastUpdate time.Time
+	progress := func(copiedBytes, totalBytes int64) {
+		if time.Since(lastUpdate) < cloneProgressInterval {
+			return
+		}
+		lastUpdate = time.Now()
+		_, err := updateCloneStatus(ctx, volume.Name, func(clone *types.CloneStatus) {
+			clone.CopiedBytes = copiedBytes
+			clone.TotalBytes = totalBytes
+		})
+		if err != nil {
+			klog.ErrorS(err, "unable to update clone progress", "volume", volume.Name)
+		}
+	}
+
+	var totalBytes int64
+	cloneErr := server.copyData(ctx, source, volumeDir, reflink, func(copiedBytes, total int64) {
+		totalBytes = total
+		progress(copiedBytes, total)
+	})
+
+	updatedVolume, err := updateCloneStatus(ctx, volume.Name, func(clone *types.CloneStatus) {
+		if cloneErr != nil {
+			clone.State = directpvtypes.CloneStateFailed
+			clone.Error = cloneErr.Error()
+			return
+		}
+		clone.State = directpvtypes.CloneStateCompleted
+		clone.CopiedBytes = totalBytes
+		clone.TotalBytes = totalBytes
+	})
+	if err != nil {
+		klog.ErrorS(err, "unable to update clone status", "volume", volume.Name)
+		if cloneErr == nil {
+			return err
+		}
+	}
+
+	if cloneErr != nil {
+		klog.ErrorS(cloneErr, "unable to clone volume", "volume", volume.Name, "source", source)
+		client.Eventf(volume, client.EventTypeWarning, client.EventReasonVolumeCloneFailed, "unable to clone from %v; %v", source, cloneErr)
+		return cloneErr
+	}
+
+	client.Eventf(updatedVolume, client.EventTypeNormal, client.EventReasonVolumeCloned, "volume is cloned from %v", source)
+	return nil
+}
+
+// cloneVolume fills the volume from its clone source before the volume is
+// staged. Clone continues in background if the request context is done.
+func (server *Server) cloneVolume(ctx context.Context, volume *types.Volume, volumeDir string) (codes.Code, error) {
+	if volume.IsCloned() {
+		return codes.OK, nil
+	}
+
+	job := server.cloneJobs.start(volume.Name, func() error {
+		return server.runClone(volume.DeepCopy(), volumeDir)
+	})
+
+	select {
+	case <-ctx.Done():
+		return codes.Aborted, fmt.Errorf("clone of volume %v is in progress", volume.Name)
+	case <-job.doneCh:
+	}
+
+	if job.err != nil {
+		return codes.Internal, fmt.Errorf("unable to clone volume %v; %w", volume.Name, job.err)
+	}
+
+	latest, err := client.VolumeClient().Get(ctx, volume.Name, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+	if err != nil {
+		return codes.Internal, err
+	}
+	*volume = *latest
+	return codes.OK, nil
+}
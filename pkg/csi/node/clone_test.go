@@ -0,0 +1,123 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/xfs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNodeStageVolumeClone(t *testing.T) {
+	testCases := []struct {
+		clone           *types.CloneStatus
+		copyErr         error
+		expectedSource  string
+		expectedReflink bool
+		expectedState   directpvtypes.CloneState
+		expectedCode    codes.Code
+	}{
+		{
+			clone:           &types.CloneStatus{SourceVolume: "volume-1", SourceFSUUID: "fsuuid1", State: directpvtypes.CloneStatePending},
+			expectedSource:  types.GetVolumeDir("fsuuid1", "volume-1"),
+			expectedReflink: true,
+			expectedState:   directpvtypes.CloneStateCompleted,
+			expectedCode:    codes.OK,
+		},
+		{
+			clone:          &types.CloneStatus{SourceSnapshot: "snapshot-1", SourceFSUUID: "fsuuid2", State: directpvtypes.CloneStatePending},
+			expectedSource: types.GetSnapshotDir("fsuuid2", "snapshot-1"),
+			expectedState:  directpvtypes.CloneStateCompleted,
+			expectedCode:   codes.OK,
+		},
+		{
+			clone:           &types.CloneStatus{SourceVolume: "volume-1", SourceFSUUID: "fsuuid1", State: directpvtypes.CloneStateFailed},
+			copyErr:         errors.New("no space left on device"),
+			expectedSource:  types.GetVolumeDir("fsuuid1", "volume-1"),
+			expectedReflink: true,
+			expectedState:   directpvtypes.CloneStateFailed,
+			expectedCode:    codes.Internal,
+		},
+		{
+			clone:         &types.CloneStatus{SourceVolume: "volume-1", SourceFSUUID: "fsuuid1", State: directpvtypes.CloneStateCompleted},
+			expectedState: directpvtypes.CloneStateCompleted,
+			expectedCode:  codes.OK,
+		},
+	}
+
+	ctx := context.TODO()
+	for i, testCase := range testCases {
+		volume := types.NewVolume("volume-2", "fsuuid1", testNodeName, "drive-1", "sda", 100*MiB)
+		volume.Status.Clone = testCase.clone
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(volume))
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		var source, target string
+		var reflink bool
+		server := createFakeServer()
+		server.copyData = func(_ context.Context, s, t string, r bool, progress xfs.ProgressFunc) error {
+			source, target, reflink = s, t, r
+			progress(1024, 1024)
+			return testCase.copyErr
+		}
+
+		_, err := server.NodeStageVolume(ctx, &csi.NodeStageVolumeRequest{
+			VolumeId:          "volume-2",
+			StagingTargetPath: "/path/to/target",
+		})
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected code: %v, got: %v; %v", i+1, testCase.expectedCode, code, err)
+		}
+		if source != testCase.expectedSource || reflink != testCase.expectedReflink {
+			t.Fatalf("case %v: expected source: %v, reflink: %v; got: %v, %v", i+1, testCase.expectedSource, testCase.expectedReflink, source, reflink)
+		}
+		if source != "" && target != types.GetVolumeDir("fsuuid1", "volume-2") {
+			t.Fatalf("case %v: unexpected target %v", i+1, target)
+		}
+
+		result, err := client.VolumeClient().Get(ctx, "volume-2", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if result.Status.Clone.State != testCase.expectedState {
+			t.Fatalf("case %v: expected state: %v, got: %v", i+1, testCase.expectedState, result.Status.Clone.State)
+		}
+		switch testCase.expectedState {
+		case directpvtypes.CloneStateFailed:
+			if result.Status.Clone.Error == "" || result.IsStaged() {
+				t.Fatalf("case %v: expected clone error and unstaged volume; got %+v", i+1, result.Status)
+			}
+		case directpvtypes.CloneStateCompleted:
+			if !result.IsStaged() {
+				t.Fatalf("case %v: expected staged volume", i+1)
+			}
+			if source != "" && result.Status.Clone.CopiedBytes != 1024 {
+				t.Fatalf("case %v: expected copied bytes: 1024, got: %v", i+1, result.Status.Clone.CopiedBytes)
+			}
+		}
+	}
+}
@@ -0,0 +1,322 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/drive"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const (
+	ephemeralKey     = "csi.storage.k8s.io/ephemeral"
+	ephemeralSizeKey = "size"
+)
+
+var errDriveNotReservable = errors.New("drive does not satisfy the request")
+
+// isEphemeral returns whether the publish request is for a CSI ephemeral inline volume.
+func isEphemeral(req *csi.NodePublishVolumeRequest) bool {
+	return req.GetVolumeContext()[ephemeralKey] == strconv.FormatBool(true)
+}
+
+// parseEphemeralAttributes returns size and access-tier from volume attributes of an ephemeral inline volume.
+func parseEphemeralAttributes(volumeContext map[string]string) (size int64, accessTier directpvtypes.AccessTier, err error) {
+	value, found := volumeContext[ephemeralSizeKey]
+	if !found {
+		return 0, "", fmt.Errorf("required volume attribute %v not found", ephemeralSizeKey)
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, "", fmt.Errorf("invalid %v %v; %w", ephemeralSizeKey, value, err)
+	}
+	if size = quantity.Value(); size <= 0 {
+		return 0, "", fmt.Errorf("invalid %v %v; value must be positive", ephemeralSizeKey, value)
+	}
+
+	if value, found := volumeContext[string(directpvtypes.AccessTierLabelKey)]; found {
+		accessTiers, err := directpvtypes.StringsToAccessTiers(value)
+		if err != nil {
+			return 0, "", fmt.Errorf("unknown access-tier %v; %w", value, err)
+		}
+		if len(accessTiers) > 0 {
+			accessTier = accessTiers[0]
+		}
+	}
+
+	return size, accessTier, nil
+}
+
+// selectEphemeralDrive selects a ready drive of this node having largest free capacity.
+func (server *Server) selectEphemeralDrive(ctx context.Context, size int64, accessTier directpvtypes.AccessTier) (*types.Drive, error) {
+	drives, err := client.NewDriveLister().
+		NodeSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(server.nodeID))}).
+		StatusSelector([]directpvtypes.DriveStatus{directpvtypes.DriveStatusReady}).
+		Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected *types.Drive
+	for i := range drives {
+		switch {
+		case !drives[i].GetDeletionTimestamp().IsZero(),
+			drives[i].IsUnschedulable(),
+			drives[i].Status.FreeCapacity < size,
+			accessTier != "" && drives[i].GetAccessTier() != accessTier:
+			continue
+		}
+		if selected == nil || drives[i].Status.FreeCapacity > selected.Status.FreeCapacity {
+			selected = &drives[i]
+		}
+	}
+
+	if selected == nil {
+		return nil, errDriveNotReservable
+	}
+	return selected, nil
+}
+
+// reserveEphemeralDrive adds the volume to its drive and reserves the size. It
+// is a no-op if the volume is already in the drive.
+func reserveEphemeralDrive(ctx context.Context, volume *types.Volume) error {
+	updateFunc := func() error {
+		drive, err := client.DriveClient().Get(ctx, string(volume.GetDriveID()), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return err
+		}
+
+		if drive.VolumeExist(volume.Name) {
+			return nil
+		}
+
+		switch {
+		case !drive.GetDeletionTimestamp().IsZero(),
+			drive.Status.Status != directpvtypes.DriveStatusReady,
+			drive.IsUnschedulable(),
+			drive.Status.FreeCapacity < volume.Status.TotalCapacity:
+			return errDriveNotReservable
+		}
+
+		drive.AddVolumeFinalizer(volume.Name)
+		drive.Status.FreeCapacity -= volume.Status.TotalCapacity
+		drive.Status.AllocatedCapacity += volume.Status.TotalCapacity
+		_, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, updateFunc)
+}
+
+// deleteEphemeralVolume deletes the volume. Volume controller of this node
+// removes its data and releases its size from the drive.
+func deleteEphemeralVolume(ctx context.Context, volumeName string) error {
+	updateFunc := func() error {
+		volume, err := client.VolumeClient().Get(ctx, volumeName, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		if err != nil {
+			return err
+		}
+		if volume.IsReleased() {
+			return nil
+		}
+		volume.RemovePVProtection()
+		_, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		return err
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	if err := client.VolumeClient().Delete(ctx, volumeName, metav1.DeleteOptions{}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// getEphemeralVolume returns the ephemeral volume of the request; the volume is
+// created on a selected drive if not found. As the volume is created before
+// reserving the drive, reservation is redone on retry after node server restart.
+func (server *Server) getEphemeralVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*types.Volume, error) {
+	volume, err := client.VolumeClient().Get(ctx, req.GetVolumeId(), metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+	switch {
+	case err == nil:
+		if !volume.IsEphemeral() {
+			return nil, status.Errorf(codes.AlreadyExists, "volume %v is not an ephemeral volume", req.GetVolumeId())
+		}
+	case apierrors.IsNotFound(err):
+		size, accessTier, err := parseEphemeralAttributes(req.GetVolumeContext())
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "invalid ephemeral volume %v; %v", req.GetVolumeId(), err)
+		}
+
+		drive, err := server.selectEphemeralDrive(ctx, size, accessTier)
+		if err != nil {
+			if errors.Is(err, errDriveNotReservable) {
+				return nil, status.Errorf(codes.ResourceExhausted, "no drive found for ephemeral volume %v of size %v", req.GetVolumeId(), size)
+			}
+			return nil, status.Errorf(codes.Internal, "unable to select drive for ephemeral volume %v; %v", req.GetVolumeId(), err)
+		}
+
+		volume = types.NewVolume(req.GetVolumeId(), drive.Status.FSUUID, drive.GetNodeID(), drive.GetDriveID(), drive.GetDriveName(), size)
+		volume.SetEphemeral()
+		if volume, err = client.VolumeClient().Create(ctx, volume, metav1.CreateOptions{}); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to create ephemeral volume %v; %v", req.GetVolumeId(), err)
+		}
+		client.Eventf(volume, client.EventTypeNormal, client.EventReasonVolumeProvisioned, "ephemeral volume is created")
+	default:
+		return nil, status.Errorf(codes.Internal, "unable to get volume %v; %v", req.GetVolumeId(), err)
+	}
+
+	if err := reserveEphemeralDrive(ctx, volume); err != nil {
+		if !errors.Is(err, errDriveNotReservable) {
+			return nil, status.Errorf(codes.Internal, "unable to reserve drive %v for ephemeral volume %v; %v", volume.GetDriveID(), volume.Name, err)
+		}
+		if err := deleteEphemeralVolume(ctx, volume.Name); err != nil {
+			klog.ErrorS(err, "unable to delete ephemeral volume", "volume", volume.Name)
+		}
+		return nil, status.Errorf(codes.ResourceExhausted, "drive %v has no free capacity for ephemeral volume %v", volume.GetDriveID(), volume.Name)
+	}
+
+	return volume, nil
+}
+
+// publishEphemeralVolume creates, reserves and quotas the ephemeral volume on
+// a drive of this node and bind-mounts it to the target path.
+func (server *Server) publishEphemeralVolume(ctx context.Context, req *csi.NodePublishVolumeRequest) (*csi.NodePublishVolumeResponse, error) {
+	if req.GetVolumeCapability().GetBlock() != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "raw block ephemeral volume %v is not supported", req.GetVolumeId())
+	}
+	if fsType := req.GetVolumeCapability().GetMount().GetFsType(); fsType != "" && fsType != "xfs" {
+		return nil, status.Errorf(codes.InvalidArgument, "unsupported filesystem type %v for ephemeral volume %v", fsType, req.GetVolumeId())
+	}
+
+	volume, err := server.getEphemeralVolume(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if volume.Status.DataPath == "" {
+		code, err := drive.StageVolume(
+			ctx,
+			volume,
+			"",
+			server.getDeviceByFSUUID,
+			server.mkdir,
+			server.setOwnership,
+			server.setQuota,
+			server.bindMount,
+			func() (rootMap map[string]utils.StringSet, err error) {
+				_, rootMap, err = server.getMounts()
+				return
+			},
+			nil,
+		)
+		if err != nil {
+			return nil, status.Error(code, err.Error())
+		}
+	}
+
+	targetPath := req.GetTargetPath()
+	if err := server.mkdir(targetPath); err != nil && !errors.Is(err, os.ErrExist) {
+		return nil, status.Errorf(codes.Internal, "unable to create target path; %v", err)
+	}
+	mountMap, rootMap, err := server.getMounts()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if mountPoints, found := rootMap["/"+volume.Name]; found && mountPoints.Exist(targetPath) {
+		klog.V(5).InfoS("ephemeral volume is already bind-mounted to targetPath", "volume", volume.Name, "targetPath", targetPath)
+	} else {
+		if _, found := mountMap[targetPath]; found {
+			// Target path is left mounted to a removed volume of same ID.
+			if err := server.unmount(targetPath); err != nil {
+				return nil, status.Errorf(codes.Internal, "unable to unmount target path %v; %v", targetPath, err)
+			}
+		}
+		if err := server.bindMount(volume.Status.DataPath, targetPath, req.GetReadonly(), volume.Status.MountFlags); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to bind mount ephemeral volume to target path; %v", err)
+		}
+	}
+
+	podName, podNS, _, _ := getPodInfo(ctx, req)
+	updateFunc := func() (err error) {
+		if volume == nil {
+			if volume, err = client.VolumeClient().Get(ctx, req.GetVolumeId(), metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()}); err != nil {
+				return err
+			}
+		}
+
+		volume.SetPodName(podName)
+		volume.SetPodNS(podNS)
+		volume.AddTargetPath(targetPath, req.GetReadonly())
+		if _, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{TypeMeta: types.NewVolumeTypeMeta()}); err != nil {
+			volume = nil
+		}
+		return err
+	}
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		return nil, status.Errorf(codes.Internal, "unable to update volume: %v", err)
+	}
+
+	return &csi.NodePublishVolumeResponse{}, nil
+}
+
+// CleanupEphemeralVolumes deletes unpublished ephemeral volumes of this node
+// left by node server restart in the middle of publishing or unpublishing.
+func CleanupEphemeralVolumes(ctx context.Context, nodeID directpvtypes.NodeID) error {
+	volumes, err := client.NewVolumeLister().
+		NodeSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(nodeID))}).
+		LabelSelector(map[directpvtypes.LabelKey]directpvtypes.LabelValue{
+			directpvtypes.EphemeralLabelKey: directpvtypes.ToLabelValue(strconv.FormatBool(true)),
+		}).
+		Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, volume := range volumes {
+		if volume.IsPublished() || !volume.GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if err := deleteEphemeralVolume(ctx, volume.Name); err != nil {
+			return err
+		}
+		klog.V(3).InfoS("Deleted unpublished ephemeral volume", "volume", volume.Name)
+	}
+
+	return nil
+}
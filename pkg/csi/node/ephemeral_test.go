@@ -0,0 +1,148 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"testing"
+
+	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/k8s"
+	"github.com/minio/directpv/pkg/types"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestEphemeralVolume(t *testing.T) {
+	k8s.SetKubeInterface(kubernetesfake.NewSimpleClientset())
+
+	newDrive := func(driveID directpvtypes.DriveID, freeCapacity int64, accessTier directpvtypes.AccessTier) *types.Drive {
+		return types.NewDrive(
+			driveID,
+			types.DriveStatus{
+				TotalCapacity: freeCapacity,
+				FreeCapacity:  freeCapacity,
+				Status:        directpvtypes.DriveStatusReady,
+				FSUUID:        string(driveID),
+			},
+			testNodeName,
+			directpvtypes.DriveName(driveID),
+			accessTier,
+		)
+	}
+	newRequest := func(volumeID string, volumeContext map[string]string) *csi.NodePublishVolumeRequest {
+		volumeContext[ephemeralKey] = "true"
+		return &csi.NodePublishVolumeRequest{
+			VolumeId:   volumeID,
+			TargetPath: "/path/to/" + volumeID,
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			},
+			VolumeContext: volumeContext,
+		}
+	}
+	getDrive := func(driveID string) *types.Drive {
+		drive, err := client.DriveClient().Get(context.TODO(), driveID, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unable to get drive %v; %v", driveID, err)
+		}
+		return drive
+	}
+
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(
+		newDrive("drive-1", 100*MiB, directpvtypes.AccessTierDefault),
+		newDrive("drive-2", 50*MiB, directpvtypes.AccessTierHot),
+	))
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+	ns := createFakeServer()
+
+	// Publish is idempotent and reserves drive capacity once.
+	for i := 0; i < 2; i++ {
+		if _, err := ns.NodePublishVolume(context.TODO(), newRequest("csi-1", map[string]string{ephemeralSizeKey: "10Mi"})); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+	volume, err := client.VolumeClient().Get(context.TODO(), "csi-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("unable to get volume; %v", err)
+	}
+	if !volume.IsEphemeral() || volume.GetDriveID() != "drive-1" || volume.Status.TotalCapacity != 10*MiB ||
+		volume.Status.Status != directpvtypes.VolumeStatusReady || !volume.IsPublished() {
+		t.Fatalf("unexpected volume %+v", volume)
+	}
+	if drive := getDrive("drive-1"); !drive.VolumeExist("csi-1") || drive.Status.FreeCapacity != 90*MiB {
+		t.Fatalf("unexpected drive status %+v", drive.Status)
+	}
+
+	// Access tier selects the matching drive.
+	if _, err := ns.NodePublishVolume(context.TODO(), newRequest("csi-2", map[string]string{
+		ephemeralSizeKey: "10Mi", string(directpvtypes.AccessTierLabelKey): "hot",
+	})); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if drive := getDrive("drive-2"); !drive.VolumeExist("csi-2") || drive.Status.FreeCapacity != 40*MiB {
+		t.Fatalf("unexpected drive status %+v", drive.Status)
+	}
+
+	errorCases := []struct {
+		volumeContext map[string]string
+		expectedCode  codes.Code
+	}{
+		{map[string]string{}, codes.InvalidArgument},
+		{map[string]string{ephemeralSizeKey: "-1Mi"}, codes.InvalidArgument},
+		{map[string]string{ephemeralSizeKey: "1Gi"}, codes.ResourceExhausted},
+		{map[string]string{ephemeralSizeKey: "60Mi", string(directpvtypes.AccessTierLabelKey): "hot"}, codes.ResourceExhausted},
+	}
+	for i, testCase := range errorCases {
+		_, err := ns.NodePublishVolume(context.TODO(), newRequest("csi-3", testCase.volumeContext))
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedCode, err)
+		}
+	}
+
+	// Unpublish deletes the volume.
+	if _, err := ns.NodeUnpublishVolume(context.TODO(), &csi.NodeUnpublishVolumeRequest{VolumeId: "csi-1", TargetPath: "/path/to/csi-1"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := client.VolumeClient().Get(context.TODO(), "csi-1", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected volume to be deleted; %v", err)
+	}
+
+	// Unpublished ephemeral volume left by restart is deleted; published volume is retained.
+	volume = types.NewVolume("csi-4", "drive-1", testNodeName, "drive-1", "drive-1", 10*MiB)
+	volume.SetEphemeral()
+	if _, err := client.VolumeClient().Create(context.TODO(), volume, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := CleanupEphemeralVolumes(context.TODO(), testNodeName); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := client.VolumeClient().Get(context.TODO(), "csi-4", metav1.GetOptions{}); !apierrors.IsNotFound(err) {
+		t.Fatalf("expected volume csi-4 to be deleted; %v", err)
+	}
+	if _, err := client.VolumeClient().Get(context.TODO(), "csi-2", metav1.GetOptions{}); err != nil {
+		t.Fatalf("expected volume csi-2 to be retained; %v", err)
+	}
+}
@@ -21,6 +21,8 @@ import (
 	"errors"
 
 	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/sys"
+	"github.com/minio/directpv/pkg/types"
 	"github.com/minio/directpv/pkg/utils"
 	"github.com/minio/directpv/pkg/xfs"
 )
@@ -38,12 +40,12 @@ func createFakeServer() *Server {
 			return map[string]utils.StringSet{consts.MountRootDir: nil}, map[string]utils.StringSet{consts.MountRootDir: nil}, nil
 		},
 		getDeviceByFSUUID: func(_ string) (string, error) { return "", nil },
-		bindMount:         func(_, _ string, _ bool) error { return nil },
+		bindMount:         func(_, _ string, _ bool, _ []string) error { return nil },
 		unmount:           func(_ string) error { return nil },
-		getQuota: func(_ context.Context, _, _ string) (quota *xfs.Quota, err error) {
+		getQuota: func(_ context.Context, _ string, _ uint32) (quota *xfs.Quota, err error) {
 			return &xfs.Quota{}, nil
 		},
-		setQuota: func(_ context.Context, _, _, _ string, _ xfs.Quota, _ bool) (err error) {
+		setQuota: func(_ context.Context, _, _ string, _ uint32, _ xfs.Quota, _ bool) (err error) {
 			return nil
 		},
 		mkdir: func(path string) error {
@@ -52,5 +54,19 @@ func createFakeServer() *Server {
 			}
 			return nil
 		},
+		setOwnership:  func(_ string, _ *types.VolumeOwnership) error { return nil },
+		setMountGroup: func(_ string, _ int, _ bool) error { return nil },
+		copyData: func(_ context.Context, _, _ string, _ bool, _ xfs.ProgressFunc) error {
+			return nil
+		},
+		createFile:       func(_ string) error { return nil },
+		allocateFile:     func(_ string, _ int64) error { return nil },
+		getLoopDevice:    func(_ string) (string, error) { return "/dev/loop0", nil },
+		attachLoopDevice: func(_ string) (string, error) { return "/dev/loop0", nil },
+		detachLoopDevice: func(_ string) error { return nil },
+		resizeLoopDevice: func(_ string) error { return nil },
+		statPath:         func(_ string) error { return nil },
+		setIOMax:         func(_, _ string, _ sys.IOMax) (string, error) { return "8:0", nil },
+		cloneJobs:        newCloneJobs(),
 	}
 }
@@ -0,0 +1,62 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/sys"
+	"github.com/minio/directpv/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+// applyIOLimits sets I/O limits of the volume on the cgroup of the pod and
+// returns MAJOR:MINOR of the throttled disk. As pod cgroup is created for every
+// pod start, limits are applied on each publish. Failure is reported by event
+// without failing the publish.
+func (server *Server) applyIOLimits(volume *types.Volume, podName, podNS, podUID string) string {
+	ioLimits := volume.Status.IOLimits
+	if ioLimits == nil {
+		return ""
+	}
+
+	if podUID == "" {
+		klog.ErrorS(nil, "unable to apply I/O limits; pod UID not found", "volume", volume.Name, "pod", podName, "namespace", podNS)
+		return ""
+	}
+
+	device, err := server.getDeviceByFSUUID(volume.Status.FSUUID)
+	if err == nil {
+		var majorMinor string
+		majorMinor, err = server.setIOMax(podUID, device, sys.IOMax{
+			ReadBPS:   ioLimits.ReadBytesPerSec,
+			WriteBPS:  ioLimits.WriteBytesPerSec,
+			ReadIOPS:  ioLimits.ReadIOPS,
+			WriteIOPS: ioLimits.WriteIOPS,
+		})
+		if err == nil {
+			klog.V(3).InfoS("I/O limits applied", "volume", volume.Name, "pod", podName, "namespace", podNS, "device", majorMinor)
+			return majorMinor
+		}
+	}
+
+	klog.ErrorS(err, "unable to apply I/O limits", "volume", volume.Name, "pod", podName, "namespace", podNS)
+	client.Eventf(
+		volume, client.EventTypeWarning, client.EventReasonVolumeIOLimitsFailed,
+		"unable to apply I/O limits to pod %v/%v; %v", podNS, podName, err,
+	)
+	return ""
+}
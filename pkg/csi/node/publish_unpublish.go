@@ -21,6 +21,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
@@ -31,8 +32,10 @@ import (
 	"github.com/minio/directpv/pkg/types"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
 	"k8s.io/klog/v2"
 )
 
@@ -41,6 +44,8 @@ const (
 	podNamespaceKey = "csi.storage.k8s.io/pod.namespace"
 )
 
+var errSingleWriterPublished = errors.New("single writer volume is already published")
+
 func parseVolumeContext(volumeContext map[string]string) (name, ns string, err error) {
 	parseValue := func(key string) (string, error) {
 		value, ok := volumeContext[key]
@@ -63,7 +68,7 @@ func parseVolumeContext(volumeContext map[string]string) (name, ns string, err e
 	return
 }
 
-func getPodInfo(ctx context.Context, req *csi.NodePublishVolumeRequest) (podName, podNS string, podLabels map[string]string) {
+func getPodInfo(ctx context.Context, req *csi.NodePublishVolumeRequest) (podName, podNS, podUID string, podLabels map[string]string) {
 	var err error
 	if podName, podNS, err = parseVolumeContext(req.GetVolumeContext()); err != nil {
 		klog.ErrorS(err, "unable to parse volume context", "context", req.GetVolumeContext(), "volume", req.GetVolumeId())
@@ -73,12 +78,33 @@ func getPodInfo(ctx context.Context, req *csi.NodePublishVolumeRequest) (podName
 	if pod, err := k8s.KubeClient().CoreV1().Pods(podNS).Get(ctx, podName, metav1.GetOptions{}); err != nil {
 		klog.ErrorS(err, "unable to get pod information", "name", podName, "namespace", podNS)
 	} else {
+		podUID = string(pod.GetUID())
 		podLabels = pod.GetLabels()
 	}
 
 	return
 }
 
+// isFSGroupChangeOnRootMismatch returns whether fsGroupChangePolicy of the pod
+// is OnRootMismatch. Kubelet does not pass the policy when volume mount group
+// is delegated to the driver; hence it is read from the pod.
+func isFSGroupChangeOnRootMismatch(ctx context.Context, req *csi.NodePublishVolumeRequest) bool {
+	podName, podNS, err := parseVolumeContext(req.GetVolumeContext())
+	if err != nil {
+		return false
+	}
+
+	pod, err := k8s.KubeClient().CoreV1().Pods(podNS).Get(ctx, podName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get pod information", "name", podName, "namespace", podNS)
+		return false
+	}
+
+	securityContext := pod.Spec.SecurityContext
+	return securityContext != nil && securityContext.FSGroupChangePolicy != nil &&
+		*securityContext.FSGroupChangePolicy == corev1.FSGroupChangeOnRootMismatch
+}
+
 func isDriveSuspended(ctx context.Context, driveID directpvtypes.DriveID) bool {
 	drive, err := client.DriveClient().Get(ctx, string(driveID), metav1.GetOptions{
 		TypeMeta: types.NewDriveTypeMeta(),
@@ -102,6 +128,11 @@ func (server *Server) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		return nil, status.Error(codes.InvalidArgument, "volume ID must not be empty")
 	}
 
+	if req.GetTargetPath() != "" && isEphemeral(req) {
+		// Ephemeral inline volume is not staged.
+		return server.publishEphemeralVolume(ctx, req)
+	}
+
 	if req.GetStagingTargetPath() == "" {
 		return nil, status.Error(codes.InvalidArgument, "Staging target path must not be empty")
 	}
@@ -115,37 +146,99 @@ func (server *Server) NodePublishVolume(ctx context.Context, req *csi.NodePublis
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
+	if block := req.GetVolumeCapability().GetBlock() != nil; block != volume.IsBlock() {
+		return nil, status.Errorf(codes.InvalidArgument, "volume %v is requested with mismatching volume mode; block requested: %v", volume.Name, block)
+	}
+
 	isSuspended := volume.IsSuspended() || isDriveSuspended(ctx, volume.GetDriveID())
 	if !isSuspended && volume.Status.StagingTargetPath != req.GetStagingTargetPath() {
 		return nil, status.Errorf(codes.FailedPrecondition, "volume %v is not yet staged, but requested with %v", volume.Name, req.GetStagingTargetPath())
 	}
 
-	if err := server.publishVolume(req, isSuspended); err != nil {
+	accessMode := req.GetVolumeCapability().GetAccessMode().GetMode()
+	if err := checkSingleWriter(volume, accessMode, req.GetTargetPath()); err != nil {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+
+	readOnly := req.GetReadonly() || accessMode == csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY
+	if volume.IsBlock() {
+		if isSuspended {
+			return nil, status.Errorf(codes.FailedPrecondition, "suspended block volume %v cannot be published", volume.Name)
+		}
+		err = server.publishBlockVolume(req, volume, readOnly)
+	} else {
+		err = server.publishVolume(ctx, req, volume, readOnly, isSuspended)
+	}
+	if err != nil {
 		klog.Errorf("unable to publish volume %s; %v", volume.Name, err)
 		return nil, status.Errorf(codes.Internal, "unable to publish volume; %v", err)
 	}
 
-	podName, podNS, podLabels := getPodInfo(ctx, req)
-	volume.SetPodName(podName)
-	volume.SetPodNS(podNS)
-	for key, value := range podLabels {
-		if strings.HasPrefix(key, consts.GroupName+"/") {
-			volume.SetLabel(directpvtypes.LabelKey(key), directpvtypes.LabelValue(value))
+	podName, podNS, podUID, podLabels := getPodInfo(ctx, req)
+	ioLimitsDevice := server.applyIOLimits(volume, podName, podNS, podUID)
+	updateFunc := func() (err error) {
+		if volume == nil {
+			volume, err = client.VolumeClient().Get(ctx, req.GetVolumeId(), metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+			if err != nil {
+				return err
+			}
+			// Concurrent publish may have happened in the meantime.
+			if err = checkSingleWriter(volume, accessMode, req.GetTargetPath()); err != nil {
+				return err
+			}
 		}
+
+		volume.SetPodName(podName)
+		volume.SetPodNS(podNS)
+		for key, value := range podLabels {
+			if strings.HasPrefix(key, consts.GroupName+"/") {
+				volume.SetLabel(directpvtypes.LabelKey(key), directpvtypes.LabelValue(value))
+			}
+		}
+		// Suspended volume is always published as read-only.
+		volume.AddTargetPath(req.GetTargetPath(), readOnly || isSuspended)
+		if ioLimitsDevice != "" && volume.Status.IOLimits != nil {
+			volume.Status.IOLimits.Device = ioLimitsDevice
+		}
+
+		if _, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
+			TypeMeta: types.NewVolumeTypeMeta(),
+		}); err != nil {
+			volume = nil
+		}
+		return err
 	}
 
-	volume.Status.TargetPath = req.GetTargetPath()
-	_, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
-		TypeMeta: types.NewVolumeTypeMeta(),
-	})
-	if err != nil {
+	if err = retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		if errors.Is(err, errSingleWriterPublished) {
+			if err := server.unmount(req.GetTargetPath()); err != nil {
+				klog.ErrorS(err, "unable to unmount target path", "TargetPath", req.GetTargetPath())
+			}
+			return nil, status.Error(codes.FailedPrecondition, err.Error())
+		}
 		return nil, status.Errorf(codes.Internal, "unable to update volume: %v", err)
 	}
 
 	return &csi.NodePublishVolumeResponse{}, nil
 }
 
-func (server *Server) publishVolume(req *csi.NodePublishVolumeRequest, isSuspended bool) error {
+// checkSingleWriter checks whether the volume is published to other target
+// path for SINGLE_NODE_SINGLE_WRITER access mode.
+func checkSingleWriter(volume *types.Volume, accessMode csi.VolumeCapability_AccessMode_Mode, targetPath string) error {
+	if accessMode != csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER {
+		return nil
+	}
+
+	for _, value := range volume.GetTargetPaths() {
+		if value != targetPath {
+			return fmt.Errorf("%w; volume %v is already published at %v", errSingleWriterPublished, volume.Name, value)
+		}
+	}
+
+	return nil
+}
+
+func (server *Server) publishVolume(ctx context.Context, req *csi.NodePublishVolumeRequest, volume *types.Volume, readOnly, isSuspended bool) error {
 	if err := server.mkdir(req.GetTargetPath()); err != nil && !errors.Is(err, os.ErrExist) {
 		return fmt.Errorf("unable to create target path; %v", err)
 	}
@@ -162,7 +255,7 @@ func (server *Server) publishVolume(req *csi.NodePublishVolumeRequest, isSuspend
 			klog.V(5).InfoS("stagingTargetPath is already bind-mounted to tmpfs mount", "stagingTargetPath", req.GetStagingTargetPath(), "targetPath", req.GetTargetPath())
 			return nil
 		}
-		if err := server.bindMount(consts.TmpMountDir, req.GetTargetPath(), true); err != nil {
+		if err := server.bindMount(consts.TmpMountDir, req.GetTargetPath(), true, nil); err != nil {
 			return fmt.Errorf("unable to bind mount target path %v to %v; %v", req.GetTargetPath(), consts.TmpMountDir, err)
 		}
 		return nil
@@ -171,10 +264,20 @@ func (server *Server) publishVolume(req *csi.NodePublishVolumeRequest, isSuspend
 	if !found {
 		return fmt.Errorf("stagingPath %v is not mounted", req.GetStagingTargetPath())
 	}
+	if volumeMountGroup := req.GetVolumeCapability().GetMount().GetVolumeMountGroup(); volumeMountGroup != "" {
+		// fsGroup of the pod is applied here instead of recursive ownership change by kubelet.
+		gid, err := strconv.ParseUint(volumeMountGroup, 10, 32)
+		if err != nil {
+			return fmt.Errorf("invalid volume mount group %v; %w", volumeMountGroup, err)
+		}
+		if err := server.setMountGroup(req.GetStagingTargetPath(), int(gid), isFSGroupChangeOnRootMismatch(ctx, req)); err != nil {
+			return fmt.Errorf("unable to set volume mount group %v; %w", volumeMountGroup, err)
+		}
+	}
 	if targetPathDevices, found := mountPointMap[req.GetTargetPath()]; found && targetPathDevices.Equal(stagingTargetPathDevices) {
 		klog.V(5).InfoS("stagingTargetPath is already bind-mounted to targetPath", "stagingTargetPath", req.GetStagingTargetPath(), "targetPath", req.GetTargetPath())
 	} else {
-		if err := server.bindMount(req.GetStagingTargetPath(), req.GetTargetPath(), req.GetReadonly()); err != nil {
+		if err := server.bindMount(req.GetStagingTargetPath(), req.GetTargetPath(), readOnly, volume.Status.MountFlags); err != nil {
 			return fmt.Errorf("unable to bind mount staging target path to target path; %v", err)
 		}
 	}
@@ -211,12 +314,40 @@ func (server *Server) NodeUnpublishVolume(ctx context.Context, req *csi.NodeUnpu
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
-	if volume.Status.TargetPath == targetPath {
-		volume.Status.TargetPath = ""
-		if _, err := client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
+	if volume.IsBlock() {
+		// Target path of raw block volume is a file created by publish.
+		if err := os.Remove(targetPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			klog.ErrorS(err, "unable to remove target path", "TargetPath", targetPath)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
+	updateFunc := func() (err error) {
+		if volume == nil {
+			if volume, err = client.VolumeClient().Get(ctx, volumeID, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()}); err != nil {
+				return err
+			}
+		}
+
+		if !volume.RemoveTargetPath(targetPath) {
+			return nil
+		}
+		if _, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
 			TypeMeta: types.NewVolumeTypeMeta(),
 		}); err != nil {
-			return nil, err
+			volume = nil
+		}
+		return err
+	}
+
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		return nil, err
+	}
+
+	if volume.IsEphemeral() && !volume.IsPublished() {
+		if err := deleteEphemeralVolume(ctx, volumeID); err != nil {
+			klog.ErrorS(err, "unable to delete ephemeral volume", "volume", volumeID)
+			return nil, status.Error(codes.Internal, err.Error())
 		}
 	}
 
@@ -18,16 +18,25 @@ package node
 
 import (
 	"context"
+	"errors"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
 	"github.com/minio/directpv/pkg/client"
 	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/k8s"
+	"github.com/minio/directpv/pkg/sys"
 	"github.com/minio/directpv/pkg/types"
 	"github.com/minio/directpv/pkg/utils"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
 )
 
 func TestNodePublishVolume(t *testing.T) {
@@ -151,3 +160,296 @@ func TestPublishUnpublishVolume(t *testing.T) {
 		t.Errorf("StagingPath was not set to empty. Got: %v", volObj.Status.TargetPath)
 	}
 }
+
+func TestPublishVolumeAccessModes(t *testing.T) {
+	newRequest := func(targetPath string, mode csi.VolumeCapability_AccessMode_Mode) *csi.NodePublishVolumeRequest {
+		return &csi.NodePublishVolumeRequest{
+			VolumeId:          "volume-1",
+			StagingTargetPath: "/path/to/staging",
+			TargetPath:        targetPath,
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: mode},
+			},
+		}
+	}
+
+	testCases := []struct {
+		mode             csi.VolumeCapability_AccessMode_Mode
+		expectedCode     codes.Code
+		expectedReadOnly bool
+	}{
+		{csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER, codes.OK, false},
+		{csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER, codes.OK, false},
+		{csi.VolumeCapability_AccessMode_SINGLE_NODE_READER_ONLY, codes.OK, true},
+		{csi.VolumeCapability_AccessMode_SINGLE_NODE_SINGLE_WRITER, codes.FailedPrecondition, false},
+	}
+
+	for i, testCase := range testCases {
+		volume := types.NewVolume("volume-1", "fsuuid-1", testNodeName, "drive-1", "sda", 20*MiB)
+		volume.Status.StagingTargetPath = "/path/to/staging"
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(volume))
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		readOnlyMounts := map[string]bool{}
+		ns := createFakeServer()
+		ns.getMounts = func() (map[string]utils.StringSet, map[string]utils.StringSet, error) {
+			return map[string]utils.StringSet{"/path/to/staging": nil}, map[string]utils.StringSet{}, nil
+		}
+		ns.bindMount = func(_, target string, readOnly bool, _ []string) error {
+			readOnlyMounts[target] = readOnly
+			return nil
+		}
+
+		if _, err := ns.NodePublishVolume(context.TODO(), newRequest("/path/to/target-1", testCase.mode)); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		_, err := ns.NodePublishVolume(context.TODO(), newRequest("/path/to/target-2", testCase.mode))
+		if code := status.Code(err); code != testCase.expectedCode {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedCode, err)
+		}
+		if readOnlyMounts["/path/to/target-1"] != testCase.expectedReadOnly {
+			t.Fatalf("case %v: read-only: expected: %v, got: %v", i+1, testCase.expectedReadOnly, readOnlyMounts["/path/to/target-1"])
+		}
+
+		expectedTargetPaths := []string{"/path/to/target-1"}
+		if testCase.expectedCode == codes.OK {
+			expectedTargetPaths = append(expectedTargetPaths, "/path/to/target-2")
+		}
+		volume, err = client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unable to get volume; %v", i+1, err)
+		}
+		if !reflect.DeepEqual(volume.GetTargetPaths(), expectedTargetPaths) {
+			t.Fatalf("case %v: target paths: expected: %v, got: %v", i+1, expectedTargetPaths, volume.GetTargetPaths())
+		}
+
+		if _, err := ns.NodeUnpublishVolume(context.TODO(), &csi.NodeUnpublishVolumeRequest{VolumeId: "volume-1", TargetPath: "/path/to/target-1"}); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		volume, err = client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unable to get volume; %v", i+1, err)
+		}
+		if volume.IsPublished() != (testCase.expectedCode == codes.OK) {
+			t.Fatalf("case %v: unexpected target paths %v", i+1, volume.GetTargetPaths())
+		}
+		if testCase.expectedCode == codes.OK && volume.Status.TargetPath != "/path/to/target-2" {
+			t.Fatalf("case %v: target path: expected: /path/to/target-2, got: %v", i+1, volume.Status.TargetPath)
+		}
+	}
+}
+
+func TestPublishVolumeIOLimits(t *testing.T) {
+	k8s.SetKubeInterface(kubernetesfake.NewSimpleClientset(&corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "default", UID: "pod-uid-1"},
+	}))
+
+	testCases := []struct {
+		ioLimits       *types.IOLimits
+		setIOMaxErr    error
+		expectedIOMax  *sys.IOMax
+		expectedDevice string
+	}{
+		{nil, nil, nil, ""},
+		{&types.IOLimits{ReadBytesPerSec: 1 * MiB, WriteIOPS: 100}, nil, &sys.IOMax{ReadBPS: 1 * MiB, WriteIOPS: 100}, "8:0"},
+		{&types.IOLimits{ReadBytesPerSec: 1 * MiB}, errors.New("cgroup v2 is not available"), &sys.IOMax{ReadBPS: 1 * MiB}, ""},
+	}
+
+	for i, testCase := range testCases {
+		volume := types.NewVolume("volume-1", "fsuuid-1", testNodeName, "drive-1", "sda", 20*MiB)
+		volume.Status.StagingTargetPath = "/path/to/staging"
+		volume.Status.IOLimits = testCase.ioLimits
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(volume))
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		var ioMax *sys.IOMax
+		var podUID string
+		ns := createFakeServer()
+		ns.getMounts = func() (map[string]utils.StringSet, map[string]utils.StringSet, error) {
+			return map[string]utils.StringSet{"/path/to/staging": nil}, map[string]utils.StringSet{}, nil
+		}
+		ns.setIOMax = func(uid, _ string, value sys.IOMax) (string, error) {
+			podUID = uid
+			ioMax = &value
+			if testCase.setIOMaxErr != nil {
+				return "", testCase.setIOMaxErr
+			}
+			return "8:0", nil
+		}
+
+		_, err := ns.NodePublishVolume(context.TODO(), &csi.NodePublishVolumeRequest{
+			VolumeId:          "volume-1",
+			StagingTargetPath: "/path/to/staging",
+			TargetPath:        "/path/to/target",
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			},
+			VolumeContext: map[string]string{podNameKey: "pod-1", podNamespaceKey: "default"},
+		})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if !reflect.DeepEqual(ioMax, testCase.expectedIOMax) {
+			t.Fatalf("case %v: io.max: expected: %v, got: %v", i+1, testCase.expectedIOMax, ioMax)
+		}
+		if ioMax != nil && podUID != "pod-uid-1" {
+			t.Fatalf("case %v: pod UID: expected: pod-uid-1, got: %v", i+1, podUID)
+		}
+
+		volume, err = client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unable to get volume; %v", i+1, err)
+		}
+		if volume.Status.IOLimits != nil && volume.Status.IOLimits.Device != testCase.expectedDevice {
+			t.Fatalf("case %v: device: expected: %v, got: %v", i+1, testCase.expectedDevice, volume.Status.IOLimits.Device)
+		}
+	}
+}
+
+func TestPublishVolumeMountFlagsAndGroup(t *testing.T) {
+	testCases := []struct {
+		mountFlags       []string
+		volumeMountGroup string
+		expectedGID      int
+		expectErr        bool
+	}{
+		{nil, "", -1, false},
+		{[]string{"nosuid", "nodev"}, "", -1, false},
+		{[]string{"noexec"}, "1000", 1000, false},
+		{nil, "invalid", -1, true},
+	}
+
+	for i, testCase := range testCases {
+		volume := types.NewVolume("volume-1", "fsuuid-1", testNodeName, "drive-1", "sda", 20*MiB)
+		volume.Status.StagingTargetPath = "/path/to/staging"
+		volume.Status.MountFlags = testCase.mountFlags
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(volume))
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		var mountFlags []string
+		gid := -1
+		ns := createFakeServer()
+		ns.getMounts = func() (map[string]utils.StringSet, map[string]utils.StringSet, error) {
+			return map[string]utils.StringSet{"/path/to/staging": nil}, map[string]utils.StringSet{}, nil
+		}
+		ns.bindMount = func(_, _ string, _ bool, flags []string) error {
+			mountFlags = flags
+			return nil
+		}
+		ns.setMountGroup = func(path string, value int, _ bool) error {
+			if path != "/path/to/staging" {
+				return errors.New("unexpected path " + path)
+			}
+			gid = value
+			return nil
+		}
+
+		_, err := ns.NodePublishVolume(context.TODO(), &csi.NodePublishVolumeRequest{
+			VolumeId:          "volume-1",
+			StagingTargetPath: "/path/to/staging",
+			TargetPath:        "/path/to/target",
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{
+					Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs", VolumeMountGroup: testCase.volumeMountGroup},
+				},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_WRITER},
+			},
+		})
+		if testCase.expectErr {
+			if err == nil {
+				t.Fatalf("case %v: expected error, but succeeded", i+1)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if !reflect.DeepEqual(mountFlags, testCase.mountFlags) {
+			t.Fatalf("case %v: mount flags: expected: %v, got: %v", i+1, testCase.mountFlags, mountFlags)
+		}
+		if gid != testCase.expectedGID {
+			t.Fatalf("case %v: gid: expected: %v, got: %v", i+1, testCase.expectedGID, gid)
+		}
+	}
+}
+
+func TestPublishVolumeReadOnly(t *testing.T) {
+	newRequest := func(targetPath string, readOnly bool) *csi.NodePublishVolumeRequest {
+		return &csi.NodePublishVolumeRequest{
+			VolumeId:          "volume-1",
+			StagingTargetPath: "/path/to/staging",
+			TargetPath:        targetPath,
+			Readonly:          readOnly,
+			VolumeCapability: &csi.VolumeCapability{
+				AccessType: &csi.VolumeCapability_Mount{Mount: &csi.VolumeCapability_MountVolume{FsType: "xfs"}},
+				AccessMode: &csi.VolumeCapability_AccessMode{Mode: csi.VolumeCapability_AccessMode_SINGLE_NODE_MULTI_WRITER},
+			},
+		}
+	}
+
+	testCases := []struct {
+		suspended                    bool
+		expectedReadWriteTargetPaths []string
+		expectedReadOnlyTargetPaths  []string
+	}{
+		{false, []string{"/path/to/target-2"}, []string{"/path/to/target-1"}},
+		{true, nil, []string{"/path/to/target-1", "/path/to/target-2"}},
+	}
+
+	for i, testCase := range testCases {
+		volume := types.NewVolume("volume-1", "fsuuid-1", testNodeName, "drive-1", "sda", 20*MiB)
+		volume.Status.StagingTargetPath = "/path/to/staging"
+		if testCase.suspended {
+			volume.Suspend()
+		}
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(volume))
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		readOnlyMounts := map[string]bool{}
+		ns := createFakeServer()
+		ns.getMounts = func() (map[string]utils.StringSet, map[string]utils.StringSet, error) {
+			return map[string]utils.StringSet{"/path/to/staging": nil, consts.TmpMountDir: nil}, map[string]utils.StringSet{}, nil
+		}
+		ns.bindMount = func(_, target string, readOnly bool, _ []string) error {
+			readOnlyMounts[target] = readOnly
+			return nil
+		}
+
+		if _, err := ns.NodePublishVolume(context.TODO(), newRequest("/path/to/target-1", true)); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if _, err := ns.NodePublishVolume(context.TODO(), newRequest("/path/to/target-2", false)); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if !readOnlyMounts["/path/to/target-1"] || readOnlyMounts["/path/to/target-2"] != testCase.suspended {
+			t.Fatalf("case %v: unexpected read-only mounts %v", i+1, readOnlyMounts)
+		}
+
+		volume, err := client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unable to get volume; %v", i+1, err)
+		}
+		if !reflect.DeepEqual(volume.GetReadWriteTargetPaths(), testCase.expectedReadWriteTargetPaths) {
+			t.Fatalf("case %v: read-write target paths: expected: %v, got: %v", i+1, testCase.expectedReadWriteTargetPaths, volume.GetReadWriteTargetPaths())
+		}
+		if !reflect.DeepEqual(volume.GetReadOnlyTargetPaths(), testCase.expectedReadOnlyTargetPaths) {
+			t.Fatalf("case %v: read-only target paths: expected: %v, got: %v", i+1, testCase.expectedReadOnlyTargetPaths, volume.GetReadOnlyTargetPaths())
+		}
+
+		if _, err := ns.NodeUnpublishVolume(context.TODO(), &csi.NodeUnpublishVolumeRequest{VolumeId: "volume-1", TargetPath: "/path/to/target-1"}); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		volume, err = client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unable to get volume; %v", i+1, err)
+		}
+		if !reflect.DeepEqual(volume.GetTargetPaths(), []string{"/path/to/target-2"}) || volume.Status.TargetPath != "/path/to/target-2" {
+			t.Fatalf("case %v: unexpected target paths %v, %v", i+1, volume.GetTargetPaths(), volume.Status.TargetPath)
+		}
+		if volume.IsReadOnlyTargetPath("/path/to/target-2") != testCase.suspended {
+			t.Fatalf("case %v: target-2 read-only: expected: %v", i+1, testCase.suspended)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/k8s"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// podUIDRegex matches pod UID in target path of a filesystem volume.
+var podUIDRegex = regexp.MustCompile(`/pods/([^/]+)/volumes/`)
+
+// getPodUID returns UID of the pod from the target path.
+func getPodUID(targetPath string) string {
+	if matches := podUIDRegex.FindStringSubmatch(targetPath); matches != nil {
+		return matches[1]
+	}
+	// Target path of raw block volume ends with pod UID.
+	return filepath.Base(targetPath)
+}
+
+// getRunningPodUIDs returns UIDs of pods running on the node.
+func getRunningPodUIDs(ctx context.Context, nodeID directpvtypes.NodeID) (utils.StringSet, error) {
+	podList, err := k8s.KubeClient().CoreV1().Pods("").List(ctx, metav1.ListOptions{
+		FieldSelector: "spec.nodeName=" + string(nodeID),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	podUIDs := make(utils.StringSet)
+	for _, pod := range podList.Items {
+		switch {
+		case pod.Spec.NodeName != string(nodeID),
+			!pod.GetDeletionTimestamp().IsZero(),
+			pod.Status.Phase == corev1.PodSucceeded,
+			pod.Status.Phase == corev1.PodFailed:
+			continue
+		}
+		podUIDs.Set(string(pod.GetUID()))
+	}
+	return podUIDs, nil
+}
+
+// ReconcileMounts compares staging and target paths recorded in volumes of
+// this node with mount points and fixes the differences. Missing mounts of
+// volumes used by running pods are re-mounted and paths of other missing mounts
+// are cleared. It must be called on start before serving requests.
+func (server *Server) ReconcileMounts(ctx context.Context) error {
+	volumes, err := client.NewVolumeLister().
+		NodeSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(server.nodeID))}).
+		Get(ctx)
+	if err != nil {
+		return err
+	}
+
+	runningPodUIDs, err := getRunningPodUIDs(ctx, server.nodeID)
+	if err != nil {
+		return err
+	}
+
+	for i := range volumes {
+		if !volumes[i].GetDeletionTimestamp().IsZero() {
+			continue
+		}
+		if err := server.reconcileVolume(ctx, &volumes[i], runningPodUIDs); err != nil {
+			klog.ErrorS(err, "unable to reconcile volume mounts", "volume", volumes[i].Name)
+		}
+	}
+
+	return nil
+}
+
+func (server *Server) reconcileVolume(ctx context.Context, volume *types.Volume, runningPodUIDs utils.StringSet) error {
+	if !volume.IsStaged() && !volume.IsPublished() {
+		return nil
+	}
+
+	mountMap, rootMap, err := server.getMounts()
+	if err != nil {
+		return err
+	}
+
+	inUse := false
+	for _, targetPath := range volume.GetTargetPaths() {
+		if runningPodUIDs.Exist(getPodUID(targetPath)) {
+			inUse = true
+			break
+		}
+	}
+
+	isSuspended := volume.IsSuspended() || isDriveSuspended(ctx, volume.GetDriveID())
+	clearStagingTargetPath := false
+	stagingMounted := !volume.IsStaged() || volume.IsBlock() || isSuspended
+	if !stagingMounted {
+		stagingTargetPath := volume.Status.StagingTargetPath
+		mountPoints, found := rootMap["/"+volume.Name]
+		switch {
+		case found && mountPoints.Exist(stagingTargetPath):
+			stagingMounted = true
+		case inUse:
+			if err := server.remountStagingTargetPath(volume, mountMap); err != nil {
+				client.Eventf(volume, client.EventTypeWarning, client.EventReasonVolumeReconcileFailed,
+					"unable to re-mount staging target path %v; %v", stagingTargetPath, err)
+			} else {
+				stagingMounted = true
+				client.Eventf(volume, client.EventTypeNormal, client.EventReasonVolumeMountReconciled,
+					"staging target path %v is re-mounted", stagingTargetPath)
+			}
+		default:
+			clearStagingTargetPath = true
+		}
+	}
+
+	var staleTargetPaths []string
+	for _, targetPath := range volume.GetTargetPaths() {
+		if _, found := mountMap[targetPath]; found {
+			continue
+		}
+
+		if !runningPodUIDs.Exist(getPodUID(targetPath)) {
+			staleTargetPaths = append(staleTargetPaths, targetPath)
+			continue
+		}
+
+		if err := server.remountTargetPath(volume, targetPath, stagingMounted, isSuspended); err != nil {
+			client.Eventf(volume, client.EventTypeWarning, client.EventReasonVolumeReconcileFailed,
+				"unable to re-mount target path %v; %v", targetPath, err)
+		} else {
+			client.Eventf(volume, client.EventTypeNormal, client.EventReasonVolumeMountReconciled,
+				"target path %v is re-mounted", targetPath)
+		}
+	}
+
+	if !clearStagingTargetPath && len(staleTargetPaths) == 0 {
+		return nil
+	}
+
+	name := volume.Name
+	stagingTargetPath := volume.Status.StagingTargetPath
+	updateFunc := func() (err error) {
+		if volume == nil {
+			if volume, err = client.VolumeClient().Get(ctx, name, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()}); err != nil {
+				return err
+			}
+		}
+		if clearStagingTargetPath {
+			volume.Status.StagingTargetPath = ""
+		}
+		for _, targetPath := range staleTargetPaths {
+			volume.RemoveTargetPath(targetPath)
+		}
+		if _, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{TypeMeta: types.NewVolumeTypeMeta()}); err != nil {
+			volume = nil
+		}
+		return err
+	}
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		return fmt.Errorf("unable to clear stale paths of volume %v; %w", name, err)
+	}
+
+	if clearStagingTargetPath {
+		client.Eventf(volume, client.EventTypeNormal, client.EventReasonVolumeMountReconciled,
+			"stale staging target path %v is cleared", stagingTargetPath)
+	}
+	for _, targetPath := range staleTargetPaths {
+		client.Eventf(volume, client.EventTypeNormal, client.EventReasonVolumeMountReconciled,
+			"stale target path %v is cleared", targetPath)
+	}
+	return nil
+}
+
+// remountStagingTargetPath bind-mounts volume directory to staging target path.
+func (server *Server) remountStagingTargetPath(volume *types.Volume, mountMap map[string]utils.StringSet) error {
+	if volume.Status.DataPath == "" {
+		return errors.New("data path of volume is not set")
+	}
+	if err := server.statPath(volume.Status.DataPath); err != nil {
+		return fmt.Errorf("data path %v is not accessible; %w", volume.Status.DataPath, err)
+	}
+
+	stagingTargetPath := volume.Status.StagingTargetPath
+	if err := server.mkdir(stagingTargetPath); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("unable to create staging target path; %w", err)
+	}
+	if _, found := mountMap[stagingTargetPath]; found {
+		// Staging target path is mounted with other than the volume.
+		if err := server.unmount(stagingTargetPath); err != nil {
+			return err
+		}
+	}
+	return server.bindMount(volume.Status.DataPath, stagingTargetPath, false, volume.Status.MountFlags)
+}
+
+// remountTargetPath bind-mounts staging target path to the target path.
+func (server *Server) remountTargetPath(volume *types.Volume, targetPath string, stagingMounted, isSuspended bool) error {
+	switch {
+	case isSuspended:
+		if err := server.mkdir(targetPath); err != nil && !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("unable to create target path; %w", err)
+		}
+		return server.bindMount(consts.TmpMountDir, targetPath, true, nil)
+	case volume.IsBlock():
+		return errors.New("loop device of raw block volume must be attached by republishing")
+	case !stagingMounted:
+		return errors.New("staging target path is not mounted")
+	}
+
+	source := volume.Status.StagingTargetPath
+	if source == "" {
+		// Ephemeral inline volume is published from its data path.
+		source = volume.Status.DataPath
+	}
+	if err := server.mkdir(targetPath); err != nil && !errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("unable to create target path; %w", err)
+	}
+	return server.bindMount(source, targetPath, volume.IsReadOnlyTargetPath(targetPath), volume.Status.MountFlags)
+}
@@ -0,0 +1,141 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/k8s"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	kubernetesfake "k8s.io/client-go/kubernetes/fake"
+)
+
+func TestReconcileMounts(t *testing.T) {
+	newPod := func(name, uid string, phase corev1.PodPhase) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default", UID: k8stypes.UID(uid)},
+			Spec:       corev1.PodSpec{NodeName: string(testNodeName)},
+			Status:     corev1.PodStatus{Phase: phase},
+		}
+	}
+	k8s.SetKubeInterface(kubernetesfake.NewSimpleClientset(
+		newPod("pod-1", "uid-1", corev1.PodRunning),
+		newPod("pod-2", "uid-2", corev1.PodSucceeded),
+		newPod("pod-3", "uid-3", corev1.PodRunning),
+		newPod("pod-4", "uid-4", corev1.PodRunning),
+	))
+
+	targetPath := func(podUID, volumeName string) string {
+		return "/var/lib/kubelet/pods/" + podUID + "/volumes/kubernetes.io~csi/" + volumeName + "/mount"
+	}
+	newVolume := func(name, podUID string) *types.Volume {
+		volume := types.NewVolume(name, "fsuuid-1", testNodeName, "drive-1", "sda", 20*MiB)
+		volume.Status.DataPath = "/data/" + name
+		volume.Status.StagingTargetPath = "/staging/" + name
+		volume.AddTargetPath(targetPath(podUID, name), false)
+		volume.Status.Status = "Ready"
+		return volume
+	}
+
+	objects := []runtime.Object{
+		newVolume("volume-1", "uid-1"), // staging and target are re-mounted.
+		newVolume("volume-2", "uid-2"), // stale staging and target paths are cleared.
+		newVolume("volume-3", "uid-3"), // mounts are intact.
+		newVolume("volume-4", "uid-4"), // data path is inaccessible.
+	}
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+
+	mounts := map[string]string{}
+	ns := createFakeServer()
+	ns.getMounts = func() (map[string]utils.StringSet, map[string]utils.StringSet, error) {
+		mountMap := map[string]utils.StringSet{targetPath("uid-3", "volume-3"): nil}
+		rootMap := map[string]utils.StringSet{"/volume-3": {"/staging/volume-3": {}}}
+		return mountMap, rootMap, nil
+	}
+	ns.bindMount = func(source, target string, _ bool, _ []string) error {
+		mounts[target] = source
+		return nil
+	}
+	ns.statPath = func(name string) error {
+		if name == "/data/volume-4" {
+			return errors.New("input/output error")
+		}
+		return nil
+	}
+
+	if err := ns.ReconcileMounts(context.TODO()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expectedMounts := map[string]string{
+		"/staging/volume-1":             "/data/volume-1",
+		targetPath("uid-1", "volume-1"): "/staging/volume-1",
+	}
+	if !reflect.DeepEqual(mounts, expectedMounts) {
+		t.Fatalf("mounts: expected: %v, got: %v", expectedMounts, mounts)
+	}
+
+	testCases := []struct {
+		name                      string
+		expectedStagingTargetPath string
+		expectedTargetPaths       []string
+	}{
+		{"volume-1", "/staging/volume-1", []string{targetPath("uid-1", "volume-1")}},
+		{"volume-2", "", nil},
+		{"volume-3", "/staging/volume-3", []string{targetPath("uid-3", "volume-3")}},
+		{"volume-4", "/staging/volume-4", []string{targetPath("uid-4", "volume-4")}},
+	}
+	for i, testCase := range testCases {
+		volume, err := client.VolumeClient().Get(context.TODO(), testCase.name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unable to get volume; %v", i+1, err)
+		}
+		if volume.Status.StagingTargetPath != testCase.expectedStagingTargetPath {
+			t.Fatalf("case %v: staging target path: expected: %v, got: %v", i+1, testCase.expectedStagingTargetPath, volume.Status.StagingTargetPath)
+		}
+		if !reflect.DeepEqual(volume.GetTargetPaths(), testCase.expectedTargetPaths) {
+			t.Fatalf("case %v: target paths: expected: %v, got: %v", i+1, testCase.expectedTargetPaths, volume.GetTargetPaths())
+		}
+	}
+}
+
+func TestGetPodUID(t *testing.T) {
+	testCases := []struct {
+		targetPath  string
+		expectedUID string
+	}{
+		{"/var/lib/kubelet/pods/uid-1/volumes/kubernetes.io~csi/pvc-1/mount", "uid-1"},
+		{"/var/lib/kubelet/plugins/kubernetes.io/csi/volumeDevices/publish/pvc-1/uid-2", "uid-2"},
+	}
+	for i, testCase := range testCases {
+		if uid := getPodUID(testCase.targetPath); uid != testCase.expectedUID {
+			t.Fatalf("case %v: expected: %v, got: %v", i+1, testCase.expectedUID, uid)
+		}
+	}
+}
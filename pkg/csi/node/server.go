@@ -18,14 +18,18 @@ package node
 
 import (
 	"context"
+	"fmt"
+	"os"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
 	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
 	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/drive"
 	"github.com/minio/directpv/pkg/metrics"
 	"github.com/minio/directpv/pkg/sys"
 	"github.com/minio/directpv/pkg/types"
 	"github.com/minio/directpv/pkg/utils"
+	"github.com/minio/directpv/pkg/volume"
 	"github.com/minio/directpv/pkg/xfs"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
@@ -46,11 +50,24 @@ type Server struct {
 
 	getMounts         func() (mountMap, rootMap map[string]utils.StringSet, err error)
 	getDeviceByFSUUID func(fsuuid string) (string, error)
-	bindMount         func(source, target string, readOnly bool) error
+	bindMount         func(source, target string, readOnly bool, flags []string) error
 	unmount           func(target string) error
-	getQuota          func(ctx context.Context, device, volumeName string) (quota *xfs.Quota, err error)
-	setQuota          func(ctx context.Context, device, path, volumeName string, quota xfs.Quota, update bool) (err error)
+	getQuota          func(ctx context.Context, device string, projectID uint32) (quota *xfs.Quota, err error)
+	setQuota          func(ctx context.Context, device, path string, projectID uint32, quota xfs.Quota, update bool) (err error)
 	mkdir             func(path string) error
+	setOwnership      func(path string, ownership *types.VolumeOwnership) error
+	setMountGroup     func(path string, gid int, onRootMismatch bool) error
+	copyData          func(ctx context.Context, source, target string, reflink bool, progress xfs.ProgressFunc) error
+	createFile        func(name string) error
+	allocateFile      func(name string, size int64) error
+	getLoopDevice     func(backingFile string) (string, error)
+	attachLoopDevice  func(backingFile string) (string, error)
+	detachLoopDevice  func(backingFile string) error
+	resizeLoopDevice  func(backingFile string) error
+	statPath          func(name string) error
+	setIOMax          func(podUID, device string, ioMax sys.IOMax) (majorMinor string, err error)
+
+	cloneJobs *cloneJobs
 }
 
 func newServer(identity string, nodeID directpvtypes.NodeID, rack, zone, region string) Server {
@@ -73,9 +90,26 @@ func newServer(identity string, nodeID directpvtypes.NodeID, rack, zone, region
 		mkdir: func(dir string) error {
 			return sys.Mkdir(dir, 0o755)
 		},
+		setOwnership:     drive.SetVolumeOwnership,
+		setMountGroup:    drive.SetVolumeMountGroup,
+		copyData:         drive.CopyVolumeData,
+		createFile:       createFile,
+		allocateFile:     sys.AllocateFile,
+		getLoopDevice:    sys.GetLoopDevice,
+		attachLoopDevice: sys.AttachLoopDevice,
+		detachLoopDevice: sys.DetachLoopDevice,
+		resizeLoopDevice: sys.ResizeLoopDevice,
+		statPath:         statPath,
+		setIOMax:         sys.SetIOMax,
+		cloneJobs:        newCloneJobs(),
 	}
 }
 
+func statPath(name string) error {
+	_, err := os.Stat(name)
+	return err
+}
+
 // NewServer creates node server.
 func NewServer(ctx context.Context,
 	identity string, nodeID directpvtypes.NodeID, rack, zone, region string,
@@ -125,6 +159,9 @@ func (server *Server) NodeGetCapabilities(_ context.Context, _ *csi.NodeGetCapab
 			nodeCap(csi.NodeServiceCapability_RPC_GET_VOLUME_STATS),
 			nodeCap(csi.NodeServiceCapability_RPC_STAGE_UNSTAGE_VOLUME),
 			nodeCap(csi.NodeServiceCapability_RPC_EXPAND_VOLUME),
+			nodeCap(csi.NodeServiceCapability_RPC_SINGLE_NODE_MULTI_WRITER),
+			nodeCap(csi.NodeServiceCapability_RPC_VOLUME_CONDITION),
+			nodeCap(csi.NodeServiceCapability_RPC_VOLUME_MOUNT_GROUP),
 		},
 	}, nil
 }
@@ -146,6 +183,23 @@ func (server *Server) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVo
 		return nil, status.Error(codes.NotFound, err.Error())
 	}
 
+	condition := server.getVolumeCondition(ctx, volume)
+
+	if volume.IsBlock() {
+		// Block device of raw block volume is preallocated.
+		return &csi.NodeGetVolumeStatsResponse{
+			Usage: []*csi.VolumeUsage{
+				{
+					Available: 0,
+					Total:     volume.Status.TotalCapacity,
+					Used:      volume.Status.TotalCapacity,
+					Unit:      csi.VolumeUsage_BYTES,
+				},
+			},
+			VolumeCondition: condition,
+		}, nil
+	}
+
 	device, err := server.getDeviceByFSUUID(volume.Status.FSUUID)
 	if err != nil {
 		klog.ErrorS(
@@ -161,9 +215,16 @@ func (server *Server) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVo
 				"either device is removed or run command "+
 				"`sudo udevadm control --reload-rules && sudo udevadm trigger`"+
 				" on the host to reload", volume.Status.FSUUID)
+		if condition.Abnormal {
+			// Report abnormal volume condition without usage.
+			return &csi.NodeGetVolumeStatsResponse{VolumeCondition: condition}, nil
+		}
 		return nil, status.Errorf(codes.NotFound, "unable to find device by FSUUID %v; %v", volume.Status.FSUUID, err)
 	}
-	quota, err := server.getQuota(ctx, device, volumeID)
+	if volume.GetProjectID() == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "project ID is not assigned to volume %v", volumeID)
+	}
+	quota, err := server.getQuota(ctx, device, volume.GetProjectID())
 	if err != nil {
 		return nil, status.Errorf(codes.NotFound, "unable to get quota information; %v", err)
 	}
@@ -175,17 +236,52 @@ func (server *Server) NodeGetVolumeStats(ctx context.Context, req *csi.NodeGetVo
 		Unit:      csi.VolumeUsage_BYTES,
 	}
 
+	// Total and available inodes are known only if inode limit is set.
+	inodeUsage := &csi.VolumeUsage{
+		Used: int64(quota.CurrentInodes),
+		Unit: csi.VolumeUsage_INODES,
+	}
+	if volume.Status.InodeLimit > 0 {
+		inodeUsage.Total = volume.Status.InodeLimit
+		inodeUsage.Available = volume.Status.InodeLimit - int64(quota.CurrentInodes)
+	}
+
 	return &csi.NodeGetVolumeStatsResponse{
 		Usage: []*csi.VolumeUsage{
 			volUsage,
+			inodeUsage,
 		},
-		VolumeCondition: &csi.VolumeCondition{
-			Abnormal: false,
-			Message:  "",
-		},
+		VolumeCondition: condition,
 	}, nil
 }
 
+// getVolumeCondition returns volume condition by the volume conditions, the
+// status of its drive and the live check of its data path.
+func (server *Server) getVolumeCondition(ctx context.Context, vol *types.Volume) *csi.VolumeCondition {
+	abnormal, message, err := volume.GetCondition(ctx, vol)
+	if err != nil {
+		klog.ErrorS(err, "unable to get volume condition", "volume", vol.Name)
+	}
+	if abnormal {
+		return &csi.VolumeCondition{Abnormal: true, Message: message}
+	}
+
+	if vol.IsStaged() && vol.Status.DataPath != "" {
+		dataPath := vol.Status.DataPath
+		if vol.IsBlock() {
+			dataPath = types.GetVolumeBlockFile(vol.Status.FSUUID, vol.Name)
+		}
+		if err := server.statPath(dataPath); err != nil {
+			return &csi.VolumeCondition{
+				Abnormal: true,
+				Message:  fmt.Sprintf("unable to access volume data path %v; %v", dataPath, err),
+			}
+		}
+	}
+
+	return &csi.VolumeCondition{Abnormal: false, Message: ""}
+}
+
 // NodeExpandVolume handles expand volume request.
 // reference: https://github.com/container-storage-interface/spec/blob/master/spec.md#nodeexpandvolume
 func (server *Server) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandVolumeRequest) (*csi.NodeExpandVolumeResponse, error) {
@@ -230,6 +326,24 @@ func (server *Server) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 		return &csi.NodeExpandVolumeResponse{CapacityBytes: requiredBytes}, nil
 	}
 
+	if volume.IsBlock() {
+		if err := server.expandBlockVolume(volume, requiredBytes); err != nil {
+			klog.ErrorS(err, "unable to expand block volume", "volume", volume.Name)
+			return nil, status.Errorf(codes.Internal, "unable to expand block volume; %v", err)
+		}
+
+		volume.Status.TotalCapacity = requiredBytes
+		volume.Status.UsedCapacity = requiredBytes
+		volume.Status.AvailableCapacity = 0
+		if _, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
+			TypeMeta: types.NewVolumeTypeMeta(),
+		}); err != nil {
+			return nil, status.Errorf(codes.Internal, "unable to update volume %v; %v", volumeID, err)
+		}
+
+		return &csi.NodeExpandVolumeResponse{CapacityBytes: requiredBytes}, nil
+	}
+
 	device, err := server.getDeviceByFSUUID(volume.Status.FSUUID)
 	if err != nil {
 		klog.ErrorS(
@@ -248,12 +362,18 @@ func (server *Server) NodeExpandVolume(ctx context.Context, req *csi.NodeExpandV
 		return nil, status.Errorf(codes.Internal, "unable to find device by FSUUID %v; %v", volume.Status.FSUUID, err)
 	}
 
+	if volume.GetProjectID() == 0 {
+		return nil, status.Errorf(codes.FailedPrecondition, "project ID is not assigned to volume %v", volumeID)
+	}
+
 	quota := xfs.Quota{
-		HardLimit: uint64(requiredBytes),
-		SoftLimit: uint64(requiredBytes),
+		HardLimit:      uint64(requiredBytes),
+		SoftLimit:      uint64(requiredBytes),
+		InodeHardLimit: uint64(volume.Status.InodeLimit),
+		InodeSoftLimit: uint64(volume.Status.InodeLimit),
 	}
 
-	if err := server.setQuota(ctx, device, volume.Status.DataPath, volume.Name, quota, true); err != nil {
+	if err := server.setQuota(ctx, device, volume.Status.DataPath, volume.GetProjectID(), quota, true); err != nil {
 		klog.ErrorS(err, "unable to set quota on volume data path", "DataPath", volume.Status.DataPath)
 		return nil, status.Errorf(codes.Internal, "unable to set quota on volume data path; %v", err)
 	}
@@ -18,13 +18,16 @@ package node
 
 import (
 	"context"
+	"os"
 	"testing"
 
 	"github.com/container-storage-interface/spec/lib/go/csi"
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
 	"github.com/minio/directpv/pkg/client"
 	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
 	"github.com/minio/directpv/pkg/types"
 	"github.com/minio/directpv/pkg/xfs"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 func init() {
@@ -45,7 +48,7 @@ func TestNodeExpandVolume(t *testing.T) {
 	nodeServer.getDeviceByFSUUID = func(_ string) (string, error) {
 		return "sda", nil
 	}
-	nodeServer.setQuota = func(_ context.Context, _, _, _ string, _ xfs.Quota, _ bool) error {
+	nodeServer.setQuota = func(_ context.Context, _, _ string, _ uint32, _ xfs.Quota, _ bool) error {
 		return nil
 	}
 
@@ -57,3 +60,118 @@ func TestNodeExpandVolume(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestNodeGetVolumeStatsCondition(t *testing.T) {
+	newDrive := func(driveStatus directpvtypes.DriveStatus) *types.Drive {
+		drive := types.NewDrive("drive-1", types.DriveStatus{Status: driveStatus}, "node-1", "sda", directpvtypes.AccessTierDefault)
+		if driveStatus == directpvtypes.DriveStatusError {
+			drive.SetIOErrorCondition()
+		}
+		return drive
+	}
+	newVolume := func(driveLost bool) *types.Volume {
+		volume := types.NewVolume("volume-1", "fsuuid1", "node-1", "drive-1", "sda", 100*MiB)
+		volume.Status.DataPath = "/var/lib/directpv/mnt/fsuuid1/volume-1"
+		volume.Status.StagingTargetPath = "/path/to/staging"
+		volume.SetProjectID(1)
+		if driveLost {
+			volume.SetDriveLost()
+		}
+		return volume
+	}
+
+	testCases := []struct {
+		drive            *types.Drive
+		volume           *types.Volume
+		statErr          error
+		deviceErr        error
+		expectedAbnormal bool
+		expectedUsage    bool
+	}{
+		{newDrive(directpvtypes.DriveStatusReady), newVolume(false), nil, nil, false, true},
+		{newDrive(directpvtypes.DriveStatusError), newVolume(false), nil, nil, true, true},
+		{newDrive(directpvtypes.DriveStatusLost), newVolume(false), nil, nil, true, true},
+		{newDrive(directpvtypes.DriveStatusReady), newVolume(false), os.ErrNotExist, nil, true, true},
+		{newDrive(directpvtypes.DriveStatusReady), newVolume(true), nil, os.ErrNotExist, true, false},
+		{nil, newVolume(false), nil, os.ErrNotExist, true, false},
+	}
+
+	for i, testCase := range testCases {
+		objects := []runtime.Object{testCase.volume}
+		if testCase.drive != nil {
+			objects = append(objects, testCase.drive)
+		}
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
+		client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		nodeServer := createFakeServer()
+		nodeServer.statPath = func(_ string) error { return testCase.statErr }
+		nodeServer.getDeviceByFSUUID = func(_ string) (string, error) { return "sda", testCase.deviceErr }
+
+		response, err := nodeServer.NodeGetVolumeStats(context.TODO(), &csi.NodeGetVolumeStatsRequest{
+			VolumeId:   "volume-1",
+			VolumePath: "/path/to/target",
+		})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if response.GetVolumeCondition().GetAbnormal() != testCase.expectedAbnormal {
+			t.Fatalf("case %v: abnormal: expected: %v, got: %v", i+1, testCase.expectedAbnormal, response.GetVolumeCondition())
+		}
+		if testCase.expectedAbnormal && response.GetVolumeCondition().GetMessage() == "" {
+			t.Fatalf("case %v: empty message for abnormal condition", i+1)
+		}
+		if (len(response.GetUsage()) != 0) != testCase.expectedUsage {
+			t.Fatalf("case %v: usage: expected: %v, got: %v", i+1, testCase.expectedUsage, response.GetUsage())
+		}
+	}
+}
+
+func TestNodeGetVolumeStatsInodes(t *testing.T) {
+	testCases := []struct {
+		inodeLimit        int64
+		expectedTotal     int64
+		expectedAvailable int64
+	}{
+		{0, 0, 0},
+		{1000, 1000, 900},
+	}
+
+	for i, testCase := range testCases {
+		volume := types.NewVolume("volume-1", "fsuuid1", "node-1", "drive-1", "sda", 100*MiB)
+		volume.Status.StagingTargetPath = "/path/to/staging"
+		volume.Status.InodeLimit = testCase.inodeLimit
+		volume.SetProjectID(1)
+		drive := types.NewDrive("drive-1", types.DriveStatus{Status: directpvtypes.DriveStatusReady}, "node-1", "sda", directpvtypes.AccessTierDefault)
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(volume, drive))
+		client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		nodeServer := createFakeServer()
+		nodeServer.getQuota = func(_ context.Context, _ string, _ uint32) (*xfs.Quota, error) {
+			return &xfs.Quota{CurrentInodes: 100}, nil
+		}
+
+		response, err := nodeServer.NodeGetVolumeStats(context.TODO(), &csi.NodeGetVolumeStatsRequest{
+			VolumeId:   "volume-1",
+			VolumePath: "/path/to/target",
+		})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+
+		var inodeUsage *csi.VolumeUsage
+		for _, usage := range response.GetUsage() {
+			if usage.GetUnit() == csi.VolumeUsage_INODES {
+				inodeUsage = usage
+			}
+		}
+		if inodeUsage == nil {
+			t.Fatalf("case %v: inode usage not found", i+1)
+		}
+		if inodeUsage.GetUsed() != 100 || inodeUsage.GetTotal() != testCase.expectedTotal || inodeUsage.GetAvailable() != testCase.expectedAvailable {
+			t.Fatalf("case %v: unexpected inode usage %v", i+1, inodeUsage)
+		}
+	}
+}
@@ -65,12 +65,14 @@ func (server *Server) NodeStageVolume(ctx context.Context, req *csi.NodeStageVol
 		stagingTargetPath,
 		server.getDeviceByFSUUID,
 		server.mkdir,
+		server.setOwnership,
 		server.setQuota,
 		server.bindMount,
 		func() (rootMap map[string]utils.StringSet, err error) {
 			_, rootMap, err = server.getMounts()
 			return
 		},
+		server.fillVolume,
 	)
 	if err != nil {
 		return nil, status.Error(code, err.Error())
@@ -109,6 +111,14 @@ func (server *Server) NodeUnstageVolume(ctx context.Context, req *csi.NodeUnstag
 		return nil, status.Error(codes.Internal, err.Error())
 	}
 
+	if volume.IsBlock() {
+		blockFile := types.GetVolumeBlockFile(volume.Status.FSUUID, volume.Name)
+		if err := server.detachLoopDevice(blockFile); err != nil {
+			klog.ErrorS(err, "unable to detach loop device", "volume", volume.Name, "blockFile", blockFile)
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+	}
+
 	if volume.Status.StagingTargetPath == stagingTargetPath {
 		volume.Status.StagingTargetPath = ""
 		if _, err := client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
@@ -95,7 +95,8 @@ func syncDrive(drive *types.Drive, device device) (updated bool) {
 
 func verifyDrive(drive *types.Drive) (updated bool) {
 	switch drive.Status.Status {
-	case directpvtypes.DriveStatusReady, directpvtypes.DriveStatusLost, directpvtypes.DriveStatusError, directpvtypes.DriveStatusMoving:
+	case directpvtypes.DriveStatusReady, directpvtypes.DriveStatusLost, directpvtypes.DriveStatusError, directpvtypes.DriveStatusMoving,
+		directpvtypes.DriveStatusDraining, directpvtypes.DriveStatusDrained:
 	default:
 		return false
 	}
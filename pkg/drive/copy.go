@@ -0,0 +1,47 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	"github.com/minio/directpv/pkg/xfs"
+)
+
+func removeDirContents(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := os.RemoveAll(filepath.Join(dir, entry.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CopyVolumeData copies source directory tree into the volume directory after
+// removing leftover of previous failed attempt if any.
+func CopyVolumeData(ctx context.Context, source, target string, reflink bool, progress xfs.ProgressFunc) error {
+	if err := removeDirContents(target); err != nil {
+		return err
+	}
+	return xfs.Copy(ctx, source, target, reflink, progress)
+}
@@ -0,0 +1,213 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+// maxDrainRetries is the maximum number of drive selections done when
+// concurrent moves consume free capacity of the selected drive.
+const maxDrainRetries = 3
+
+var errNoDrainDrive = errors.New("no drive with enough free capacity and matching access-tier found")
+
+// selectDrainDrive selects a drive on the node of the draining drive having
+// largest free capacity to move the volume. Drives already moving other
+// volumes are considered as they are ready otherwise.
+func selectDrainDrive(ctx context.Context, drive *types.Drive, volume *types.Volume) (*types.Drive, error) {
+	drives, err := client.NewDriveLister().
+		NodeSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(drive.GetNodeID()))}).
+		StatusSelector([]directpvtypes.DriveStatus{directpvtypes.DriveStatusReady, directpvtypes.DriveStatusMoving}).
+		Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected *types.Drive
+	for i := range drives {
+		switch {
+		case drives[i].GetDriveID() == drive.GetDriveID(),
+			!drives[i].GetDeletionTimestamp().IsZero(),
+			drives[i].IsUnschedulable(),
+			drives[i].GetAccessTier() != drive.GetAccessTier(),
+			drives[i].Status.FreeCapacity < volume.Status.TotalCapacity,
+			drives[i].HasVolumeClaimID(volume.GetClaimID()):
+			continue
+		}
+		if selected == nil || drives[i].Status.FreeCapacity > selected.Status.FreeCapacity {
+			selected = &drives[i]
+		}
+	}
+
+	if selected == nil {
+		return nil, errNoDrainDrive
+	}
+	return selected, nil
+}
+
+// reserveDrainDrive reserves the volume size in the destination drive and sets
+// the drive to moving state. The volume is moved by the move handler of the
+// destination drive.
+func reserveDrainDrive(ctx context.Context, driveID directpvtypes.DriveID, volume *types.Volume) error {
+	updateFunc := func() error {
+		drive, err := client.DriveClient().Get(ctx, string(driveID), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			return err
+		}
+
+		if drive.VolumeExist(volume.Name) {
+			return nil
+		}
+
+		switch {
+		case !drive.GetDeletionTimestamp().IsZero(),
+			drive.Status.Status != directpvtypes.DriveStatusReady && drive.Status.Status != directpvtypes.DriveStatusMoving,
+			drive.IsUnschedulable(),
+			drive.Status.FreeCapacity < volume.Status.TotalCapacity,
+			drive.HasVolumeClaimID(volume.GetClaimID()):
+			return errNoDrainDrive
+		}
+
+		drive.AddVolumeFinalizer(volume.Name)
+		drive.SetVolumeClaimID(volume.GetClaimID())
+		drive.Status.FreeCapacity -= volume.Status.TotalCapacity
+		drive.Status.AllocatedCapacity += volume.Status.TotalCapacity
+		drive.Status.Status = directpvtypes.DriveStatusMoving
+
+		_, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, updateFunc)
+}
+
+// getMoveReservations returns drive IDs of other drives on the node of the
+// draining drive by volume names reserved on them.
+func getMoveReservations(ctx context.Context, drive *types.Drive) (map[string]directpvtypes.DriveID, error) {
+	drives, err := client.NewDriveLister().
+		NodeSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(drive.GetNodeID()))}).
+		Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reservations := map[string]directpvtypes.DriveID{}
+	for i := range drives {
+		if drives[i].GetDriveID() == drive.GetDriveID() {
+			continue
+		}
+		for _, volumeName := range drives[i].GetVolumes() {
+			reservations[volumeName] = drives[i].GetDriveID()
+		}
+	}
+	return reservations, nil
+}
+
+// drain moves unpublished volumes of the drive to other drives on the same
+// node and marks the drive as drained once it has no volumes. Volumes those
+// cannot be moved are reported by events and draining is retried.
+func (handler *driveEventHandler) drain(ctx context.Context, drive *types.Drive) error {
+	reservations, err := getMoveReservations(ctx, drive)
+	if err != nil {
+		return err
+	}
+
+	var unmovedVolumes []string
+	for _, volumeName := range drive.GetVolumes() {
+		volume, err := client.VolumeClient().Get(ctx, volumeName, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		if err != nil {
+			klog.ErrorS(err, "unable to retrieve volume", "volume", volumeName)
+			return err
+		}
+
+		if volume.GetDriveID() != drive.GetDriveID() || !isMoveDone(volume) {
+			// Volume is being moved to another drive.
+			continue
+		}
+
+		if destDriveID, found := reservations[volume.Name]; found {
+			// Volume is reserved in other drive by previous drain and
+			// yet to be moved by move handler of that drive.
+			klog.V(5).InfoS("Volume is already reserved for moving",
+				"volume", volume.Name,
+				"source", drive.GetDriveID(),
+				"destination", destDriveID)
+			continue
+		}
+
+		var reason string
+		switch {
+		case volume.IsPublished():
+			reason = "volume is published"
+		case volume.IsBlock() && volume.IsStaged():
+			reason = "block volume is staged"
+		default:
+			err = errNoDrainDrive
+			for i := 0; i < maxDrainRetries && errors.Is(err, errNoDrainDrive); i++ {
+				var destDrive *types.Drive
+				if destDrive, err = selectDrainDrive(ctx, drive, volume); err == nil {
+					if err = reserveDrainDrive(ctx, destDrive.GetDriveID(), volume); err == nil {
+						klog.V(3).InfoS("Volume is being moved",
+							"volume", volume.Name,
+							"source", drive.GetDriveID(),
+							"destination", destDrive.GetDriveID())
+					}
+				}
+			}
+			switch {
+			case err == nil:
+				continue
+			case !errors.Is(err, errNoDrainDrive):
+				return err
+			}
+			reason = err.Error()
+		}
+
+		unmovedVolumes = append(unmovedVolumes, volume.Name)
+		client.Eventf(
+			drive, client.EventTypeWarning, client.EventReasonVolumeNotDrained,
+			"unable to move volume %v; %v", volume.Name, reason,
+		)
+	}
+
+	if len(unmovedVolumes) != 0 {
+		return fmt.Errorf("unable to move volumes %v from draining drive %v", unmovedVolumes, drive.GetDriveID())
+	}
+
+	if drive.GetVolumeCount() > 0 {
+		// Drive is re-processed when moved volumes release it.
+		return nil
+	}
+
+	drive.Status.Status = directpvtypes.DriveStatusDrained
+	if _, err := client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()}); err != nil {
+		return err
+	}
+
+	client.Eventf(drive, client.EventTypeNormal, client.EventReasonDriveDrained, "Drive is drained and ready for removal")
+	return nil
+}
@@ -0,0 +1,120 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"context"
+	"testing"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestDrain(t *testing.T) {
+	newDrive := func(driveID directpvtypes.DriveID, status directpvtypes.DriveStatus, accessTier directpvtypes.AccessTier, volumes ...string) *types.Drive {
+		drive := types.NewDrive(
+			driveID,
+			types.DriveStatus{
+				Status:        status,
+				TotalCapacity: 10 * testVolumeSize,
+				FreeCapacity:  10 * testVolumeSize,
+			},
+			"node-1",
+			directpvtypes.DriveName(driveID),
+			accessTier,
+		)
+		for _, volume := range volumes {
+			drive.AddVolumeFinalizer(volume)
+			drive.Status.FreeCapacity -= testVolumeSize
+			drive.Status.AllocatedCapacity += testVolumeSize
+		}
+		return drive
+	}
+
+	volume1 := types.NewVolume("volume-1", "fsuuid-1", "node-1", "drive-1", "drive-1", testVolumeSize)
+	volume2 := types.NewVolume("volume-2", "fsuuid-1", "node-1", "drive-1", "drive-1", testVolumeSize)
+	volume2.AddTargetPath("/var/lib/kubelet/pods/pod-1/volumes/kubernetes.io~csi/volume-2/mount", false)
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(
+		newDrive("drive-1", directpvtypes.DriveStatusDraining, directpvtypes.AccessTierDefault, "volume-1", "volume-2"),
+		newDrive("drive-2", directpvtypes.DriveStatusReady, directpvtypes.AccessTierHot),
+		newDrive("drive-3", directpvtypes.DriveStatusReady, directpvtypes.AccessTierDefault),
+		volume1,
+		volume2,
+	))
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+	getDrive := func(driveID string) *types.Drive {
+		drive, err := client.DriveClient().Get(context.TODO(), driveID, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unable to get drive; %v", err)
+		}
+		return drive
+	}
+
+	handler := &driveEventHandler{nodeID: "node-1"}
+
+	// Published volume is not moved.
+	if err := handler.drain(context.TODO(), getDrive("drive-1")); err == nil {
+		t.Fatalf("expected error; but succeeded")
+	}
+	if drive := getDrive("drive-2"); drive.GetVolumeCount() != 0 {
+		t.Fatalf("drive with mismatching access-tier must not be selected; volumes: %v", drive.GetVolumes())
+	}
+	drive := getDrive("drive-3")
+	if !drive.VolumeExist("volume-1") || drive.VolumeExist("volume-2") {
+		t.Fatalf("destination drive: unexpected volumes: %v", drive.GetVolumes())
+	}
+	if drive.Status.Status != directpvtypes.DriveStatusMoving || drive.Status.FreeCapacity != 9*testVolumeSize {
+		t.Fatalf("destination drive: unexpected status: %v, free capacity: %v", drive.Status.Status, drive.Status.FreeCapacity)
+	}
+	if drive := getDrive("drive-1"); drive.Status.Status != directpvtypes.DriveStatusDraining {
+		t.Fatalf("status: expected: %v, got: %v", directpvtypes.DriveStatusDraining, drive.Status.Status)
+	}
+
+	// Requeued drain does not reserve pending move again on other drive.
+	if _, err := client.DriveClient().Create(
+		context.TODO(),
+		newDrive("drive-4", directpvtypes.DriveStatusReady, directpvtypes.AccessTierDefault),
+		metav1.CreateOptions{},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err := handler.drain(context.TODO(), getDrive("drive-1")); err == nil {
+		t.Fatalf("expected error; but succeeded")
+	}
+	if drive := getDrive("drive-4"); drive.GetVolumeCount() != 0 || drive.Status.FreeCapacity != 10*testVolumeSize {
+		t.Fatalf("volume reserved again; volumes: %v, free capacity: %v", drive.GetVolumes(), drive.Status.FreeCapacity)
+	}
+	if drive := getDrive("drive-3"); drive.Status.FreeCapacity != 9*testVolumeSize {
+		t.Fatalf("destination drive: unexpected free capacity: %v", drive.Status.FreeCapacity)
+	}
+
+	// Empty drive is marked as drained.
+	drive = getDrive("drive-1")
+	drive.RemoveVolumeFinalizer("volume-1")
+	drive.RemoveVolumeFinalizer("volume-2")
+	if err := handler.drain(context.TODO(), drive); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if drive := getDrive("drive-1"); drive.Status.Status != directpvtypes.DriveStatusDrained {
+		t.Fatalf("status: expected: %v, got: %v", directpvtypes.DriveStatusDrained, drive.Status.Status)
+	}
+}
@@ -65,8 +65,9 @@ func SetIOError(ctx context.Context, driveID directpvtypes.DriveID) error {
 
 func stageVolumeMount(
 	volumeName, volumeDir, stagingTargetPath string,
+	mountFlags []string,
 	getMounts func() (map[string]utils.StringSet, error),
-	bindMount func(volumeDir, stagingTargetPath string, readOnly bool) error,
+	bindMount func(volumeDir, stagingTargetPath string, readOnly bool, flags []string) error,
 ) error {
 	rootMountPointMap, err := getMounts()
 	if err != nil {
@@ -78,7 +79,7 @@ func stageVolumeMount(
 		return nil
 	}
 
-	return bindMount(volumeDir, stagingTargetPath, false)
+	return bindMount(volumeDir, stagingTargetPath, false, mountFlags)
 }
 
 // StageVolume creates and mounts staging target path of the volume to the drive.
@@ -88,9 +89,11 @@ func StageVolume(
 	stagingTargetPath string,
 	getDeviceByFSUUID func(fsuuid string) (string, error),
 	mkdir func(volumeDir string) error,
-	setQuota func(ctx context.Context, device, stagingTargetPath, volumeName string, quota xfs.Quota, update bool) error,
-	bindMount func(volumeDir, stagingTargetPath string, readOnly bool) error,
+	setOwnership func(volumeDir string, ownership *types.VolumeOwnership) error,
+	setQuota func(ctx context.Context, device, stagingTargetPath string, projectID uint32, quota xfs.Quota, update bool) error,
+	bindMount func(volumeDir, stagingTargetPath string, readOnly bool, flags []string) error,
 	getMounts func() (map[string]utils.StringSet, error),
+	fillVolume func(ctx context.Context, volume *types.Volume, volumeDir string) (codes.Code, error),
 ) (codes.Code, error) {
 	device, err := getDeviceByFSUUID(volume.Status.FSUUID)
 	if err != nil {
@@ -112,7 +115,14 @@ func StageVolume(
 
 	volumeDir := types.GetVolumeDir(volume.Status.FSUUID, volume.Name)
 
-	if err := mkdir(volumeDir); err != nil && !errors.Is(err, os.ErrExist) {
+	switch err := mkdir(volumeDir); {
+	case err == nil:
+		// Ownership is set only on newly created volume directory to retain changes done by the workload.
+		if err := setOwnership(volumeDir, volume.Status.Ownership); err != nil {
+			klog.ErrorS(err, "unable to set ownership of volume directory", "VolumeDir", volumeDir)
+			return codes.Internal, fmt.Errorf("unable to set ownership of volume directory; %w", err)
+		}
+	case !errors.Is(err, os.ErrExist):
 		if errors.Unwrap(err) == syscall.EIO {
 			if err := SetIOError(ctx, volume.GetDriveID()); err != nil {
 				return codes.Internal, fmt.Errorf("unable to set drive error; %w", err)
@@ -122,18 +132,35 @@ func StageVolume(
 		return codes.Internal, err
 	}
 
-	quota := xfs.Quota{
-		HardLimit: uint64(volume.Status.TotalCapacity),
-		SoftLimit: uint64(volume.Status.TotalCapacity),
+	// Raw block volume is bounded by its preallocated backing file.
+	if !volume.IsBlock() {
+		if err := assignProjectID(ctx, volume); err != nil {
+			klog.ErrorS(err, "unable to assign project ID", "volume", volume.Name)
+			return codes.Internal, fmt.Errorf("unable to assign project ID; %w", err)
+		}
+
+		quota := xfs.Quota{
+			HardLimit:      uint64(volume.Status.TotalCapacity),
+			SoftLimit:      uint64(volume.Status.TotalCapacity),
+			InodeHardLimit: uint64(volume.Status.InodeLimit),
+			InodeSoftLimit: uint64(volume.Status.InodeLimit),
+		}
+
+		if err := setQuota(ctx, device, volumeDir, volume.GetProjectID(), quota, false); err != nil {
+			klog.ErrorS(err, "unable to set quota on volume data path", "DataPath", volumeDir)
+			return codes.Internal, fmt.Errorf("unable to set quota on volume data path; %w", err)
+		}
 	}
 
-	if err := setQuota(ctx, device, volumeDir, volume.Name, quota, false); err != nil {
-		klog.ErrorS(err, "unable to set quota on volume data path", "DataPath", volumeDir)
-		return codes.Internal, fmt.Errorf("unable to set quota on volume data path; %w", err)
+	if fillVolume != nil {
+		if code, err := fillVolume(ctx, volume, volumeDir); err != nil {
+			return code, err
+		}
 	}
 
-	if stagingTargetPath != "" {
-		if err := stageVolumeMount(volume.Name, volumeDir, stagingTargetPath, getMounts, bindMount); err != nil {
+	// Raw block volume is published from its loop device; hence staging target path is not mounted.
+	if stagingTargetPath != "" && !volume.IsBlock() {
+		if err := stageVolumeMount(volume.Name, volumeDir, stagingTargetPath, volume.Status.MountFlags, getMounts, bindMount); err != nil {
 			return codes.Internal, fmt.Errorf("unable to bind mount volume directory to staging target path; %w", err)
 		}
 	}
@@ -155,11 +182,15 @@ type driveEventHandler struct {
 	getMounts         func() (mountPointMap, deviceMap, rootMountPointMap map[string]utils.StringSet, err error)
 	unmount           func(target string) error
 	mkdir             func(path string) error
-	bindMount         func(source, target string, readOnly bool) error
+	setOwnership      func(path string, ownership *types.VolumeOwnership) error
+	bindMount         func(source, target string, readOnly bool, flags []string) error
 	getDeviceByFSUUID func(fsuuid string) (string, error)
-	setQuota          func(ctx context.Context, device, path, volumeName string, quota xfs.Quota, update bool) (err error)
+	setQuota          func(ctx context.Context, device, path string, projectID uint32, quota xfs.Quota, update bool) (err error)
 	rmdir             func(fsuuid string) error
 	exists            func(name string) error
+	copyData          func(ctx context.Context, source, target string, reflink bool, progress xfs.ProgressFunc) error
+	verifyData        func(ctx context.Context, source, target string) error
+	removeAll         func(path string) error
 }
 
 func newDriveEventHandler(nodeID directpvtypes.NodeID) *driveEventHandler {
@@ -175,6 +206,7 @@ func newDriveEventHandler(nodeID directpvtypes.NodeID) *driveEventHandler {
 		mkdir: func(dir string) error {
 			return sys.Mkdir(dir, 0o755)
 		},
+		setOwnership:      SetVolumeOwnership,
 		bindMount:         xfs.BindMount,
 		getDeviceByFSUUID: sys.GetDeviceByFSUUID,
 		setQuota:          xfs.SetQuota,
@@ -193,6 +225,9 @@ func newDriveEventHandler(nodeID directpvtypes.NodeID) *driveEventHandler {
 			_, err = os.Lstat(name)
 			return err
 		},
+		copyData:   CopyVolumeData,
+		verifyData: xfs.Verify,
+		removeAll:  os.RemoveAll,
 	}
 }
 
@@ -269,66 +304,58 @@ func (handler *driveEventHandler) move(ctx context.Context, drive *types.Drive)
 	for _, volumeName := range drive.GetVolumes() {
 		volume, err := client.VolumeClient().Get(ctx, volumeName, metav1.GetOptions{})
 		if err != nil {
-			klog.ErrorS(err, "unable to retrieve volume", "volume", volume.Name)
+			klog.ErrorS(err, "unable to retrieve volume", "volume", volumeName)
 			return err
 		}
 
-		if volume.Status.FSUUID == drive.Status.FSUUID {
+		if volume.Status.FSUUID == drive.Status.FSUUID && isMoveDone(volume) {
 			continue
 		}
 
-		if volume.IsPublished() {
-			return fmt.Errorf("cannot move published volume %v to drive ID %v", volume.Name, drive.GetDriveID())
-		}
+		if volume.Status.FSUUID != drive.Status.FSUUID {
+			if volume.IsPublished() {
+				return fmt.Errorf("cannot move published volume %v to drive ID %v", volume.Name, drive.GetDriveID())
+			}
 
-		if volume.GetNodeID() != drive.GetNodeID() {
-			return fmt.Errorf(
-				"volume %v must be on same node of destination drive; volume node %v; desination node %v",
-				volume.Name,
-				volume.GetNodeID(),
-				drive.GetNodeID(),
-			)
-		}
+			if volume.IsBlock() && volume.IsStaged() {
+				return fmt.Errorf("cannot move staged block volume %v to drive ID %v", volume.Name, drive.GetDriveID())
+			}
 
-		srcDriveID := volume.GetDriveID()
-		volume.Status.FSUUID = drive.Status.FSUUID
-		volume.SetDriveID(drive.GetDriveID())
-		volume.SetDriveName(drive.GetDriveName())
-		volume.Status.DataPath = ""
-		if volume.IsStaged() {
-			_, err = StageVolume(
-				ctx,
-				volume,
-				volume.Status.StagingTargetPath,
-				handler.getDeviceByFSUUID,
-				handler.mkdir,
-				handler.setQuota,
-				handler.bindMount,
-				func() (rootMap map[string]utils.StringSet, err error) {
-					_, _, rootMap, err = handler.getMounts()
-					return
-				},
-			)
-			if err != nil && !errors.Is(err, os.ErrNotExist) {
-				klog.ErrorS(err, "unable to stage volume after volume move",
-					"volume", volume.Name,
-					"dataPath", volume.Status.DataPath,
-					"stagingTargetPath", volume.Status.StagingTargetPath,
+			if volume.GetNodeID() != drive.GetNodeID() {
+				return fmt.Errorf(
+					"volume %v must be on same node of destination drive; volume node %v; desination node %v",
+					volume.Name,
+					volume.GetNodeID(),
+					drive.GetNodeID(),
 				)
 			}
-		} else {
-			volume.Status.Status = directpvtypes.VolumeStatusPending
+
+			// Source is recorded to resume the move on failure.
+			volume.Status.Move = &types.MoveStatus{
+				SourceDriveID: volume.GetDriveID(),
+				SourceFSUUID:  volume.Status.FSUUID,
+				State:         directpvtypes.MoveStateInProgress,
+			}
+			volume.Status.FSUUID = drive.Status.FSUUID
+			volume.SetDriveID(drive.GetDriveID())
+			volume.SetDriveName(drive.GetDriveName())
+			volume.Status.DataPath = ""
+			volume.SetProjectID(0) // Project ID is assigned per drive.
+			if volume, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
+				TypeMeta: types.NewVolumeTypeMeta(),
+			}); err != nil {
+				return err
+			}
 		}
 
-		if _, err := client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
-			TypeMeta: types.NewVolumeTypeMeta(),
-		}); err != nil {
+		if err := handler.moveVolume(ctx, volume); err != nil {
+			klog.ErrorS(err, "unable to move volume", "volume", volume.Name, "drive", drive.GetDriveID())
 			return err
 		}
 
 		client.Eventf(
 			volume, client.EventTypeNormal, client.EventReasonVolumeMoved,
-			"Volume moved from drive %v to drive %v", srcDriveID, volume.GetDriveID(),
+			"Volume moved from drive %v to drive %v", volume.Status.Move.SourceDriveID, volume.GetDriveID(),
 		)
 	}
 
@@ -345,6 +372,8 @@ func (handler *driveEventHandler) handleUpdate(ctx context.Context, drive *types
 		return handler.remove(ctx, drive)
 	case directpvtypes.DriveStatusMoving:
 		return handler.move(ctx, drive)
+	case directpvtypes.DriveStatusDraining:
+		return handler.drain(ctx, drive)
 	}
 
 	return nil
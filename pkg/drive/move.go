@@ -0,0 +1,274 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	"google.golang.org/grpc/codes"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"k8s.io/klog/v2"
+)
+
+const moveProgressInterval = 5 * time.Second
+
+func isMoveDone(volume *types.Volume) bool {
+	return volume.Status.Move == nil || volume.Status.Move.State == directpvtypes.MoveStateCompleted
+}
+
+func updateMoveStatus(ctx context.Context, volumeName string, updateFunc func(move *types.MoveStatus)) (*types.Volume, error) {
+	var volume *types.Volume
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() (err error) {
+		volume, err = client.VolumeClient().Get(ctx, volumeName, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		if err != nil {
+			return err
+		}
+		if volume.Status.Move == nil {
+			return fmt.Errorf("volume %v is not being moved", volumeName)
+		}
+		updateFunc(volume.Status.Move)
+		volume, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{TypeMeta: types.NewVolumeTypeMeta()})
+		return err
+	})
+	return volume, err
+}
+
+// isSourceDataLost checks whether volume data on the source drive is not
+// accessible anymore i.e. the source drive is removed or lost.
+func isSourceDataLost(ctx context.Context, move *types.MoveStatus) (bool, error) {
+	drive, err := client.DriveClient().Get(ctx, string(move.SourceDriveID), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	switch drive.Status.Status {
+	case directpvtypes.DriveStatusLost, directpvtypes.DriveStatusError:
+		return true, nil
+	}
+	return false, nil
+}
+
+// copyMoveData copies the volume data from the source drive to the volume
+// directory on the destination drive and verifies the copy. Progress is
+// recorded in move status of the volume.
+func (handler *driveEventHandler) copyMoveData(ctx context.Context, volume *types.Volume, volumeDir string) (codes.Code, error) {
+	move := volume.Status.Move
+	if move == nil || move.State == directpvtypes.MoveStateVerified || move.State == directpvtypes.MoveStateCompleted {
+		return codes.OK, nil
+	}
+
+	source := types.GetVolumeDir(move.SourceFSUUID, volume.Name)
+	if err := handler.exists(source); err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return codes.Internal, fmt.Errorf("unable to access volume data on source drive; %w", err)
+		}
+
+		var message string
+		if err := handler.exists(types.GetVolumeRootDir(move.SourceFSUUID)); err != nil {
+			// Source drive is not mounted.
+			lost, err := isSourceDataLost(ctx, move)
+			if err != nil {
+				return codes.Internal, err
+			}
+			if !lost {
+				return codes.Unavailable, fmt.Errorf("source drive %v of volume %v is not mounted", move.SourceDriveID, volume.Name)
+			}
+			message = fmt.Sprintf("volume data not available on source drive %v", move.SourceDriveID)
+			client.Eventf(volume, client.EventTypeWarning, client.EventReasonVolumeMoveFailed, "Volume moved without data; %v", message)
+		}
+
+		updatedVolume, err := updateMoveStatus(ctx, volume.Name, func(move *types.MoveStatus) {
+			move.State = directpvtypes.MoveStateVerified
+			move.Error = message
+		})
+		if err != nil {
+			return codes.Internal, err
+		}
+		*volume = *updatedVolume
+		return codes.OK, nil
+	}
+
+	klog.V(3).InfoS("Moving volume data", "volume", volume.Name, "source", source, "target", volumeDir)
+
+	_, err := updateMoveStatus(ctx, volume.Name, func(move *types.MoveStatus) {
+		move.State = directpvtypes.MoveStateInProgress
+		move.CopiedBytes = 0
+		move.Error = ""
+	})
+	if err != nil {
+		return codes.Internal, err
+	}
+
+	var lastUpdate time.Time
+	var totalBytes int64
+	progress := func(copiedBytes, total int64) {
+		totalBytes = total
+		if time.Since(lastUpdate) < moveProgressInterval {
+			return
+		}
+		lastUpdate = time.Now()
+		_, err := updateMoveStatus(ctx, volume.Name, func(move *types.MoveStatus) {
+			move.CopiedBytes = copiedBytes
+			move.TotalBytes = total
+		})
+		if err != nil {
+			klog.ErrorS(err, "unable to update move progress", "volume", volume.Name)
+		}
+	}
+
+	// Source and destination drives are different filesystems; hence data is
+	// always copied without reflink.
+	moveErr := handler.copyData(ctx, source, volumeDir, false, progress)
+	if moveErr == nil {
+		moveErr = handler.verifyData(ctx, source, volumeDir)
+	}
+
+	updatedVolume, err := updateMoveStatus(ctx, volume.Name, func(move *types.MoveStatus) {
+		if moveErr != nil {
+			move.State = directpvtypes.MoveStateFailed
+			move.Error = moveErr.Error()
+			return
+		}
+		move.State = directpvtypes.MoveStateVerified
+		move.CopiedBytes = totalBytes
+		move.TotalBytes = totalBytes
+	})
+	if err != nil {
+		klog.ErrorS(err, "unable to update move status", "volume", volume.Name)
+		if moveErr == nil {
+			return codes.Internal, err
+		}
+	}
+
+	if moveErr != nil {
+		klog.ErrorS(moveErr, "unable to move volume data", "volume", volume.Name, "source", source)
+		client.Eventf(volume, client.EventTypeWarning, client.EventReasonVolumeMoveFailed, "unable to move data from drive %v; %v", move.SourceDriveID, moveErr)
+		return codes.Internal, moveErr
+	}
+
+	*volume = *updatedVolume
+	return codes.OK, nil
+}
+
+// releaseMoveSource removes the volume data and reservation from the source
+// drive after the volume data is verified on the destination drive.
+func (handler *driveEventHandler) releaseMoveSource(ctx context.Context, volume *types.Volume) error {
+	move := volume.Status.Move
+	if move.State != directpvtypes.MoveStateVerified {
+		return fmt.Errorf("volume data of %v is not verified on destination drive", volume.Name)
+	}
+
+	source := types.GetVolumeDir(move.SourceFSUUID, volume.Name)
+	if err := handler.removeAll(source); err != nil {
+		return fmt.Errorf("unable to remove volume data on source drive %v; %w", move.SourceDriveID, err)
+	}
+
+	updateFunc := func() error {
+		drive, err := client.DriveClient().Get(ctx, string(move.SourceDriveID), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			return err
+		}
+		if !drive.RemoveVolumeFinalizer(volume.Name) {
+			return nil
+		}
+		drive.Status.FreeCapacity += volume.Status.TotalCapacity
+		drive.Status.AllocatedCapacity -= volume.Status.TotalCapacity
+		_, err = client.DriveClient().Update(ctx, drive, metav1.UpdateOptions{TypeMeta: types.NewDriveTypeMeta()})
+		return err
+	}
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		return fmt.Errorf("unable to release source drive %v; %w", move.SourceDriveID, err)
+	}
+
+	updatedVolume, err := updateMoveStatus(ctx, volume.Name, func(move *types.MoveStatus) {
+		move.State = directpvtypes.MoveStateCompleted
+	})
+	if err != nil {
+		return err
+	}
+	*volume = *updatedVolume
+	return nil
+}
+
+// moveVolume stages the volume on the destination drive by filling the
+// volume directory from the source drive and releases the source drive.
+func (handler *driveEventHandler) moveVolume(ctx context.Context, volume *types.Volume) error {
+	staged := volume.IsStaged()
+	if staged && !volume.IsBlock() {
+		// Staging target path is still bind-mounted from the source drive.
+		if err := handler.unmount(volume.Status.StagingTargetPath); err != nil {
+			return fmt.Errorf("unable to unmount staging target path %v; %w", volume.Status.StagingTargetPath, err)
+		}
+	}
+
+	_, err := StageVolume(
+		ctx,
+		volume,
+		volume.Status.StagingTargetPath,
+		handler.getDeviceByFSUUID,
+		handler.mkdir,
+		handler.setOwnership,
+		handler.setQuota,
+		handler.bindMount,
+		func() (rootMap map[string]utils.StringSet, err error) {
+			_, _, rootMap, err = handler.getMounts()
+			return
+		},
+		handler.copyMoveData,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to stage volume %v on destination drive; %w", volume.Name, err)
+	}
+
+	if !staged {
+		// Unstaged volume is staged again by NodeStageVolume.
+		updateFunc := func() error {
+			updatedVolume, err := client.VolumeClient().Get(ctx, volume.Name, metav1.GetOptions{TypeMeta: types.NewVolumeTypeMeta()})
+			if err != nil {
+				return err
+			}
+			updatedVolume.Status.DataPath = ""
+			updatedVolume.Status.Status = directpvtypes.VolumeStatusPending
+			if updatedVolume, err = client.VolumeClient().Update(ctx, updatedVolume, metav1.UpdateOptions{TypeMeta: types.NewVolumeTypeMeta()}); err != nil {
+				return err
+			}
+			*volume = *updatedVolume
+			return nil
+		}
+		if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+			return err
+		}
+	}
+
+	return handler.releaseMoveSource(ctx, volume)
+}
@@ -0,0 +1,157 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/utils"
+	"github.com/minio/directpv/pkg/xfs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const testVolumeSize = 10 * 1024 * 1024
+
+func newMoveTestDrive(driveID directpvtypes.DriveID, fsuuid string, status directpvtypes.DriveStatus) *types.Drive {
+	drive := types.NewDrive(
+		driveID,
+		types.DriveStatus{
+			Status:            status,
+			FSUUID:            fsuuid,
+			TotalCapacity:     10 * testVolumeSize,
+			FreeCapacity:      9 * testVolumeSize,
+			AllocatedCapacity: testVolumeSize,
+		},
+		"node-1",
+		directpvtypes.DriveName(driveID),
+		directpvtypes.AccessTierDefault,
+	)
+	drive.AddVolumeFinalizer("volume-1")
+	return drive
+}
+
+func newMoveTestHandler(copyErr *error, sources *[]string, removed *[]string) *driveEventHandler {
+	return &driveEventHandler{
+		nodeID: "node-1",
+		getMounts: func() (mountPointMap, deviceMap, rootMountPointMap map[string]utils.StringSet, err error) {
+			return nil, nil, nil, nil
+		},
+		unmount:           func(string) error { return nil },
+		mkdir:             func(string) error { return nil },
+		setOwnership:      func(string, *types.VolumeOwnership) error { return nil },
+		bindMount:         func(string, string, bool, []string) error { return nil },
+		getDeviceByFSUUID: func(string) (string, error) { return "/dev/sdb", nil },
+		setQuota: func(context.Context, string, string, uint32, xfs.Quota, bool) error {
+			return nil
+		},
+		exists: func(string) error { return nil },
+		copyData: func(_ context.Context, source, _ string, _ bool, progress xfs.ProgressFunc) error {
+			*sources = append(*sources, source)
+			progress(testVolumeSize, testVolumeSize)
+			return *copyErr
+		},
+		verifyData: func(context.Context, string, string) error { return nil },
+		removeAll: func(path string) error {
+			*removed = append(*removed, path)
+			return nil
+		},
+	}
+}
+
+func TestMoveVolume(t *testing.T) {
+	volume := types.NewVolume("volume-1", "fsuuid-1", "node-1", "drive-1", "drive-1", testVolumeSize)
+	volume.Status.Status = directpvtypes.VolumeStatusReady
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(
+		newMoveTestDrive("drive-1", "fsuuid-1", directpvtypes.DriveStatusReady),
+		newMoveTestDrive("drive-2", "fsuuid-2", directpvtypes.DriveStatusMoving),
+		volume,
+	))
+	client.SetDriveInterface(clientset.DirectpvLatest().DirectPVDrives())
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+	var sources, removed []string
+	copyErr := errors.New("no space left on device")
+	handler := newMoveTestHandler(&copyErr, &sources, &removed)
+
+	getDrive := func(driveID string) *types.Drive {
+		drive, err := client.DriveClient().Get(context.TODO(), driveID, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unable to get drive; %v", err)
+		}
+		return drive
+	}
+	getVolume := func() *types.Volume {
+		volume, err := client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unable to get volume; %v", err)
+		}
+		return volume
+	}
+
+	// Failed copy retains volume data and reservation in source drive.
+	if err := handler.move(context.TODO(), getDrive("drive-2")); err == nil {
+		t.Fatalf("expected error; but succeeded")
+	}
+	volume = getVolume()
+	if volume.Status.Move == nil || volume.Status.Move.State != directpvtypes.MoveStateFailed || volume.Status.Move.Error == "" {
+		t.Fatalf("unexpected move status; %+v", volume.Status.Move)
+	}
+	if volume.GetDriveID() != "drive-2" || volume.Status.FSUUID != "fsuuid-2" {
+		t.Fatalf("unexpected volume drive; driveID: %v, FSUUID: %v", volume.GetDriveID(), volume.Status.FSUUID)
+	}
+	if len(removed) != 0 {
+		t.Fatalf("source volume data must not be removed; %v", removed)
+	}
+	if drive := getDrive("drive-1"); !drive.VolumeExist("volume-1") {
+		t.Fatalf("source drive must retain volume reservation")
+	}
+	if drive := getDrive("drive-2"); drive.Status.Status != directpvtypes.DriveStatusMoving {
+		t.Fatalf("destination drive: status: expected: %v, got: %v", directpvtypes.DriveStatusMoving, drive.Status.Status)
+	}
+
+	// Move is resumed on retry.
+	copyErr = nil
+	if err := handler.move(context.TODO(), getDrive("drive-2")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	sourceDir := types.GetVolumeDir("fsuuid-1", "volume-1")
+	if len(sources) != 2 || sources[1] != sourceDir {
+		t.Fatalf("copy source: expected: %v, got: %v", sourceDir, sources)
+	}
+	if len(removed) != 1 || removed[0] != sourceDir {
+		t.Fatalf("removed source: expected: %v, got: %v", sourceDir, removed)
+	}
+	volume = getVolume()
+	if volume.Status.Move.State != directpvtypes.MoveStateCompleted || volume.Status.Move.CopiedBytes != testVolumeSize {
+		t.Fatalf("unexpected move status; %+v", volume.Status.Move)
+	}
+	if volume.Status.Status != directpvtypes.VolumeStatusPending {
+		t.Fatalf("volume status: expected: %v, got: %v", directpvtypes.VolumeStatusPending, volume.Status.Status)
+	}
+	if drive := getDrive("drive-1"); drive.VolumeExist("volume-1") || drive.Status.FreeCapacity != 10*testVolumeSize {
+		t.Fatalf("source drive: unexpected reservation; volumes: %v, free capacity: %v", drive.GetVolumes(), drive.Status.FreeCapacity)
+	}
+	if drive := getDrive("drive-2"); drive.Status.Status != directpvtypes.DriveStatusReady {
+		t.Fatalf("destination drive: status: expected: %v, got: %v", directpvtypes.DriveStatusReady, drive.Status.Status)
+	}
+}
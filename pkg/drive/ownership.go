@@ -0,0 +1,120 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/minio/directpv/pkg/types"
+)
+
+// SetVolumeOwnership sets owner, group and permission mode of volume directory.
+func SetVolumeOwnership(volumeDir string, ownership *types.VolumeOwnership) error {
+	if ownership == nil {
+		return nil
+	}
+
+	if err := os.Chown(volumeDir, int(ownership.UID), int(ownership.GID)); err != nil {
+		return err
+	}
+
+	if ownership.Mode == "" {
+		return nil
+	}
+
+	mode, err := strconv.ParseUint(ownership.Mode, 8, 32)
+	if err != nil {
+		return fmt.Errorf("invalid mode %v; %w", ownership.Mode, err)
+	}
+	return os.Chmod(volumeDir, toFileMode(mode))
+}
+
+// SetVolumeMountGroup recursively sets group of files in volume directory to
+// gid with group read/write permission and set-group-ID permission on
+// directories like kubelet does for fsGroup. If onRootMismatch is set, the
+// change is skipped when volume directory already has expected group and
+// permissions.
+func SetVolumeMountGroup(volumeDir string, gid int, onRootMismatch bool) error {
+	if onRootMismatch {
+		info, err := os.Stat(volumeDir)
+		if err != nil {
+			return err
+		}
+		if hasMountGroup(info, gid) {
+			return nil
+		}
+	}
+
+	return filepath.WalkDir(volumeDir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := os.Lchown(path, -1, gid); err != nil {
+			return err
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return nil // Permission of symlink is not used.
+		}
+
+		mode := info.Mode() | getMountGroupMode(info.IsDir())
+		if mode == info.Mode() {
+			return nil
+		}
+		return os.Chmod(path, mode)
+	})
+}
+
+func getMountGroupMode(isDir bool) os.FileMode {
+	if isDir {
+		return 0o770 | os.ModeSetgid
+	}
+	return 0o660
+}
+
+func hasMountGroup(info fs.FileInfo, gid int) bool {
+	if fileGID, found := getFileGID(info); !found || fileGID != gid {
+		return false
+	}
+	mode := getMountGroupMode(info.IsDir())
+	return info.Mode()&mode == mode
+}
+
+// toFileMode converts unix permission mode bits to os.FileMode.
+func toFileMode(mode uint64) os.FileMode {
+	fileMode := os.FileMode(mode & 0o777)
+	if mode&0o4000 != 0 {
+		fileMode |= os.ModeSetuid
+	}
+	if mode&0o2000 != 0 {
+		fileMode |= os.ModeSetgid
+	}
+	if mode&0o1000 != 0 {
+		fileMode |= os.ModeSticky
+	}
+	return fileMode
+}
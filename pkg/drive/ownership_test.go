@@ -0,0 +1,89 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/minio/directpv/pkg/types"
+)
+
+func TestSetVolumeOwnership(t *testing.T) {
+	volumeDir := t.TempDir()
+	uid, gid := os.Getuid(), os.Getgid()
+
+	ownership := &types.VolumeOwnership{UID: int64(uid), GID: int64(gid), Mode: "2750"}
+	if err := SetVolumeOwnership(volumeDir, ownership); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	info, err := os.Stat(volumeDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := os.FileMode(0o750) | os.ModeSetgid; info.Mode()&(os.ModePerm|os.ModeSetgid) != expected {
+		t.Fatalf("mode: expected: %v, got: %v", expected, info.Mode())
+	}
+
+	if err := SetVolumeOwnership(volumeDir, &types.VolumeOwnership{UID: int64(uid), GID: int64(gid), Mode: "abc"}); err == nil {
+		t.Fatalf("expected error, but succeeded")
+	}
+
+	subDir := filepath.Join(volumeDir, "dir1")
+	file := filepath.Join(subDir, "file1")
+	if err := os.Mkdir(subDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chmod(volumeDir, 0o700); err != nil {
+		t.Fatal(err)
+	}
+
+	checkMode := func(path string, expected os.FileMode) {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode()&(os.ModePerm|os.ModeSetgid) != expected {
+			t.Fatalf("%v: mode: expected: %v, got: %v", path, expected, info.Mode())
+		}
+	}
+
+	if err := SetVolumeMountGroup(volumeDir, gid, false); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	checkMode(volumeDir, os.FileMode(0o770)|os.ModeSetgid)
+	checkMode(subDir, os.FileMode(0o770)|os.ModeSetgid)
+	checkMode(file, 0o660)
+
+	// Matching volume directory skips the change for OnRootMismatch policy.
+	if err := os.Chmod(file, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetVolumeMountGroup(volumeDir, gid, true); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	checkMode(file, 0o600)
+
+	if err := SetVolumeMountGroup(volumeDir, gid, false); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	checkMode(file, 0o660)
+}
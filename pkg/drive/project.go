@@ -0,0 +1,165 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path"
+	"strings"
+	"sync"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/xfs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// Volumes of a drive are staged only by the node server of the drive;
+// hence serializing project ID allocation in this process is collision safe.
+var projectIDMutex sync.Mutex
+
+func getUsedProjectIDs(ctx context.Context, volume *types.Volume) (map[uint32]string, error) {
+	volumes, err := client.NewVolumeLister().
+		DriveIDSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(volume.GetDriveID()))}).
+		Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs := map[uint32]string{}
+	for _, vol := range volumes {
+		if vol.Name == volume.Name {
+			continue
+		}
+
+		projectID := vol.GetProjectID()
+		if projectID == 0 && vol.Status.DataPath != "" && !vol.IsBlock() {
+			projectID = xfs.GetLegacyProjectID(vol.Name)
+		}
+		if projectID != 0 {
+			projectIDs[projectID] = vol.Name
+		}
+	}
+
+	deletedProjectIDs, err := getDeletedProjectIDs(types.GetVolumeRootDir(volume.Status.FSUUID), xfs.GetProjectID)
+	if err != nil {
+		return nil, err
+	}
+	for projectID, name := range deletedProjectIDs {
+		projectIDs[projectID] = name
+	}
+
+	return projectIDs, nil
+}
+
+// getDeletedProjectIDs returns project IDs of deleted volume directories in the
+// volume root directory. Deleted volume directory is removed asynchronously
+// and it is charged to its project ID until it is removed.
+func getDeletedProjectIDs(rootDir string, getProjectID func(path string) (uint32, error)) (map[uint32]string, error) {
+	entries, err := os.ReadDir(rootDir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	projectIDs := map[uint32]string{}
+	for _, entry := range entries {
+		if !entry.IsDir() || !strings.HasSuffix(entry.Name(), ".deleted") {
+			continue
+		}
+
+		projectID, err := getProjectID(path.Join(rootDir, entry.Name()))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			continue // Removed in the meantime.
+		case err != nil:
+			return nil, err
+		case projectID != 0:
+			projectIDs[projectID] = entry.Name()
+		}
+	}
+
+	return projectIDs, nil
+}
+
+func updateProjectID(ctx context.Context, volume *types.Volume, projectID uint32) error {
+	volume.SetProjectID(projectID)
+	updatedVolume, err := client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
+		TypeMeta: types.NewVolumeTypeMeta(),
+	})
+	if err != nil {
+		return err
+	}
+	*volume = *updatedVolume
+	return nil
+}
+
+// assignProjectID assigns XFS project ID unique on the drive to the volume.
+// Volume staged by older versions keeps its project ID derived from volume
+// name unless it collides with other volume on the drive.
+func assignProjectID(ctx context.Context, volume *types.Volume) error {
+	projectIDMutex.Lock()
+	defer projectIDMutex.Unlock()
+
+	usedProjectIDs, err := getUsedProjectIDs(ctx, volume)
+	if err != nil {
+		return err
+	}
+
+	projectID := volume.GetProjectID()
+	if projectID == 0 && volume.Status.DataPath != "" {
+		projectID = xfs.GetLegacyProjectID(volume.Name)
+	}
+
+	if projectID != 0 {
+		volumeName, found := usedProjectIDs[projectID]
+		if !found {
+			if volume.GetProjectID() == projectID {
+				return nil
+			}
+			return updateProjectID(ctx, volume, projectID)
+		}
+
+		// Colliding project ID shares quota with other volume; hence a new project ID is assigned.
+		klog.InfoS(
+			"project ID collides with other volume; assigning new project ID",
+			"volume", volume.Name,
+			"projectID", projectID,
+			"collidingVolume", volumeName,
+			"drive", volume.GetDriveID(),
+		)
+		client.Eventf(
+			volume, client.EventTypeWarning, client.EventReasonStageVolume,
+			"project ID %v collides with volume %v on drive %v; new project ID is assigned",
+			projectID, volumeName, volume.GetDriveID(),
+		)
+	}
+
+	for projectID = 1; ; projectID++ {
+		if _, found := usedProjectIDs[projectID]; !found {
+			break
+		}
+	}
+
+	return updateProjectID(ctx, volume, projectID)
+}
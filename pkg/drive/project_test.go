@@ -0,0 +1,124 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"context"
+	"os"
+	"path"
+	"testing"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/xfs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func init() {
+	client.FakeInit()
+}
+
+func TestAssignProjectID(t *testing.T) {
+	newVolume := func(name, driveID string, projectID uint32, dataPath string) *types.Volume {
+		volume := types.NewVolume(name, "fsuuid1", "node-1", "drive-1", "sda", 100)
+		volume.SetDriveID(directpvtypes.DriveID(driveID))
+		volume.SetProjectID(projectID)
+		volume.Status.DataPath = dataPath
+		return volume
+	}
+
+	testCases := []struct {
+		volume            *types.Volume
+		objects           []runtime.Object
+		expectedProjectID uint32
+	}{
+		// new volume gets first free project ID.
+		{newVolume("volume-1", "drive-1", 0, ""), nil, 1},
+		{
+			newVolume("volume-1", "drive-1", 0, ""),
+			[]runtime.Object{newVolume("volume-2", "drive-1", 1, "/data/volume-2"), newVolume("volume-3", "drive-1", 3, "/data/volume-3")},
+			2,
+		},
+		// project IDs on other drives are not considered.
+		{newVolume("volume-1", "drive-1", 0, ""), []runtime.Object{newVolume("volume-2", "drive-2", 1, "/data/volume-2")}, 1},
+		// assigned project ID is kept.
+		{newVolume("volume-1", "drive-1", 5, "/data/volume-1"), []runtime.Object{newVolume("volume-2", "drive-1", 1, "/data/volume-2")}, 5},
+		// colliding project ID is reassigned.
+		{newVolume("volume-1", "drive-1", 1, "/data/volume-1"), []runtime.Object{newVolume("volume-2", "drive-1", 1, "/data/volume-2")}, 2},
+		// volume staged by older version is migrated to legacy project ID.
+		{newVolume("volume-1", "drive-1", 0, "/data/volume-1"), nil, xfs.GetLegacyProjectID("volume-1")},
+		// legacy project ID of not yet migrated volume is considered.
+		{
+			newVolume("volume-1", "drive-1", xfs.GetLegacyProjectID("volume-2"), "/data/volume-1"),
+			[]runtime.Object{newVolume("volume-2", "drive-1", 0, "/data/volume-2")},
+			1,
+		},
+	}
+
+	for i, testCase := range testCases {
+		objects := append([]runtime.Object{testCase.volume.DeepCopy()}, testCase.objects...)
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(objects...))
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		volume := testCase.volume.DeepCopy()
+		if err := assignProjectID(context.TODO(), volume); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if volume.GetProjectID() != testCase.expectedProjectID {
+			t.Fatalf("case %v: project ID: expected: %v, got: %v", i+1, testCase.expectedProjectID, volume.GetProjectID())
+		}
+
+		updatedVolume, err := client.VolumeClient().Get(context.TODO(), volume.Name, metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if updatedVolume.GetProjectID() != testCase.expectedProjectID {
+			t.Fatalf("case %v: recorded project ID: expected: %v, got: %v", i+1, testCase.expectedProjectID, updatedVolume.GetProjectID())
+		}
+	}
+}
+
+func TestGetDeletedProjectIDs(t *testing.T) {
+	rootDir := t.TempDir()
+	for _, name := range []string{"volume-1", "volume-2.deleted", "volume-3.deleted"} {
+		if err := os.Mkdir(path.Join(rootDir, name), 0o755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(path.Join(rootDir, "volume-4.deleted"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	fakeProjectIDs := map[string]uint32{"volume-1": 1, "volume-2.deleted": 2, "volume-3.deleted": 0}
+	projectIDs, err := getDeletedProjectIDs(rootDir, func(dir string) (uint32, error) {
+		return fakeProjectIDs[path.Base(dir)], nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if len(projectIDs) != 1 || projectIDs[2] != "volume-2.deleted" {
+		t.Fatalf("expected: map[2:volume-2.deleted], got: %v", projectIDs)
+	}
+
+	projectIDs, err = getDeletedProjectIDs(path.Join(rootDir, "unknown"), nil)
+	if err != nil || len(projectIDs) != 0 {
+		t.Fatalf("expected no project IDs, got: %v; %v", projectIDs, err)
+	}
+}
@@ -19,9 +19,13 @@ package drive
 import (
 	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"os/exec"
 	"path"
+	"regexp"
+	"strings"
 	"sync"
 
 	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
@@ -36,12 +40,23 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// repairIssueRegex matches xfs_repair output lines reporting filesystem inconsistencies.
+var repairIssueRegex = regexp.MustCompile(`(?i)(\bbad\b|corrupt|correcting|fixing|junking|clearing|zeroing|invalid|disconnected (dir )?inode \d|would |^alert:)`)
+
 type logWriter struct {
 	buffer []byte
+	lines  []string
 	closed bool
 	mutex  sync.Mutex
 }
 
+func (w *logWriter) addLine(line string) {
+	klog.Info(line)
+	if line = strings.TrimSpace(line); line != "" {
+		w.lines = append(w.lines, line)
+	}
+}
+
 func (w *logWriter) Write(data []byte) (n int, err error) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
@@ -58,7 +73,7 @@ func (w *logWriter) Write(data []byte) (n int, err error) {
 			break
 		}
 
-		klog.Info(string(w.buffer[:index+1]))
+		w.addLine(string(w.buffer[:index+1]))
 		w.buffer = w.buffer[index+1:]
 	}
 
@@ -69,12 +84,65 @@ func (w *logWriter) Close() (err error) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
 
-	klog.Info(string(w.buffer))
+	w.addLine(string(w.buffer))
 	w.buffer = nil
 	w.closed = true
 	return nil
 }
 
+func summarizeRepairIssues(issues []string) string {
+	switch len(issues) {
+	case 0:
+		return "0 inconsistencies"
+	case 1:
+		return "1 inconsistency; " + issues[0]
+	default:
+		return fmt.Sprintf("%v inconsistencies; first: %v", len(issues), issues[0])
+	}
+}
+
+// getRepairResult returns the outcome of xfs_repair and its summary from the
+// output and the error of xfs_repair.
+func getRepairResult(lines []string, dryRun bool, err error) (directpvtypes.DriveConditionReason, string) {
+	var issues []string
+	var logDirty bool
+	var lastLine string
+	for _, line := range lines {
+		lastLine = line
+		switch {
+		case strings.HasPrefix(line, "ERROR:") && strings.Contains(line, "valuable metadata changes in a log"):
+			logDirty = true
+		case repairIssueRegex.MatchString(line):
+			issues = append(issues, line)
+		}
+	}
+
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+
+	switch {
+	case err == nil && (dryRun || len(issues) == 0):
+		// xfs_repair in no modify mode exits with error if inconsistencies are found.
+		return directpvtypes.DriveConditionReasonRepairClean, "no inconsistencies found"
+	case err == nil:
+		return directpvtypes.DriveConditionReasonRepairFixed, "fixed " + summarizeRepairIssues(issues)
+	case logDirty || exitCode == 2:
+		return directpvtypes.DriveConditionReasonRepairNeedsLogZeroing,
+			"filesystem log has unreplayed metadata changes; mount the drive to replay the log or repair with --force to zero the log"
+	case dryRun && exitCode == 1:
+		return directpvtypes.DriveConditionReasonRepairCorrupted, "found " + summarizeRepairIssues(issues) + " in no modify mode"
+	default:
+		message := err.Error()
+		if lastLine != "" {
+			message = lastLine + "; " + message
+		}
+		return directpvtypes.DriveConditionReasonRepairUnrecoverable, message
+	}
+}
+
 func repair(ctx context.Context, drive *types.Drive, force, disablePrefetch, dryRun bool,
 	getDeviceByFSUUID func(fsuuid string) (string, error),
 	getMounts func() (deviceMap map[string]utils.StringSet, err error),
@@ -149,15 +217,17 @@ func repair(ctx context.Context, drive *types.Drive, force, disablePrefetch, dry
 	}
 
 	logWriter := &logWriter{}
-	err = repair(ctx, device, force, disablePrefetch, dryRun, logWriter)
+	rerr := repair(ctx, device, force, disablePrefetch, dryRun, logWriter)
 	logWriter.Close()
-	if err != nil {
-		return err
-	}
+	result, summary := getRepairResult(logWriter.lines, dryRun, rerr)
+	klog.InfoS("Drive repair completed", "drive", drive.GetDriveID(), "result", result, "summary", summary)
 
-	merr := mount(device, target)
-	if merr != nil {
-		klog.ErrorS(err, "unable to mount the drive", "Source", device, "Target", target)
+	// Drive is not modified in no modify mode; hence it is mounted back.
+	var merr error
+	if rerr == nil || dryRun {
+		if merr = mount(device, target); merr != nil {
+			klog.ErrorS(merr, "unable to mount the drive", "Source", device, "Target", target)
+		}
 	}
 
 	driveID := drive.GetDriveID()
@@ -167,15 +237,33 @@ func repair(ctx context.Context, drive *types.Drive, force, disablePrefetch, dry
 			return err
 		}
 
-		if merr != nil {
-			drive.SetMountErrorCondition(fmt.Sprintf("unable to mount; %v", err))
+		drive.SetRepairedCondition(result, summary)
+		if drive.GetRepairedCondition().Status == metav1.ConditionTrue {
+			client.Eventf(drive,
+				client.EventTypeNormal,
+				client.EventReasonDriveRepaired,
+				"Drive repair completed with %v result; %v", result, summary,
+			)
+		} else {
+			client.Eventf(drive,
+				client.EventTypeWarning,
+				client.EventReasonDriveRepairFailed,
+				"Drive repair completed with %v result; %v", result, summary,
+			)
+		}
+
+		switch {
+		case rerr != nil && !dryRun:
+			drive.Status.Status = directpvtypes.DriveStatusError
+		case merr != nil:
+			drive.SetMountErrorCondition(fmt.Sprintf("unable to mount; %v", merr))
 			client.Eventf(drive,
 				client.EventTypeWarning,
 				client.EventReasonDriveMountError,
-				"unable to mount the drive; %v", err,
+				"unable to mount the drive; %v", merr,
 			)
 			drive.Status.Status = directpvtypes.DriveStatusError
-		} else {
+		default:
 			client.Eventf(
 				drive,
 				client.EventTypeNormal,
@@ -189,7 +277,10 @@ func repair(ctx context.Context, drive *types.Drive, force, disablePrefetch, dry
 		return err
 	}
 
-	return retry.RetryOnConflict(retry.DefaultRetry, updateFunc)
+	if err := retry.RetryOnConflict(retry.DefaultRetry, updateFunc); err != nil {
+		return err
+	}
+	return rerr
 }
 
 // Repair runs `xfs_repair` command on specified drive
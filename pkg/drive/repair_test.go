@@ -0,0 +1,115 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package drive
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+	"testing"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+)
+
+const cleanRepairOutput = `Phase 1 - find and verify superblock...
+        - block cache size set to 1493424 entries
+Phase 2 - using internal log
+        - zero log...
+zero_log: head block 2 tail block 2
+        - scan filesystem freespace and inode maps...
+        - found root inode chunk
+Phase 3 - for each AG...
+        - scan and clear agi unlinked lists...
+        - process known inodes and perform inode discovery...
+        - agno = 0
+        - process newly discovered inodes...
+Phase 4 - check for duplicate blocks...
+        - setting up duplicate extent list...
+        - check for inodes claiming duplicate blocks...
+        - agno = 0
+Phase 5 - rebuild AG headers and trees...
+        - reset superblock...
+Phase 6 - check inode connectivity...
+        - resetting contents of realtime bitmap and summary inodes
+        - traversing filesystem ...
+        - traversal finished ...
+        - moving disconnected inodes to lost+found ...
+Phase 7 - verify and correct link counts...
+done`
+
+func newExitError(t *testing.T, exitCode int) error {
+	err := exec.Command("sh", "-c", fmt.Sprintf("exit %v", exitCode)).Run()
+	var exitErr *exec.ExitError
+	if !errors.As(err, &exitErr) {
+		t.Fatalf("unable to create exit error; %v", err)
+	}
+	return fmt.Errorf("unable to run xfs_repair on device /dev/sdb; %w", err)
+}
+
+func TestGetRepairResult(t *testing.T) {
+	lines := func(output string) (result []string) {
+		for _, line := range strings.Split(output, "\n") {
+			result = append(result, strings.TrimSpace(line))
+		}
+		return result
+	}
+
+	testCases := []struct {
+		output         string
+		dryRun         bool
+		err            error
+		expectedResult directpvtypes.DriveConditionReason
+	}{
+		{cleanRepairOutput, false, nil, directpvtypes.DriveConditionReasonRepairClean},
+		{cleanRepairOutput, true, nil, directpvtypes.DriveConditionReasonRepairClean},
+		{
+			"Phase 3 - for each AG...\nbad magic number 0x0 on inode 131, resetting magic number\n" + cleanRepairOutput,
+			false,
+			nil,
+			directpvtypes.DriveConditionReasonRepairFixed,
+		},
+		{
+			"Phase 3 - for each AG...\nbad magic number 0x0 on inode 131, would reset magic number\nNo modify flag set, skipping phase 5",
+			true,
+			newExitError(t, 1),
+			directpvtypes.DriveConditionReasonRepairCorrupted,
+		},
+		{
+			"Phase 2 - using internal log\n        - zero log...\nERROR: The filesystem has valuable metadata changes in a log which needs to\nbe replayed.",
+			false,
+			newExitError(t, 2),
+			directpvtypes.DriveConditionReasonRepairNeedsLogZeroing,
+		},
+		{
+			"Phase 1 - find and verify superblock...\nSorry, could not find valid secondary superblock\nExiting now.",
+			false,
+			newExitError(t, 1),
+			directpvtypes.DriveConditionReasonRepairUnrecoverable,
+		},
+	}
+
+	for i, testCase := range testCases {
+		result, summary := getRepairResult(lines(testCase.output), testCase.dryRun, testCase.err)
+		if result != testCase.expectedResult {
+			t.Fatalf("case %v: result: expected: %v, got: %v", i+1, testCase.expectedResult, result)
+		}
+		if summary == "" {
+			t.Fatalf("case %v: empty summary", i+1)
+		}
+	}
+}
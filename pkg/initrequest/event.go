@@ -287,6 +287,7 @@ func (handler *initRequestEventHandler) initDevice(device pkgdevice.Device, forc
 		directpvtypes.DriveName(device.Name),
 		directpvtypes.AccessTierDefault,
 	)
+	drive.SetReflinkLabel(handler.reflink)
 	if _, err = client.DriveClient().Create(context.Background(), drive, metav1.CreateOptions{}); err != nil {
 		return fmt.Errorf("unable to create Drive CRD; %w", err)
 	}
@@ -33,7 +33,7 @@ type metricsCollector struct {
 	nodeID            directpvtypes.NodeID
 	desc              *prometheus.Desc
 	getDeviceByFSUUID func(fsuuid string) (string, error)
-	getQuota          func(ctx context.Context, device, volumeID string) (quota *xfs.Quota, err error)
+	getQuota          func(ctx context.Context, device string, projectID uint32) (quota *xfs.Quota, err error)
 }
 
 func newMetricsCollector(nodeID directpvtypes.NodeID) *metricsCollector {
@@ -51,6 +51,11 @@ func (c *metricsCollector) Describe(ch chan<- *prometheus.Desc) {
 }
 
 func (c *metricsCollector) publishVolumeStats(ctx context.Context, volume *types.Volume, ch chan<- prometheus.Metric) {
+	if volume.GetProjectID() == 0 {
+		klog.V(5).InfoS("project ID is not assigned to volume", "volume", volume.Name)
+		return
+	}
+
 	device, err := c.getDeviceByFSUUID(volume.Status.FSUUID)
 	if err != nil {
 		klog.ErrorS(
@@ -68,7 +73,7 @@ func (c *metricsCollector) publishVolumeStats(ctx context.Context, volume *types
 				" on the host to reload", volume.Status.FSUUID)
 		return
 	}
-	quota, err := c.getQuota(ctx, device, volume.Name)
+	quota, err := c.getQuota(ctx, device, volume.GetProjectID())
 	if err != nil {
 		klog.ErrorS(err, "unable to get quota information", "volume", volume.Name)
 		return
@@ -93,6 +98,39 @@ func (c *metricsCollector) publishVolumeStats(ctx context.Context, volume *types
 		prometheus.GaugeValue,
 		float64(volume.Status.TotalCapacity), tenantName, volume.Name, string(volume.GetNodeID()),
 	)
+
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName(consts.AppName, "stats", "inodes_used"),
+			"Total number of inodes used by the volume",
+			[]string{"tenant", "volumeID", "node"}, nil),
+		prometheus.GaugeValue,
+		float64(quota.CurrentInodes), tenantName, volume.Name, string(volume.GetNodeID()),
+	)
+
+	nearlyFull := 0
+	if volume.IsNearlyFull() {
+		nearlyFull = 1
+	}
+	ch <- prometheus.MustNewConstMetric(
+		prometheus.NewDesc(
+			prometheus.BuildFQName(consts.AppName, "stats", "nearly_full"),
+			"Whether volume usage is above usage threshold",
+			[]string{"tenant", "volumeID", "node"}, nil),
+		prometheus.GaugeValue,
+		float64(nearlyFull), tenantName, volume.Name, string(volume.GetNodeID()),
+	)
+
+	if volume.Status.InodeLimit > 0 {
+		ch <- prometheus.MustNewConstMetric(
+			prometheus.NewDesc(
+				prometheus.BuildFQName(consts.AppName, "stats", "inodes_total"),
+				"Total number of inodes allocated to the volume",
+				[]string{"tenant", "volumeID", "node"}, nil),
+			prometheus.GaugeValue,
+			float64(volume.Status.InodeLimit), tenantName, volume.Name, string(volume.GetNodeID()),
+		)
+	}
 }
 
 // Collect is called by Prometheus registry when collecting metrics.
@@ -108,7 +146,7 @@ func (c *metricsCollector) Collect(ch chan<- prometheus.Metric) {
 			return
 		}
 
-		if result.Volume.Status.TargetPath != "" {
+		if result.Volume.IsPublished() {
 			c.publishVolumeStats(ctx, &result.Volume, ch)
 		}
 	}
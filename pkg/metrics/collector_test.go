@@ -23,6 +23,7 @@ import (
 	"testing"
 	"time"
 
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
 	"github.com/minio/directpv/pkg/client"
 	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
 	"github.com/minio/directpv/pkg/consts"
@@ -40,10 +41,15 @@ const MiB = 1024 * 1024
 type metricType string
 
 const (
-	metricStatsBytesUsed  metricType = consts.AppName + "_stats_bytes_used"
-	metricStatsBytesTotal metricType = consts.AppName + "_stats_bytes_total"
+	metricStatsBytesUsed   metricType = consts.AppName + "_stats_bytes_used"
+	metricStatsBytesTotal  metricType = consts.AppName + "_stats_bytes_total"
+	metricStatsInodesUsed  metricType = consts.AppName + "_stats_inodes_used"
+	metricStatsInodesTotal metricType = consts.AppName + "_stats_inodes_total"
+	metricStatsNearlyFull  metricType = consts.AppName + "_stats_nearly_full"
 )
 
+const testInodesUsed = 100
+
 var volumes []types.Volume
 
 func init() {
@@ -53,8 +59,12 @@ func init() {
 	}
 	volumes[0].Status.UsedCapacity = 10 * MiB
 	volumes[0].Status.TargetPath = "/path/targetpath"
+	volumes[0].SetProjectID(1)
 	volumes[1].Status.UsedCapacity = 20 * MiB
 	volumes[1].Status.TargetPath = "/path/targetpath"
+	volumes[1].Status.InodeLimit = 1000
+	volumes[1].SetProjectID(2)
+	volumes[1].SetNearlyFull(directpvtypes.VolumeConditionReasonUsageAboveWarningThreshold, "volume usage 85% is above warning threshold 80%")
 	client.FakeInit()
 }
 
@@ -63,13 +73,16 @@ func createFakeMetricsCollector() *metricsCollector {
 		desc:              prometheus.NewDesc(consts.AppName+"_stats", "Statistics exposed by "+consts.AppPrettyName, nil, nil),
 		nodeID:            "test-node-1",
 		getDeviceByFSUUID: func(_ string) (string, error) { return "", nil },
-		getQuota: func(_ context.Context, _, volumeID string) (quota *xfs.Quota, err error) {
+		getQuota: func(_ context.Context, _ string, projectID uint32) (quota *xfs.Quota, err error) {
 			for _, volume := range volumes {
-				if volume.Name == volumeID {
+				if volume.GetProjectID() == projectID {
 					return &xfs.Quota{
-						HardLimit:    uint64(volume.Status.TotalCapacity),
-						SoftLimit:    uint64(volume.Status.TotalCapacity),
-						CurrentSpace: uint64(volume.Status.UsedCapacity),
+						HardLimit:      uint64(volume.Status.TotalCapacity),
+						SoftLimit:      uint64(volume.Status.TotalCapacity),
+						CurrentSpace:   uint64(volume.Status.UsedCapacity),
+						InodeHardLimit: uint64(volume.Status.InodeLimit),
+						InodeSoftLimit: uint64(volume.Status.InodeLimit),
+						CurrentInodes:  testInodesUsed,
 					}, nil
 				}
 			}
@@ -109,8 +122,9 @@ func TestVolumeStatsEmitter(t *testing.T) {
 	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
 
 	metricChan := make(chan prometheus.Metric)
-	noOfMetricsExposedPerVolume := 2
-	expectedNoOfMetrics := len(testObjects) * noOfMetricsExposedPerVolume
+	// inodes_total metric is exposed only for volumes[1] having inode limit.
+	noOfMetricsExposedPerVolume := 4
+	expectedNoOfMetrics := len(testObjects)*noOfMetricsExposedPerVolume + 1
 	noOfMetricsReceived := 0
 	wg.Add(1)
 	go func() {
@@ -151,6 +165,30 @@ func TestVolumeStatsEmitter(t *testing.T) {
 					if volObj.Status.TotalCapacity != int64(*metricOut.Gauge.Value) {
 						t.Errorf("Expected Total capacity: %v But got %v", volObj.Status.TotalCapacity, *metricOut.Gauge.Value)
 					}
+				case metricStatsInodesUsed:
+					if int64(*metricOut.Gauge.Value) != testInodesUsed {
+						t.Errorf("Expected used inodes: %v But got %v", testInodesUsed, *metricOut.Gauge.Value)
+					}
+				case metricStatsInodesTotal:
+					volObj, gErr := client.VolumeClient().Get(ctx, volumeName, metav1.GetOptions{
+						TypeMeta: types.NewVolumeTypeMeta(),
+					})
+					if gErr != nil {
+						(*t).Fatalf("[%s] Volume (%s) not found. Error: %v", volumeName, volumeName, gErr)
+					}
+					if volObj.Status.InodeLimit != int64(*metricOut.Gauge.Value) {
+						t.Errorf("Expected total inodes: %v But got %v", volObj.Status.InodeLimit, *metricOut.Gauge.Value)
+					}
+				case metricStatsNearlyFull:
+					volObj, gErr := client.VolumeClient().Get(ctx, volumeName, metav1.GetOptions{
+						TypeMeta: types.NewVolumeTypeMeta(),
+					})
+					if gErr != nil {
+						(*t).Fatalf("[%s] Volume (%s) not found. Error: %v", volumeName, volumeName, gErr)
+					}
+					if volObj.IsNearlyFull() != (*metricOut.Gauge.Value == 1) {
+						t.Errorf("Expected nearly full: %v But got %v", volObj.IsNearlyFull(), *metricOut.Gauge.Value)
+					}
 				default:
 					t.Errorf("Invalid metric type caught")
 				}
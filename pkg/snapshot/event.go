@@ -0,0 +1,176 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/consts"
+	"github.com/minio/directpv/pkg/controller"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/xfs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	workerThreads = 10
+	resyncPeriod  = 10 * time.Minute
+)
+
+type snapshotEventHandler struct {
+	nodeID    directpvtypes.NodeID
+	reflink   func(ctx context.Context, source, target string) error
+	removeAll func(path string) error
+	mkdirAll  func(path string, perm os.FileMode) error
+}
+
+func newSnapshotEventHandler(nodeID directpvtypes.NodeID) *snapshotEventHandler {
+	return &snapshotEventHandler{
+		nodeID:    nodeID,
+		reflink:   xfs.Reflink,
+		removeAll: os.RemoveAll,
+		mkdirAll:  os.MkdirAll,
+	}
+}
+
+func (handler *snapshotEventHandler) ListerWatcher() cache.ListerWatcher {
+	labelSelector := fmt.Sprintf("%s=%s", directpvtypes.NodeLabelKey, handler.nodeID)
+	return cache.NewFilteredListWatchFromClient(
+		client.RESTClient(),
+		consts.SnapshotResource,
+		"",
+		func(options *metav1.ListOptions) {
+			options.LabelSelector = labelSelector
+		},
+	)
+}
+
+func (handler *snapshotEventHandler) ObjectType() runtime.Object {
+	return &types.Snapshot{}
+}
+
+func (handler *snapshotEventHandler) Handle(ctx context.Context, _ controller.EventType, object runtime.Object) error {
+	snapshot := object.(*types.Snapshot)
+	if !snapshot.GetDeletionTimestamp().IsZero() {
+		return handler.delete(ctx, snapshot)
+	}
+
+	if snapshot.Status.Status == directpvtypes.SnapshotStatusPending {
+		return handler.create(ctx, snapshot)
+	}
+
+	return nil
+}
+
+func (handler *snapshotEventHandler) create(ctx context.Context, snapshot *types.Snapshot) error {
+	sourceDir := types.GetVolumeDir(snapshot.Status.FSUUID, snapshot.GetSourceVolume())
+	dataPath := types.GetSnapshotDir(snapshot.Status.FSUUID, snapshot.Name)
+
+	// Remove leftover of previous failed attempt if any.
+	if err := handler.removeAll(dataPath); err != nil {
+		return err
+	}
+
+	err := handler.reflink(ctx, sourceDir, dataPath)
+	if errors.Is(err, os.ErrNotExist) {
+		err = handler.handleMissingSource(ctx, snapshot, dataPath, err)
+	}
+
+	switch {
+	case err == nil:
+		snapshot.Status.DataPath = dataPath
+		snapshot.Status.Status = directpvtypes.SnapshotStatusReady
+		snapshot.Status.Error = ""
+	case errors.Is(err, xfs.ErrReflinkNotSupported):
+		klog.ErrorS(err, "unable to create snapshot", "snapshot", snapshot.Name, "sourceVolume", snapshot.GetSourceVolume())
+		if rmErr := handler.removeAll(dataPath); rmErr != nil {
+			klog.ErrorS(rmErr, "unable to remove partial snapshot", "snapshot", snapshot.Name, "DataPath", dataPath)
+		}
+		snapshot.Status.Status = directpvtypes.SnapshotStatusError
+		snapshot.Status.Error = fmt.Sprintf(
+			"drive %v does not support XFS reflink; snapshots are not supported on this drive",
+			snapshot.GetDriveID(),
+		)
+	default:
+		return fmt.Errorf("unable to create snapshot %v of volume %v; %w", snapshot.Name, snapshot.GetSourceVolume(), err)
+	}
+
+	if _, err = client.SnapshotClient().Update(ctx, snapshot, metav1.UpdateOptions{TypeMeta: types.NewSnapshotTypeMeta()}); err != nil {
+		return err
+	}
+
+	if snapshot.IsReady() {
+		client.Eventf(snapshot, client.EventTypeNormal, client.EventReasonSnapshotCreated, "snapshot is created from volume %v", snapshot.GetSourceVolume())
+	} else {
+		client.Eventf(snapshot, client.EventTypeWarning, client.EventReasonSnapshotError, "%v", snapshot.Status.Error)
+	}
+
+	return nil
+}
+
+// handleMissingSource creates empty snapshot if the source volume is never staged
+// i.e. the volume has no data; else returns sourceErr.
+func (handler *snapshotEventHandler) handleMissingSource(ctx context.Context, snapshot *types.Snapshot, dataPath string, sourceErr error) error {
+	volume, err := client.VolumeClient().Get(ctx, snapshot.GetSourceVolume(), metav1.GetOptions{})
+	switch {
+	case err != nil:
+		klog.ErrorS(err, "unable to get source volume", "snapshot", snapshot.Name, "sourceVolume", snapshot.GetSourceVolume())
+	case volume.Status.DataPath == "":
+		return handler.mkdirAll(dataPath, 0o755)
+	}
+
+	client.Eventf(
+		snapshot,
+		client.EventTypeWarning,
+		client.EventReasonSnapshotError,
+		"directory of source volume %v not found; %v",
+		snapshot.GetSourceVolume(),
+		sourceErr,
+	)
+	return sourceErr
+}
+
+func (handler *snapshotEventHandler) delete(ctx context.Context, snapshot *types.Snapshot) error {
+	dataPath := snapshot.Status.DataPath
+	if dataPath == "" {
+		dataPath = types.GetSnapshotDir(snapshot.Status.FSUUID, snapshot.Name)
+	}
+
+	if err := handler.removeAll(dataPath); err != nil {
+		klog.ErrorS(err, "unable to remove snapshot data path", "snapshot", snapshot.Name, "DataPath", dataPath)
+		return err
+	}
+
+	snapshot.RemovePurgeProtection()
+	_, err := client.SnapshotClient().Update(ctx, snapshot, metav1.UpdateOptions{TypeMeta: types.NewSnapshotTypeMeta()})
+	return err
+}
+
+// StartController starts snapshot controller.
+func StartController(ctx context.Context, nodeID directpvtypes.NodeID) {
+	ctrl := controller.New("snapshot", newSnapshotEventHandler(nodeID), workerThreads, resyncPeriod)
+	ctrl.Run(ctx)
+}
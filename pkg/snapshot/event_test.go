@@ -0,0 +1,165 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/controller"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/xfs"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func init() {
+	client.FakeInit()
+}
+
+func TestSnapshotEventHandlerHandle(t *testing.T) {
+	testCases := []struct {
+		reflinkErr     error
+		expectedStatus directpvtypes.SnapshotStatus
+		expectErr      bool
+	}{
+		{nil, directpvtypes.SnapshotStatusReady, false},
+		{xfs.ErrReflinkNotSupported, directpvtypes.SnapshotStatusError, false},
+		{errors.New("input/output error"), directpvtypes.SnapshotStatusPending, true},
+	}
+
+	ctx := context.TODO()
+	for i, testCase := range testCases {
+		snapshot := types.NewSnapshot("snapshot-1", "volume-1", "fsuuid1", "node-1", "drive-1", "sda", 1024)
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(snapshot))
+		client.SetSnapshotInterface(clientset.DirectpvLatest().DirectPVSnapshots())
+
+		var source, target string
+		handler := &snapshotEventHandler{
+			nodeID: "node-1",
+			reflink: func(_ context.Context, s, t string) error {
+				source, target = s, t
+				return testCase.reflinkErr
+			},
+			removeAll: func(_ string) error { return nil },
+		}
+
+		err := handler.Handle(ctx, controller.AddEvent, snapshot)
+		if testCase.expectErr != (err != nil) {
+			t.Fatalf("case %v: expected error: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if source != types.GetVolumeDir("fsuuid1", "volume-1") || target != types.GetSnapshotDir("fsuuid1", "snapshot-1") {
+			t.Fatalf("case %v: unexpected reflink source %v and target %v", i+1, source, target)
+		}
+
+		result, err := client.SnapshotClient().Get(ctx, "snapshot-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if result.Status.Status != testCase.expectedStatus {
+			t.Fatalf("case %v: expected status: %v, got: %v", i+1, testCase.expectedStatus, result.Status.Status)
+		}
+		if result.Status.Status == directpvtypes.SnapshotStatusError && result.Status.Error == "" {
+			t.Fatalf("case %v: expected error message in status", i+1)
+		}
+	}
+}
+
+func TestSnapshotEventHandlerMissingSource(t *testing.T) {
+	testCases := []struct {
+		dataPath       string
+		expectedStatus directpvtypes.SnapshotStatus
+		expectErr      bool
+	}{
+		{"", directpvtypes.SnapshotStatusReady, false},
+		{types.GetVolumeDir("fsuuid1", "volume-1"), directpvtypes.SnapshotStatusPending, true},
+	}
+
+	ctx := context.TODO()
+	for i, testCase := range testCases {
+		snapshot := types.NewSnapshot("snapshot-1", "volume-1", "fsuuid1", "node-1", "drive-1", "sda", 1024)
+		volume := types.NewVolume("volume-1", "fsuuid1", "node-1", "drive-1", "sda", 1024)
+		volume.Status.DataPath = testCase.dataPath
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(snapshot, volume))
+		client.SetSnapshotInterface(clientset.DirectpvLatest().DirectPVSnapshots())
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		var createdPath string
+		handler := &snapshotEventHandler{
+			nodeID:    "node-1",
+			reflink:   func(_ context.Context, _, _ string) error { return os.ErrNotExist },
+			removeAll: func(_ string) error { return nil },
+			mkdirAll:  func(path string, _ os.FileMode) error { createdPath = path; return nil },
+		}
+
+		err := handler.Handle(ctx, controller.AddEvent, snapshot)
+		if testCase.expectErr != (err != nil) {
+			t.Fatalf("case %v: expected error: %v, got: %v", i+1, testCase.expectErr, err)
+		}
+		if testCase.expectErr && createdPath != "" {
+			t.Fatalf("case %v: expected no snapshot directory, got: %v", i+1, createdPath)
+		}
+		if !testCase.expectErr && createdPath != types.GetSnapshotDir("fsuuid1", "snapshot-1") {
+			t.Fatalf("case %v: unexpected snapshot directory %v", i+1, createdPath)
+		}
+
+		result, err := client.SnapshotClient().Get(ctx, "snapshot-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if result.Status.Status != testCase.expectedStatus {
+			t.Fatalf("case %v: expected status: %v, got: %v", i+1, testCase.expectedStatus, result.Status.Status)
+		}
+	}
+}
+
+func TestSnapshotEventHandlerDelete(t *testing.T) {
+	snapshot := types.NewSnapshot("snapshot-1", "volume-1", "fsuuid1", "node-1", "drive-1", "sda", 1024)
+	snapshot.Status.DataPath = "/data/path"
+	now := metav1.Now()
+	snapshot.DeletionTimestamp = &now
+
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(snapshot))
+	client.SetSnapshotInterface(clientset.DirectpvLatest().DirectPVSnapshots())
+
+	var removedPath string
+	handler := &snapshotEventHandler{
+		nodeID:    "node-1",
+		reflink:   func(_ context.Context, _, _ string) error { return errors.New("reflink must not be called") },
+		removeAll: func(path string) error { removedPath = path; return nil },
+	}
+
+	ctx := context.TODO()
+	if err := handler.Handle(ctx, controller.DeleteEvent, snapshot); err != nil {
+		t.Fatal(err)
+	}
+	if removedPath != "/data/path" {
+		t.Fatalf("expected removed path: /data/path, got: %v", removedPath)
+	}
+
+	result, err := client.SnapshotClient().Get(ctx, "snapshot-1", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Finalizers) != 0 {
+		t.Fatalf("expected empty finalizers, got: %v", result.Finalizers)
+	}
+}
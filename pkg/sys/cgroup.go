@@ -0,0 +1,31 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sys
+
+// IOMax denotes cgroup v2 io.max limits of a device; zero value is unlimited.
+type IOMax struct {
+	ReadBPS   int64
+	WriteBPS  int64
+	ReadIOPS  int64
+	WriteIOPS int64
+}
+
+// SetIOMax sets io.max limits of the whole disk of the device on the cgroup of
+// the pod. MAJOR:MINOR of the disk the limits are set on is returned.
+func SetIOMax(podUID, device string, ioMax IOMax) (majorMinor string, err error) {
+	return setIOMax(podUID, device, ioMax)
+}
@@ -0,0 +1,114 @@
+//go:build linux
+
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sys
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const cgroupRootDir = "/sys/fs/cgroup"
+
+// getPodCgroupDir returns cgroup directory of the pod created by kubelet either
+// by systemd or cgroupfs cgroup driver in any QoS class.
+func getPodCgroupDir(podUID string) (string, error) {
+	systemdPodUID := strings.ReplaceAll(podUID, "-", "_")
+	dirs := []string{
+		"kubepods.slice/kubepods-pod" + systemdPodUID + ".slice",
+		"kubepods.slice/kubepods-burstable.slice/kubepods-burstable-pod" + systemdPodUID + ".slice",
+		"kubepods.slice/kubepods-besteffort.slice/kubepods-besteffort-pod" + systemdPodUID + ".slice",
+		"kubepods/pod" + podUID,
+		"kubepods/burstable/pod" + podUID,
+		"kubepods/besteffort/pod" + podUID,
+	}
+	for _, dir := range dirs {
+		dir = path.Join(cgroupRootDir, dir)
+		if _, err := os.Stat(path.Join(dir, "io.max")); err == nil {
+			return dir, nil
+		}
+	}
+
+	return "", fmt.Errorf("cgroup of pod %v not found; %w", podUID, os.ErrNotExist)
+}
+
+// getDiskMajorMinor returns MAJOR:MINOR of the device, or its parent disk if the device is a partition,
+// as io.max accepts whole disks only.
+func getDiskMajorMinor(device string) (string, error) {
+	sysDir, err := filepath.EvalSymlinks(path.Join("/sys/class/block", filepath.Base(device)))
+	if err != nil {
+		return "", err
+	}
+
+	if _, err = os.Stat(path.Join(sysDir, "partition")); err == nil {
+		sysDir = filepath.Dir(sysDir)
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	data, err := os.ReadFile(path.Join(sysDir, "dev"))
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func formatIOMax(majorMinor string, ioMax IOMax) string {
+	toString := func(value int64) string {
+		if value <= 0 {
+			return "max"
+		}
+		return strconv.FormatInt(value, 10)
+	}
+
+	return fmt.Sprintf(
+		"%v rbps=%v wbps=%v riops=%v wiops=%v",
+		majorMinor,
+		toString(ioMax.ReadBPS),
+		toString(ioMax.WriteBPS),
+		toString(ioMax.ReadIOPS),
+		toString(ioMax.WriteIOPS),
+	)
+}
+
+func setIOMax(podUID, device string, ioMax IOMax) (string, error) {
+	if _, err := os.Stat(path.Join(cgroupRootDir, "cgroup.controllers")); err != nil {
+		return "", fmt.Errorf("cgroup v2 is not available; %w", err)
+	}
+
+	cgroupDir, err := getPodCgroupDir(podUID)
+	if err != nil {
+		return "", err
+	}
+
+	majorMinor, err := getDiskMajorMinor(device)
+	if err != nil {
+		return "", fmt.Errorf("unable to get MAJOR:MINOR of device %v; %w", device, err)
+	}
+
+	if err = os.WriteFile(path.Join(cgroupDir, "io.max"), []byte(formatIOMax(majorMinor, ioMax)), 0o644); err != nil {
+		return "", err
+	}
+
+	return majorMinor, nil
+}
@@ -0,0 +1,48 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sys
+
+import "errors"
+
+// ErrLoopDeviceNotFound denotes no loop device is attached to the backing file.
+var ErrLoopDeviceNotFound = errors.New("loop device not found")
+
+// AllocateFile creates file if not exists and preallocates its blocks up to size.
+func AllocateFile(name string, size int64) error {
+	return allocateFile(name, size)
+}
+
+// GetLoopDevice returns loop device attached to the backing file.
+func GetLoopDevice(backingFile string) (device string, err error) {
+	return getLoopDevice(backingFile)
+}
+
+// AttachLoopDevice attaches the backing file to a free loop device. If the
+// backing file is already attached, its loop device is returned.
+func AttachLoopDevice(backingFile string) (device string, err error) {
+	return attachLoopDevice(backingFile)
+}
+
+// DetachLoopDevice detaches loop device of the backing file if attached.
+func DetachLoopDevice(backingFile string) error {
+	return detachLoopDevice(backingFile)
+}
+
+// ResizeLoopDevice updates the size of loop device to its backing file size.
+func ResizeLoopDevice(backingFile string) error {
+	return resizeLoopDevice(backingFile)
+}
@@ -0,0 +1,179 @@
+//go:build linux
+
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package sys
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"k8s.io/klog/v2"
+)
+
+// Refer https://man7.org/linux/man-pages/man4/loop.4.html
+const (
+	loopSetFD       = 0x4C00
+	loopClearFD     = 0x4C01
+	loopSetCapacity = 0x4C07
+	loopCtlGetFree  = 0x4C82
+
+	loopControlDevice = "/dev/loop-control"
+	maxLoopRetries    = 5
+)
+
+func ioctl(file *os.File, request, arg uintptr) error {
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), request, arg); errno != 0 {
+		return &os.PathError{Op: "ioctl", Path: file.Name(), Err: errno}
+	}
+	return nil
+}
+
+func allocateFile(name string, size int64) error {
+	file, err := os.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+	if info.Size() >= size {
+		return nil
+	}
+
+	if err := syscall.Fallocate(int(file.Fd()), 0, 0, size); err != nil {
+		return &os.PathError{Op: "fallocate", Path: name, Err: err}
+	}
+
+	return nil
+}
+
+func getLoopDevice(backingFile string) (string, error) {
+	backingFile = filepath.Clean(backingFile)
+	files, err := filepath.Glob("/sys/block/loop*/loop/backing_file")
+	if err != nil {
+		return "", err
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				continue // loop device detached in the meantime
+			}
+			return "", err
+		}
+
+		if strings.TrimSuffix(string(data), "\n") == backingFile {
+			return "/dev/" + path.Base(path.Dir(path.Dir(file))), nil
+		}
+	}
+
+	return "", ErrLoopDeviceNotFound
+}
+
+func attachLoopDevice(backingFile string) (device string, err error) {
+	device, err = getLoopDevice(backingFile)
+	if !errors.Is(err, ErrLoopDeviceNotFound) {
+		return device, err
+	}
+
+	file, err := os.OpenFile(backingFile, os.O_RDWR, 0)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	control, err := os.OpenFile(loopControlDevice, os.O_RDWR, 0)
+	if err != nil {
+		return "", err
+	}
+	defer control.Close()
+
+	for i := 0; i < maxLoopRetries; i++ {
+		index, _, errno := syscall.Syscall(syscall.SYS_IOCTL, control.Fd(), loopCtlGetFree, 0)
+		if errno != 0 {
+			return "", &os.PathError{Op: "ioctl", Path: loopControlDevice, Err: errno}
+		}
+
+		device = fmt.Sprintf("/dev/loop%d", index)
+		loop, err := os.OpenFile(device, os.O_RDWR, 0)
+		if err != nil {
+			return "", err
+		}
+		err = ioctl(loop, loopSetFD, file.Fd())
+		loop.Close()
+
+		switch {
+		case err == nil:
+			klog.V(5).InfoS("loop device attached", "device", device, "backingFile", backingFile)
+			return device, nil
+		case errors.Is(err, syscall.EBUSY):
+			// Loop device is taken by someone else in the meantime; retry with next free device.
+		default:
+			return "", err
+		}
+	}
+
+	return "", fmt.Errorf("unable to find free loop device for %v", backingFile)
+}
+
+func detachLoopDevice(backingFile string) error {
+	device, err := getLoopDevice(backingFile)
+	if err != nil {
+		if errors.Is(err, ErrLoopDeviceNotFound) {
+			return nil
+		}
+		return err
+	}
+
+	loop, err := os.OpenFile(device, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer loop.Close()
+
+	if err := ioctl(loop, loopClearFD, 0); err != nil && !errors.Is(err, syscall.ENXIO) {
+		return err
+	}
+
+	klog.V(5).InfoS("loop device detached", "device", device, "backingFile", backingFile)
+	return nil
+}
+
+func resizeLoopDevice(backingFile string) error {
+	device, err := getLoopDevice(backingFile)
+	if err != nil {
+		return err
+	}
+
+	loop, err := os.OpenFile(device, os.O_RDWR, 0)
+	if err != nil {
+		return err
+	}
+	defer loop.Close()
+
+	return ioctl(loop, loopSetCapacity, 0)
+}
@@ -44,9 +44,10 @@ func Mount(device, target, fsType string, flags []string, superBlockFlags string
 	return mount(device, target, fsType, flags, superBlockFlags)
 }
 
-// BindMount does bind-mount of source to target.
-func BindMount(source, target, fsType string, recursive, readOnly bool, superBlockFlags string) error {
-	return bindMount(source, target, fsType, recursive, readOnly, superBlockFlags)
+// BindMount does bind-mount of source to target. Read-only and flags are
+// applied by remounting the bind mount.
+func BindMount(source, target, fsType string, recursive, readOnly bool, flags []string, superBlockFlags string) error {
+	return bindMount(source, target, fsType, recursive, readOnly, flags, superBlockFlags)
 }
 
 // Unmount unmounts target with force, detach and expire options.
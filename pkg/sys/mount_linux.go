@@ -152,16 +152,41 @@ func mount(device, target, fsType string, flags []string, superBlockFlags string
 	return syscall.Mount(device, target, fsType, mountFlags, superBlockFlags)
 }
 
-func bindMount(source, target, fsType string, recursive, readOnly bool, superBlockFlags string) error {
-	flags := mountFlagMap["bind"]
+func bindMount(source, target, fsType string, recursive, readOnly bool, flags []string, superBlockFlags string) error {
+	bindFlags := mountFlagMap["bind"]
 	if recursive {
-		flags |= mountFlagMap["recursive"]
+		bindFlags |= mountFlagMap["recursive"]
 	}
+
+	// Per mount point flags are ignored on creating a bind mount; hence they are applied by remount.
+	remountFlags := uintptr(0)
 	if readOnly {
-		flags |= mountFlagMap["ro"]
+		remountFlags |= mountFlagMap["ro"]
+	}
+	for _, flag := range flags {
+		value, found := mountFlagMap[flag]
+		if !found {
+			return fmt.Errorf("unknown flag %v", flag)
+		}
+		remountFlags |= value
+	}
+
+	klog.V(5).InfoS("bind mounting directory", "source", source, "target", target, "fsType", fsType, "recursive", recursive, "readOnly", readOnly, "flags", flags, "superBlockFlags", superBlockFlags)
+	if err := syscall.Mount(source, target, fsType, bindFlags|remountFlags, superBlockFlags); err != nil {
+		return err
+	}
+
+	if remountFlags == 0 {
+		return nil
+	}
+
+	if err := syscall.Mount(source, target, fsType, bindFlags|mountFlagMap["remount"]|remountFlags, superBlockFlags); err != nil {
+		if uerr := syscall.Unmount(target, syscall.MNT_DETACH); uerr != nil {
+			klog.ErrorS(uerr, "unable to unmount bind mount", "target", target)
+		}
+		return fmt.Errorf("unable to remount %v with flags %v; %w", target, flags, err)
 	}
-	klog.V(5).InfoS("bind mounting directory", "source", source, "target", target, "fsType", fsType, "recursive", recursive, "readOnly", readOnly, "superBlockFlags", superBlockFlags)
-	return syscall.Mount(source, target, fsType, flags, superBlockFlags)
+	return nil
 }
 
 func unmount(target string, force, detach, expire bool) error {
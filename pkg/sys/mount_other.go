@@ -37,6 +37,6 @@ func unmount(target string, force, detach, expire bool) error {
 	return fmt.Errorf("unsupported operating system %v", runtime.GOOS)
 }
 
-func bindMount(source, target, fsType string, recursive, readOnly bool, superBlockFlags string) error {
+func bindMount(source, target, fsType string, recursive, readOnly bool, flags []string, superBlockFlags string) error {
 	return fmt.Errorf("unsupported operating system %v", runtime.GOOS)
 }
@@ -41,6 +41,10 @@ type (
 	LatestDriveInterface = typeddirectpv.DirectPVDriveInterface
 
 	VolumeStatus          = directpv.VolumeStatus
+	CloneStatus           = directpv.CloneStatus
+	MoveStatus            = directpv.MoveStatus
+	IOLimits              = directpv.IOLimits
+	VolumeOwnership       = directpv.VolumeOwnership
 	Volume                = directpv.DirectPVVolume
 	VolumeStatusList      = []directpv.DirectPVVolume
 	VolumeList            = directpv.DirectPVVolumeList
@@ -60,6 +64,11 @@ type (
 	InitRequestStatusList      = []directpv.DirectPVInitRequest
 	InitRequestList            = directpv.DirectPVInitRequestList
 	LatestInitRequestInterface = typeddirectpv.DirectPVInitRequestInterface
+
+	SnapshotStatus          = directpv.SnapshotStatus
+	Snapshot                = directpv.DirectPVSnapshot
+	SnapshotList            = directpv.DirectPVSnapshotList
+	LatestSnapshotInterface = typeddirectpv.DirectPVSnapshotInterface
 )
 
 var (
@@ -67,6 +76,7 @@ var (
 	NewVolume      = directpv.NewDirectPVVolume
 	NewNode        = directpv.NewDirectPVNode
 	NewInitRequest = directpv.NewDirectPVInitRequest
+	NewSnapshot    = directpv.NewDirectPVSnapshot
 )
 
 type ExtClientsetInterface interface {
@@ -56,6 +56,14 @@ func NewInitRequestTypeMeta() metav1.TypeMeta {
 	}
 }
 
+// NewSnapshotTypeMeta gets new snapshot CRD type meta.
+func NewSnapshotTypeMeta() metav1.TypeMeta {
+	return metav1.TypeMeta{
+		APIVersion: string(directpvtypes.LatestVersionLabelKey),
+		Kind:       consts.SnapshotKind,
+	}
+}
+
 // GetDriveMountDir returns drive mount directory.
 func GetDriveMountDir(fsuuid string) string {
 	return path.Join(consts.MountRootDir, fsuuid)
@@ -80,3 +88,13 @@ func GetVolumeRootDir(fsuuid string) string {
 func GetVolumeDir(fsuuid, volumeName string) string {
 	return path.Join(GetVolumeRootDir(fsuuid), volumeName)
 }
+
+// GetVolumeBlockFile returns backing file of raw block volume.
+func GetVolumeBlockFile(fsuuid, volumeName string) string {
+	return path.Join(GetVolumeDir(fsuuid, volumeName), "block.img")
+}
+
+// GetSnapshotDir returns snapshot directory.
+func GetSnapshotDir(fsuuid, snapshotName string) string {
+	return path.Join(GetVolumeRootDir(fsuuid), ".snapshots", snapshotName)
+}
@@ -46,7 +46,8 @@ type volumeEventHandler struct {
 	nodeID            directpvtypes.NodeID
 	unmount           func(target string) error
 	getDeviceByFSUUID func(fsuuid string) (string, error)
-	removeQuota       func(ctx context.Context, device, path, volumeName string) error
+	removeQuota       func(ctx context.Context, device, path string, projectID uint32) error
+	detachLoopDevice  func(backingFile string) error
 }
 
 func newVolumeEventHandler(nodeID directpvtypes.NodeID) *volumeEventHandler {
@@ -56,9 +57,10 @@ func newVolumeEventHandler(nodeID directpvtypes.NodeID) *volumeEventHandler {
 			return sys.Unmount(mountPoint, true, true, false)
 		},
 		getDeviceByFSUUID: sys.GetDeviceByFSUUID,
-		removeQuota: func(ctx context.Context, device, path, volumeName string) error {
-			return xfs.SetQuota(ctx, device, path, volumeName, xfs.Quota{}, true)
+		removeQuota: func(ctx context.Context, device, path string, projectID uint32) error {
+			return xfs.SetQuota(ctx, device, path, projectID, xfs.Quota{}, true)
 		},
+		detachLoopDevice: sys.DetachLoopDevice,
 	}
 }
 
@@ -99,9 +101,17 @@ func sync(ctx context.Context, volume *types.Volume) error {
 		}
 		return nil
 	}
-	driveName := drive.GetDriveName()
-	if volume.GetDriveName() != driveName {
+	updated := false
+	if driveName := drive.GetDriveName(); volume.GetDriveName() != driveName {
 		volume.SetDriveName(driveName)
+		updated = true
+	}
+	// Volume staged by older versions uses project ID derived from volume name.
+	if volume.GetProjectID() == 0 && volume.Status.DataPath != "" && !volume.IsBlock() {
+		volume.SetProjectID(xfs.GetLegacyProjectID(volume.Name))
+		updated = true
+	}
+	if updated {
 		_, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
 			TypeMeta: types.NewVolumeTypeMeta(),
 		})
@@ -114,12 +124,12 @@ func (handler *volumeEventHandler) delete(ctx context.Context, volume *types.Vol
 		return fmt.Errorf("volume %v must be released before cleaning up", volume.Name)
 	}
 
-	if volume.Status.TargetPath != "" {
-		if err := handler.unmount(volume.Status.TargetPath); err != nil {
+	for _, targetPath := range volume.GetTargetPaths() {
+		if err := handler.unmount(targetPath); err != nil {
 			if _, ok := err.(*os.PathError); !ok {
 				klog.ErrorS(err, "unable to unmount container path",
 					"volume", volume.Name,
-					"containerPath", volume.Status.TargetPath,
+					"containerPath", targetPath,
 				)
 				return err
 			}
@@ -137,6 +147,17 @@ func (handler *volumeEventHandler) delete(ctx context.Context, volume *types.Vol
 		}
 	}
 
+	if volume.IsBlock() {
+		blockFile := types.GetVolumeBlockFile(volume.Status.FSUUID, volume.Name)
+		if err := handler.detachLoopDevice(blockFile); err != nil {
+			klog.ErrorS(err, "unable to detach loop device",
+				"volume", volume.Name,
+				"blockFile", blockFile,
+			)
+			return err
+		}
+	}
+
 	deletedDir := volume.Status.DataPath + ".deleted"
 	if err := os.Rename(volume.Status.DataPath, deletedDir); err != nil && !errors.Is(err, os.ErrNotExist) {
 		// FIXME: Also handle input/output error
@@ -190,22 +211,25 @@ func (handler *volumeEventHandler) releaseVolume(ctx context.Context, volume *ty
 
 	found := drive.RemoveVolumeFinalizer(volume.Name)
 	if found {
-		if device, err := handler.getDeviceByFSUUID(volume.Status.FSUUID); err != nil {
-			klog.ErrorS(
-				err,
-				"unable to find device by FSUUID; "+
-					"either device is removed or run command "+
-					"`sudo udevadm control --reload-rules && sudo udevadm trigger`"+
-					"on the host to reload",
-				"FSUUID", volume.Status.FSUUID)
-			client.Eventf(
-				volume, client.EventTypeWarning, client.EventReasonStageVolume,
-				"unable to find device by FSUUID %v; "+
-					"either device is removed or run command "+
-					"`sudo udevadm control --reload-rules && sudo udevadm trigger`"+
-					" on the host to reload", volume.Status.FSUUID)
-		} else if err := handler.removeQuota(ctx, device, volume.Status.DataPath, volume.Name); err != nil {
-			klog.ErrorS(err, "unable to remove quota on volume data path", "DataPath", volume.Status.DataPath)
+		// Project ID is assigned only when quota is set.
+		if volume.GetProjectID() != 0 {
+			if device, err := handler.getDeviceByFSUUID(volume.Status.FSUUID); err != nil {
+				klog.ErrorS(
+					err,
+					"unable to find device by FSUUID; "+
+						"either device is removed or run command "+
+						"`sudo udevadm control --reload-rules && sudo udevadm trigger`"+
+						"on the host to reload",
+					"FSUUID", volume.Status.FSUUID)
+				client.Eventf(
+					volume, client.EventTypeWarning, client.EventReasonStageVolume,
+					"unable to find device by FSUUID %v; "+
+						"either device is removed or run command "+
+						"`sudo udevadm control --reload-rules && sudo udevadm trigger`"+
+						" on the host to reload", volume.Status.FSUUID)
+			} else if err := handler.removeQuota(ctx, device, volume.Status.DataPath, volume.GetProjectID()); err != nil {
+				klog.ErrorS(err, "unable to remove quota on volume data path", "DataPath", volume.Status.DataPath)
+			}
 		}
 
 		drive.Status.FreeCapacity += volume.Status.TotalCapacity
@@ -219,8 +243,10 @@ func (handler *volumeEventHandler) releaseVolume(ctx context.Context, volume *ty
 	return err
 }
 
-// StartController starts volume controller.
-func StartController(ctx context.Context, nodeID directpvtypes.NodeID) {
+// StartController starts volume controller and syncs volume usage on given interval.
+// Volume usage is checked against warning and critical thresholds in percentage on every sync.
+func StartController(ctx context.Context, nodeID directpvtypes.NodeID, usageSyncInterval time.Duration, warningThreshold, criticalThreshold int) {
+	go startUsageSync(ctx, nodeID, usageSyncInterval, warningThreshold, criticalThreshold)
 	ctrl := controller.New("volume", newVolumeEventHandler(nodeID), workerThreads, resyncPeriod)
 	ctrl.Run(ctx)
 }
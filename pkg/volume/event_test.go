@@ -41,7 +41,8 @@ func createFakeVolumeEventListener(nodeID directpvtypes.NodeID) *volumeEventHand
 		nodeID:            nodeID,
 		unmount:           func(_ string) error { return nil },
 		getDeviceByFSUUID: func(_ string) (string, error) { return "", nil },
-		removeQuota:       func(_ context.Context, _, _, _ string) error { return nil },
+		removeQuota:       func(_ context.Context, _, _ string, _ uint32) error { return nil },
+		detachLoopDevice:  func(_ string) error { return nil },
 	}
 }
 
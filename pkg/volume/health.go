@@ -0,0 +1,57 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package volume
+
+import (
+	"context"
+	"fmt"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/types"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetCondition returns whether the volume is abnormal and its message by the
+// volume conditions and the status of its drive.
+func GetCondition(ctx context.Context, volume *types.Volume) (abnormal bool, message string, err error) {
+	if volume.IsDriveLost() {
+		return true, string(directpvtypes.VolumeConditionMessageDriveLost), nil
+	}
+
+	drive, err := client.DriveClient().Get(ctx, string(volume.GetDriveID()), metav1.GetOptions{TypeMeta: types.NewDriveTypeMeta()})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return true, fmt.Sprintf("drive %v of the volume is not found", volume.GetDriveID()), nil
+		}
+		return false, "", err
+	}
+
+	switch drive.Status.Status {
+	case directpvtypes.DriveStatusLost:
+		return true, fmt.Sprintf("drive %v of the volume is lost", volume.GetDriveID()), nil
+	case directpvtypes.DriveStatusError:
+		message = fmt.Sprintf("drive %v of the volume is in error state", volume.GetDriveID())
+		if condition := drive.GetLatestErrorCondition(); condition != nil {
+			message = fmt.Sprintf("%v; %v", message, condition.Message)
+		}
+		return true, message, nil
+	}
+
+	return false, "", nil
+}
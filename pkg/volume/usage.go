@@ -0,0 +1,223 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package volume
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	"github.com/minio/directpv/pkg/k8s"
+	"github.com/minio/directpv/pkg/sys"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/xfs"
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stypes "k8s.io/apimachinery/pkg/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultUsageSyncInterval is the default interval to sync volume usage.
+	DefaultUsageSyncInterval = time.Minute
+
+	// DefaultUsageWarningThreshold is the default usage percentage to warn volume is nearly full.
+	DefaultUsageWarningThreshold = 80
+
+	// DefaultUsageCriticalThreshold is the default usage percentage to warn volume is critically full.
+	DefaultUsageCriticalThreshold = 95
+
+	usageUpdateRate  = 5
+	usageUpdateBurst = 10
+)
+
+// Usage levels ordered by severity.
+var usageLevels = map[directpvtypes.VolumeConditionReason]int{
+	directpvtypes.VolumeConditionReasonUsageBelowThreshold:         0,
+	directpvtypes.VolumeConditionReasonUsageAboveWarningThreshold:  1,
+	directpvtypes.VolumeConditionReasonUsageAboveCriticalThreshold: 2,
+}
+
+type usageSyncer struct {
+	nodeID            directpvtypes.NodeID
+	warningThreshold  int64
+	criticalThreshold int64
+	getDeviceByFSUUID func(fsuuid string) (string, error)
+	getQuota          func(ctx context.Context, device string, projectID uint32) (quota *xfs.Quota, err error)
+	limiter           *rate.Limiter
+}
+
+func newUsageSyncer(nodeID directpvtypes.NodeID, warningThreshold, criticalThreshold int) *usageSyncer {
+	return &usageSyncer{
+		nodeID:            nodeID,
+		warningThreshold:  int64(warningThreshold),
+		criticalThreshold: int64(criticalThreshold),
+		getDeviceByFSUUID: sys.GetDeviceByFSUUID,
+		getQuota:          xfs.GetQuota,
+		limiter:           rate.NewLimiter(rate.Limit(usageUpdateRate), usageUpdateBurst),
+	}
+}
+
+// getUsageReason returns nearly full condition reason and message for the usage.
+// Zero threshold is disabled.
+func (syncer *usageSyncer) getUsageReason(usedCapacity, totalCapacity int64) (directpvtypes.VolumeConditionReason, string) {
+	if totalCapacity <= 0 {
+		return directpvtypes.VolumeConditionReasonUsageBelowThreshold, ""
+	}
+
+	percent := usedCapacity * 100 / totalCapacity
+	switch {
+	case syncer.criticalThreshold > 0 && percent >= syncer.criticalThreshold:
+		return directpvtypes.VolumeConditionReasonUsageAboveCriticalThreshold,
+			fmt.Sprintf("volume usage %v%% is above critical threshold %v%%", percent, syncer.criticalThreshold)
+	case syncer.warningThreshold > 0 && percent >= syncer.warningThreshold:
+		return directpvtypes.VolumeConditionReasonUsageAboveWarningThreshold,
+			fmt.Sprintf("volume usage %v%% is above warning threshold %v%%", percent, syncer.warningThreshold)
+	default:
+		return directpvtypes.VolumeConditionReasonUsageBelowThreshold,
+			fmt.Sprintf("volume usage %v%% is below thresholds", percent)
+	}
+}
+
+// syncVolume updates used and available capacity of the volume from its project quota
+// and sets nearly full condition on crossing usage thresholds.
+func (syncer *usageSyncer) syncVolume(ctx context.Context, volume *types.Volume) error {
+	// Usage of raw block volume is fixed to its preallocated capacity.
+	if !volume.IsStaged() || volume.IsBlock() || volume.GetProjectID() == 0 {
+		return nil
+	}
+
+	device, err := syncer.getDeviceByFSUUID(volume.Status.FSUUID)
+	if err != nil {
+		return fmt.Errorf("unable to find device by FSUUID %v; %w", volume.Status.FSUUID, err)
+	}
+
+	// Soft limit of the quota is kept at capacity as XFS enforces it after grace period;
+	// hence usage thresholds are evaluated on current usage.
+	quota, err := syncer.getQuota(ctx, device, volume.GetProjectID())
+	if err != nil {
+		return fmt.Errorf("unable to get quota; %w", err)
+	}
+
+	usedCapacity := int64(quota.CurrentSpace)
+	availableCapacity := volume.Status.TotalCapacity - usedCapacity
+	if availableCapacity < 0 {
+		availableCapacity = 0
+	}
+	usageChanged := volume.Status.UsedCapacity != usedCapacity || volume.Status.AvailableCapacity != availableCapacity
+
+	reason, message := syncer.getUsageReason(usedCapacity, volume.Status.TotalCapacity)
+	currentReason := directpvtypes.VolumeConditionReasonUsageBelowThreshold
+	if condition := volume.GetNearlyFullCondition(); condition != nil {
+		currentReason = directpvtypes.VolumeConditionReason(condition.Reason)
+	}
+	conditionChanged := reason != currentReason
+
+	if !usageChanged && !conditionChanged {
+		return nil
+	}
+
+	if err = syncer.limiter.Wait(ctx); err != nil {
+		return err
+	}
+
+	if !conditionChanged {
+		patch := fmt.Sprintf(`{"status":{"usedCapacity":%v,"availableCapacity":%v}}`, usedCapacity, availableCapacity)
+		_, err = client.VolumeClient().Patch(
+			ctx, volume.Name, k8stypes.MergePatchType, []byte(patch), metav1.PatchOptions{},
+		)
+		return err
+	}
+
+	volume.Status.UsedCapacity = usedCapacity
+	volume.Status.AvailableCapacity = availableCapacity
+	volume.SetNearlyFull(reason, message)
+	if _, err = client.VolumeClient().Update(ctx, volume, metav1.UpdateOptions{
+		TypeMeta: types.NewVolumeTypeMeta(),
+	}); err != nil {
+		return err
+	}
+
+	eventType, eventReason := client.EventTypeWarning, client.EventReasonVolumeNearlyFull
+	if usageLevels[reason] < usageLevels[currentReason] {
+		eventType, eventReason = client.EventTypeNormal, client.EventReasonVolumeUsageNormal
+	}
+	client.Eventf(volume, eventType, eventReason, "%v", message)
+	postPVCEvent(ctx, volume.Name, eventType, eventReason, message)
+	return nil
+}
+
+// postPVCEvent raises event on the PVC bound to the volume.
+func postPVCEvent(ctx context.Context, volumeName string, eventType client.EventType, reason client.EventReason, message string) {
+	pv, err := k8s.KubeClient().CoreV1().PersistentVolumes().Get(ctx, volumeName, metav1.GetOptions{})
+	if err != nil {
+		klog.ErrorS(err, "unable to get persistent volume", "volume", volumeName)
+		return
+	}
+	if pv.Spec.ClaimRef == nil {
+		return
+	}
+
+	pvc, err := k8s.KubeClient().CoreV1().PersistentVolumeClaims(pv.Spec.ClaimRef.Namespace).Get(
+		ctx, pv.Spec.ClaimRef.Name, metav1.GetOptions{},
+	)
+	if err != nil {
+		klog.ErrorS(err, "unable to get PVC", "namespace", pv.Spec.ClaimRef.Namespace, "name", pv.Spec.ClaimRef.Name, "volume", volumeName)
+		return
+	}
+
+	client.Eventf(pvc, eventType, reason, "%v", message)
+}
+
+// sync updates usage of all staged volumes on this node.
+func (syncer *usageSyncer) sync(ctx context.Context) {
+	resultCh := client.NewVolumeLister().
+		NodeSelector([]directpvtypes.LabelValue{directpvtypes.ToLabelValue(string(syncer.nodeID))}).
+		List(ctx)
+	for result := range resultCh {
+		if result.Err != nil {
+			klog.ErrorS(result.Err, "unable to list volumes", "node", syncer.nodeID)
+			return
+		}
+
+		if err := syncer.syncVolume(ctx, &result.Volume); err != nil {
+			klog.ErrorS(err, "unable to sync volume usage", "volume", result.Volume.Name)
+		}
+	}
+}
+
+func startUsageSync(ctx context.Context, nodeID directpvtypes.NodeID, interval time.Duration, warningThreshold, criticalThreshold int) {
+	if interval <= 0 {
+		klog.V(3).InfoS("volume usage sync is disabled")
+		return
+	}
+
+	syncer := newUsageSyncer(nodeID, warningThreshold, criticalThreshold)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			syncer.sync(ctx)
+		}
+	}
+}
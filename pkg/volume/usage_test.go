@@ -0,0 +1,154 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package volume
+
+import (
+	"context"
+	"testing"
+
+	directpvtypes "github.com/minio/directpv/pkg/apis/directpv.min.io/types"
+	"github.com/minio/directpv/pkg/client"
+	clientsetfake "github.com/minio/directpv/pkg/clientset/fake"
+	"github.com/minio/directpv/pkg/types"
+	"github.com/minio/directpv/pkg/xfs"
+	"golang.org/x/time/rate"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestUsageSyncerSyncVolume(t *testing.T) {
+	newVolume := func(staged bool, usedCapacity int64) *types.Volume {
+		volume := types.NewVolume("volume-1", "fsuuid1", "node-1", "drive-1", "sda", 100*MiB)
+		if staged {
+			volume.Status.StagingTargetPath = "/path/to/staging"
+			volume.SetProjectID(1)
+		}
+		volume.Status.UsedCapacity = usedCapacity
+		volume.Status.AvailableCapacity = volume.Status.TotalCapacity - usedCapacity
+		return volume
+	}
+
+	testCases := []struct {
+		volume        *types.Volume
+		currentSpace  uint64
+		expectedUsed  int64
+		expectedPatch bool
+	}{
+		{newVolume(true, 0), 10 * MiB, 10 * MiB, true},
+		{newVolume(true, 10*MiB), 10 * MiB, 10 * MiB, false},
+		{newVolume(false, 0), 10 * MiB, 0, false},
+	}
+
+	for i, testCase := range testCases {
+		clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(testCase.volume))
+		client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+		syncer := &usageSyncer{
+			nodeID:            "node-1",
+			getDeviceByFSUUID: func(_ string) (string, error) { return "sda", nil },
+			getQuota: func(_ context.Context, _ string, _ uint32) (*xfs.Quota, error) {
+				return &xfs.Quota{CurrentSpace: testCase.currentSpace}, nil
+			},
+			limiter: rate.NewLimiter(rate.Inf, 1),
+		}
+
+		if err := syncer.syncVolume(context.TODO(), testCase.volume); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+
+		patched := false
+		for _, action := range clientset.Actions() {
+			if _, ok := action.(k8stesting.PatchAction); ok {
+				patched = true
+			}
+		}
+		if patched != testCase.expectedPatch {
+			t.Fatalf("case %v: patch: expected: %v, got: %v", i+1, testCase.expectedPatch, patched)
+		}
+
+		volume, err := client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if volume.Status.UsedCapacity != testCase.expectedUsed {
+			t.Fatalf("case %v: used capacity: expected: %v, got: %v", i+1, testCase.expectedUsed, volume.Status.UsedCapacity)
+		}
+		if volume.Status.AvailableCapacity != volume.Status.TotalCapacity-testCase.expectedUsed {
+			t.Fatalf("case %v: available capacity: expected: %v, got: %v", i+1, volume.Status.TotalCapacity-testCase.expectedUsed, volume.Status.AvailableCapacity)
+		}
+	}
+}
+
+func TestUsageSyncerThresholds(t *testing.T) {
+	volume := types.NewVolume("volume-1", "fsuuid1", "node-1", "drive-1", "sda", 100*MiB)
+	volume.Status.StagingTargetPath = "/path/to/staging"
+	volume.SetProjectID(1)
+	clientset := types.NewExtFakeClientset(clientsetfake.NewSimpleClientset(volume))
+	client.SetVolumeInterface(clientset.DirectpvLatest().DirectPVVolumes())
+
+	var currentSpace uint64
+	syncer := &usageSyncer{
+		nodeID:            "node-1",
+		warningThreshold:  80,
+		criticalThreshold: 95,
+		getDeviceByFSUUID: func(_ string) (string, error) { return "sda", nil },
+		getQuota: func(_ context.Context, _ string, _ uint32) (*xfs.Quota, error) {
+			return &xfs.Quota{CurrentSpace: currentSpace}, nil
+		},
+		limiter: rate.NewLimiter(rate.Inf, 1),
+	}
+
+	testCases := []struct {
+		currentSpace       uint64
+		expectedReason     directpvtypes.VolumeConditionReason
+		expectedNearlyFull bool
+	}{
+		{10 * MiB, "", false},
+		{85 * MiB, directpvtypes.VolumeConditionReasonUsageAboveWarningThreshold, true},
+		{96 * MiB, directpvtypes.VolumeConditionReasonUsageAboveCriticalThreshold, true},
+		{90 * MiB, directpvtypes.VolumeConditionReasonUsageAboveWarningThreshold, true},
+		{10 * MiB, directpvtypes.VolumeConditionReasonUsageBelowThreshold, false},
+	}
+
+	for i, testCase := range testCases {
+		currentSpace = testCase.currentSpace
+		volume, err := client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		if err := syncer.syncVolume(context.TODO(), volume); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+
+		if volume, err = client.VolumeClient().Get(context.TODO(), "volume-1", metav1.GetOptions{}); err != nil {
+			t.Fatalf("case %v: unexpected error %v", i+1, err)
+		}
+		var reason directpvtypes.VolumeConditionReason
+		if condition := volume.GetNearlyFullCondition(); condition != nil {
+			reason = directpvtypes.VolumeConditionReason(condition.Reason)
+		}
+		if reason != testCase.expectedReason {
+			t.Fatalf("case %v: reason: expected: %v, got: %v", i+1, testCase.expectedReason, reason)
+		}
+		if volume.IsNearlyFull() != testCase.expectedNearlyFull {
+			t.Fatalf("case %v: nearly full: expected: %v, got: %v", i+1, testCase.expectedNearlyFull, volume.IsNearlyFull())
+		}
+		if volume.Status.UsedCapacity != int64(testCase.currentSpace) {
+			t.Fatalf("case %v: used capacity: expected: %v, got: %v", i+1, testCase.currentSpace, volume.Status.UsedCapacity)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package xfs
+
+import (
+	"context"
+	"fmt"
+)
+
+// ProgressFunc is called with number of bytes copied so far and total bytes to copy.
+type ProgressFunc func(copiedBytes, totalBytes int64)
+
+// Copy copies source directory tree into target directory preserving
+// ownership, permissions, extended attributes, hard links and holes of sparse
+// files. If reflink is set, data blocks are shared whenever the filesystem
+// supports it, otherwise data is copied.
+func Copy(ctx context.Context, source, target string, reflink bool, progress ProgressFunc) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- copyDir(ctx, source, target, reflink, progress)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w; %v", ErrCanceled, ctx.Err())
+	case err := <-errCh:
+		return err
+	}
+}
+
+// Verify checks whether target directory tree is a copy of source directory tree.
+func Verify(ctx context.Context, source, target string) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- verifyDir(ctx, source, target)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w; %v", ErrCanceled, ctx.Err())
+	case err := <-errCh:
+		return err
+	}
+}
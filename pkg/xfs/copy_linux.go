@@ -0,0 +1,454 @@
+//go:build linux
+
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package xfs
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Refer https://man7.org/linux/man-pages/man2/lseek.2.html
+const (
+	seekData = 3
+	seekHole = 4
+)
+
+type inode struct {
+	dev uint64
+	ino uint64
+}
+
+// getHardLinkInode returns inode of the file if it has more than one hard link.
+func getHardLinkInode(info fs.FileInfo) (inode, bool) {
+	stat, ok := info.Sys().(*syscall.Stat_t)
+	if !ok || stat.Nlink < 2 {
+		return inode{}, false
+	}
+	return inode{dev: uint64(stat.Dev), ino: stat.Ino}, true
+}
+
+func listXattrs(path string) ([]string, error) {
+	size, err := syscall.Listxattr(path, nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	buf := make([]byte, size)
+	if size, err = syscall.Listxattr(path, buf); err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, name := range strings.Split(string(buf[:size]), "\x00") {
+		if name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+func getXattr(path, name string) ([]byte, error) {
+	size, err := syscall.Getxattr(path, name, nil)
+	if err != nil || size == 0 {
+		return nil, err
+	}
+	value := make([]byte, size)
+	if size, err = syscall.Getxattr(path, name, value); err != nil {
+		return nil, err
+	}
+	return value[:size], nil
+}
+
+func copyXattrs(source, target string) error {
+	names, err := listXattrs(source)
+	if err != nil {
+		if errors.Is(err, syscall.ENOTSUP) {
+			return nil
+		}
+		return &os.PathError{Op: "listxattr", Path: source, Err: err}
+	}
+
+	for _, name := range names {
+		value, err := getXattr(source, name)
+		if err != nil {
+			return &os.PathError{Op: "getxattr", Path: source, Err: err}
+		}
+		if err := syscall.Setxattr(target, name, value, 0); err != nil {
+			return &os.PathError{Op: "setxattr", Path: target, Err: err}
+		}
+	}
+
+	return nil
+}
+
+func copyMetadata(source, path string, info fs.FileInfo) error {
+	if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+		if err := os.Lchown(path, int(stat.Uid), int(stat.Gid)); err != nil {
+			return err
+		}
+	}
+
+	if info.Mode()&os.ModeSymlink != 0 {
+		return nil
+	}
+
+	if err := os.Chmod(path, info.Mode()); err != nil {
+		return err
+	}
+
+	// Extended attributes are copied after chown as it clears security.capability.
+	if err := copyXattrs(source, path); err != nil {
+		return err
+	}
+
+	return os.Chtimes(path, time.Now(), info.ModTime())
+}
+
+// copyTree walks source directory and recreates it in target directory by
+// calling copyFile for regular files. Hard links are recreated in target
+// directory. Existing target directory is reused. Walking is stopped if ctx is
+// canceled.
+func copyTree(ctx context.Context, source, target string, copyFile func(source, target string, info fs.FileInfo) error) error {
+	type dirEntry struct {
+		source string
+		path   string
+		info   fs.FileInfo
+	}
+	var dirs []dirEntry
+	links := map[inode]string{}
+
+	err := filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%w; %v", ErrCanceled, err)
+		}
+
+		relPath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(target, relPath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.IsDir():
+			if err := os.Mkdir(targetPath, 0o700); err != nil && (relPath != "." || !errors.Is(err, os.ErrExist)) {
+				return err
+			}
+			// Directory metadata is applied after its content is copied.
+			dirs = append(dirs, dirEntry{source: path, path: targetPath, info: info})
+			return nil
+		case info.Mode().IsRegular():
+			ino, isLink := getHardLinkInode(info)
+			if isLink {
+				if linkPath, found := links[ino]; found {
+					return os.Link(linkPath, targetPath)
+				}
+				links[ino] = targetPath
+			}
+			if err := copyFile(path, targetPath, info); err != nil {
+				return err
+			}
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			if err := os.Symlink(link, targetPath); err != nil {
+				return err
+			}
+		default:
+			// Skip device, socket and pipe files.
+			return nil
+		}
+
+		return copyMetadata(path, targetPath, info)
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		if err := copyMetadata(dirs[i].source, dirs[i].path, dirs[i].info); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func getDirSize(dir string) (size int64, err error) {
+	links := map[inode]struct{}{}
+	err = filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.Type().IsRegular() {
+			info, err := entry.Info()
+			if err != nil {
+				return err
+			}
+			if ino, isLink := getHardLinkInode(info); isLink {
+				if _, found := links[ino]; found {
+					return nil
+				}
+				links[ino] = struct{}{}
+			}
+			size += info.Size()
+		}
+		return nil
+	})
+	return size, err
+}
+
+// copySparseData copies data segments of source file to target file by
+// skipping holes.
+func copySparseData(src, dst *os.File, size int64) error {
+	var offset int64
+	for offset < size {
+		dataOffset, err := src.Seek(offset, seekData)
+		if err != nil {
+			if errors.Is(err, syscall.ENXIO) {
+				break // No more data till end of file.
+			}
+			return err
+		}
+
+		holeOffset, err := src.Seek(dataOffset, seekHole)
+		if err != nil {
+			return err
+		}
+
+		if _, err := src.Seek(dataOffset, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := dst.Seek(dataOffset, io.SeekStart); err != nil {
+			return err
+		}
+		if _, err := io.CopyN(dst, src, holeOffset-dataOffset); err != nil {
+			return err
+		}
+
+		offset = holeOffset
+	}
+
+	return dst.Truncate(size)
+}
+
+func copyFileData(source, target string, mode os.FileMode) (int64, error) {
+	src, err := os.Open(source)
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	info, err := src.Stat()
+	if err != nil {
+		return 0, err
+	}
+
+	dst, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+
+	err = copySparseData(src, dst, info.Size())
+	if errors.Is(err, syscall.EINVAL) {
+		// Filesystem does not support seeking data and holes; copy whole file.
+		if _, err = src.Seek(0, io.SeekStart); err == nil {
+			if _, err = dst.Seek(0, io.SeekStart); err == nil {
+				_, err = io.Copy(dst, src)
+			}
+		}
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), dst.Sync()
+}
+
+func copyDir(ctx context.Context, source, target string, reflink bool, progress ProgressFunc) error {
+	if _, err := os.Stat(source); err != nil {
+		return err
+	}
+
+	totalBytes, err := getDirSize(source)
+	if err != nil {
+		return err
+	}
+
+	if progress == nil {
+		progress = func(_, _ int64) {}
+	}
+
+	var copiedBytes int64
+	progress(copiedBytes, totalBytes)
+	err = copyTree(ctx, source, target, func(source, target string, info fs.FileInfo) error {
+		if reflink {
+			err := cloneFile(source, target, info.Mode())
+			switch {
+			case err == nil:
+				copiedBytes += info.Size()
+				progress(copiedBytes, totalBytes)
+				return nil
+			case !errors.Is(err, ErrReflinkNotSupported):
+				return err
+			}
+
+			// Fallback to data copy for rest of the files.
+			reflink = false
+			if err := os.Remove(target); err != nil {
+				return err
+			}
+		}
+
+		n, err := copyFileData(source, target, info.Mode())
+		copiedBytes += n
+		progress(copiedBytes, totalBytes)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	progress(totalBytes, totalBytes)
+	return nil
+}
+
+func compareFileData(source, target string) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Open(target)
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	srcBuf := make([]byte, 1024*1024)
+	dstBuf := make([]byte, len(srcBuf))
+	for {
+		n, srcErr := io.ReadFull(src, srcBuf)
+		m, dstErr := io.ReadFull(dst, dstBuf)
+		if n != m || !bytes.Equal(srcBuf[:n], dstBuf[:m]) {
+			return fmt.Errorf("content of %v differs from %v", target, source)
+		}
+
+		switch {
+		case errors.Is(srcErr, io.EOF), errors.Is(srcErr, io.ErrUnexpectedEOF):
+			if errors.Is(dstErr, io.EOF) || errors.Is(dstErr, io.ErrUnexpectedEOF) {
+				return nil
+			}
+			return fmt.Errorf("content of %v differs from %v", target, source)
+		case srcErr != nil:
+			return srcErr
+		case dstErr != nil:
+			return dstErr
+		}
+	}
+}
+
+// verifyDir checks whether the target directory tree has the same files,
+// ownership, permissions and content of source directory tree. Walking is
+// stopped if ctx is canceled.
+func verifyDir(ctx context.Context, source, target string) error {
+	return filepath.WalkDir(source, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return fmt.Errorf("%w; %v", ErrCanceled, err)
+		}
+
+		relPath, err := filepath.Rel(source, path)
+		if err != nil {
+			return err
+		}
+		targetPath := filepath.Join(target, relPath)
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() && !info.Mode().IsRegular() && info.Mode()&os.ModeSymlink == 0 {
+			return nil // Device, socket and pipe files are not copied.
+		}
+
+		targetInfo, err := os.Lstat(targetPath)
+		if err != nil {
+			return err
+		}
+
+		if info.Mode() != targetInfo.Mode() {
+			return fmt.Errorf("mode %v of %v differs from %v", targetInfo.Mode(), targetPath, info.Mode())
+		}
+
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok {
+			if targetStat, ok := targetInfo.Sys().(*syscall.Stat_t); ok {
+				if stat.Uid != targetStat.Uid || stat.Gid != targetStat.Gid {
+					return fmt.Errorf("ownership %v:%v of %v differs from %v:%v",
+						targetStat.Uid, targetStat.Gid, targetPath, stat.Uid, stat.Gid)
+				}
+			}
+		}
+
+		switch {
+		case info.Mode().IsRegular():
+			if info.Size() != targetInfo.Size() {
+				return fmt.Errorf("size %v of %v differs from %v", targetInfo.Size(), targetPath, info.Size())
+			}
+			return compareFileData(path, targetPath)
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			targetLink, err := os.Readlink(targetPath)
+			if err != nil {
+				return err
+			}
+			if link != targetLink {
+				return fmt.Errorf("link %v of %v differs from %v", targetLink, targetPath, link)
+			}
+		}
+
+		return nil
+	})
+}
@@ -0,0 +1,177 @@
+//go:build linux
+
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package xfs
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestCopy(t *testing.T) {
+	source := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(source, "dir1", "dir2"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	files := map[string]string{
+		"file1":                 "hello",
+		"dir1/file2":            "world",
+		"dir1/dir2/empty-file3": "",
+	}
+	for name, data := range files {
+		if err := os.WriteFile(filepath.Join(source, name), []byte(data), 0o640); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink("file1", filepath.Join(source, "link1")); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, reflink := range []bool{false, true} {
+		target := filepath.Join(t.TempDir(), "target")
+		if err := os.Mkdir(target, 0o755); err != nil {
+			t.Fatal(err)
+		}
+
+		var copiedBytes, totalBytes int64
+		err := Copy(context.Background(), source, target, reflink, func(copied, total int64) {
+			copiedBytes, totalBytes = copied, total
+		})
+		if err != nil {
+			t.Fatalf("reflink %v: unexpected error %v", reflink, err)
+		}
+		if copiedBytes != 10 || totalBytes != 10 {
+			t.Fatalf("reflink %v: expected: 10/10 bytes, got: %v/%v", reflink, copiedBytes, totalBytes)
+		}
+
+		for name, data := range files {
+			result, err := os.ReadFile(filepath.Join(target, name))
+			if err != nil {
+				t.Fatalf("reflink %v: unexpected error %v", reflink, err)
+			}
+			if string(result) != data {
+				t.Fatalf("reflink %v: file %v: expected: %v, got: %v", reflink, name, data, string(result))
+			}
+			info, err := os.Stat(filepath.Join(target, name))
+			if err != nil {
+				t.Fatalf("reflink %v: unexpected error %v", reflink, err)
+			}
+			if info.Mode().Perm() != 0o640 {
+				t.Fatalf("reflink %v: file %v: expected mode: 0640, got: %v", reflink, name, info.Mode().Perm())
+			}
+		}
+
+		link, err := os.Readlink(filepath.Join(target, "link1"))
+		if err != nil || link != "file1" {
+			t.Fatalf("reflink %v: expected link: file1, got: %v; %v", reflink, link, err)
+		}
+	}
+}
+
+func TestCopyLinksAndSparseFiles(t *testing.T) {
+	source := t.TempDir()
+	if err := os.WriteFile(filepath.Join(source, "file1"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Link(filepath.Join(source, "file1"), filepath.Join(source, "link1")); err != nil {
+		t.Fatal(err)
+	}
+
+	sparseFile, err := os.Create(filepath.Join(source, "sparse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := sparseFile.WriteAt([]byte("world"), 4*1024*1024); err != nil {
+		t.Fatal(err)
+	}
+	sparseFile.Close()
+
+	xattrSupported := syscall.Setxattr(filepath.Join(source, "file1"), "user.directpv", []byte("value"), 0) == nil
+
+	target := t.TempDir()
+	if err := Copy(context.Background(), source, target, false, nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	info1, err := os.Stat(filepath.Join(target, "file1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	info2, err := os.Stat(filepath.Join(target, "link1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !os.SameFile(info1, info2) {
+		t.Fatalf("expected hard link of file1")
+	}
+
+	info, err := os.Stat(filepath.Join(target, "sparse"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 4*1024*1024+5 {
+		t.Fatalf("expected size: %v, got: %v", 4*1024*1024+5, info.Size())
+	}
+	if blocks := info.Sys().(*syscall.Stat_t).Blocks * 512; blocks >= info.Size() {
+		t.Fatalf("expected sparse file; allocated: %v, size: %v", blocks, info.Size())
+	}
+
+	if xattrSupported {
+		value, err := getXattr(filepath.Join(target, "file1"), "user.directpv")
+		if err != nil || string(value) != "value" {
+			t.Fatalf("expected xattr value: value, got: %v; %v", string(value), err)
+		}
+	}
+
+	if err := Verify(context.Background(), source, target); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(target, "file1"), []byte("HELLO"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := Verify(context.Background(), source, target); err == nil {
+		t.Fatalf("expected error for modified content")
+	}
+}
+
+func TestCopyCanceled(t *testing.T) {
+	source := t.TempDir()
+	if err := os.WriteFile(filepath.Join(source, "file1"), []byte("hello"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	target := t.TempDir()
+	if err := copyDir(ctx, source, target, false, nil); !errors.Is(err, ErrCanceled) {
+		t.Fatalf("expected error: %v, got: %v", ErrCanceled, err)
+	}
+	if _, err := os.Stat(filepath.Join(target, "file1")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected file1 not copied; %v", err)
+	}
+
+	if err := verifyDir(ctx, source, source); !errors.Is(err, ErrCanceled) {
+		t.Fatalf("expected error: %v, got: %v", ErrCanceled, err)
+	}
+}
@@ -0,0 +1,33 @@
+//go:build !linux
+
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package xfs
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+func copyDir(_ context.Context, source, target string, reflink bool, progress ProgressFunc) error {
+	return fmt.Errorf("unsupported operating system %v", runtime.GOOS)
+}
+
+func verifyDir(_ context.Context, source, target string) error {
+	return fmt.Errorf("unsupported operating system %v", runtime.GOOS)
+}
@@ -21,7 +21,7 @@ func Mount(device, target string) error {
 	return mount(device, target)
 }
 
-// BindMount bind-mounts source to target.
-func BindMount(source, target string, readOnly bool) error {
-	return bindMount(source, target, readOnly)
+// BindMount bind-mounts source to target with optional extra mount flags.
+func BindMount(source, target string, readOnly bool, flags []string) error {
+	return bindMount(source, target, readOnly, flags)
 }
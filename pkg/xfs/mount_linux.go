@@ -69,6 +69,6 @@ func mount(device, target string) error {
 	return nil
 }
 
-func bindMount(source, target string, readOnly bool) error {
-	return sys.BindMount(source, target, "xfs", false, readOnly, "prjquota")
+func bindMount(source, target string, readOnly bool, flags []string) error {
+	return sys.BindMount(source, target, "xfs", false, readOnly, flags, "prjquota")
 }
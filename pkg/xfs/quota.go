@@ -18,8 +18,11 @@ package xfs
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+
+	sha256 "github.com/minio/sha256-simd"
 )
 
 // ErrCanceled denotes canceled by context error.
@@ -27,16 +30,30 @@ var ErrCanceled = errors.New("canceled by context")
 
 // Quota denotes XFS quota information.
 type Quota struct {
-	HardLimit    uint64
-	SoftLimit    uint64
-	CurrentSpace uint64
+	HardLimit      uint64
+	SoftLimit      uint64
+	CurrentSpace   uint64
+	InodeHardLimit uint64
+	InodeSoftLimit uint64
+	CurrentInodes  uint64
+}
+
+// GetLegacyProjectID returns XFS project ID derived from volume ID by older versions.
+func GetLegacyProjectID(volumeID string) uint32 {
+	hash := sha256.Sum256([]byte(volumeID))
+	return binary.LittleEndian.Uint32(hash[:8])
+}
+
+// GetProjectID returns XFS project ID of given path.
+func GetProjectID(path string) (uint32, error) {
+	return getProjectID(path)
 }
 
-// GetQuota returns XFS quota information of given volume ID.
-func GetQuota(ctx context.Context, device, volumeID string) (quota *Quota, err error) {
+// GetQuota returns XFS quota information of given project ID.
+func GetQuota(ctx context.Context, device string, projectID uint32) (quota *Quota, err error) {
 	doneCh := make(chan struct{})
 	go func() {
-		quota, err = getQuota(device, volumeID)
+		quota, err = getQuota(device, projectID)
 		close(doneCh)
 	}()
 
@@ -49,11 +66,11 @@ func GetQuota(ctx context.Context, device, volumeID string) (quota *Quota, err e
 	return quota, err
 }
 
-// SetQuota sets quota information on given path and volume ID.
-func SetQuota(ctx context.Context, device, path, volumeID string, quota Quota, update bool) (err error) {
+// SetQuota sets quota information on given path and project ID.
+func SetQuota(ctx context.Context, device, path string, projectID uint32, quota Quota, update bool) (err error) {
 	doneCh := make(chan struct{})
 	go func() {
-		err = setQuota(device, path, volumeID, quota, update)
+		err = setQuota(device, path, projectID, quota, update)
 		close(doneCh)
 	}()
 
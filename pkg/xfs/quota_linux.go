@@ -19,13 +19,11 @@
 package xfs
 
 import (
-	"encoding/binary"
 	"math"
 	"os"
 	"syscall"
 	"unsafe"
 
-	sha256 "github.com/minio/sha256-simd"
 	"k8s.io/klog/v2"
 )
 
@@ -45,8 +43,10 @@ const (
 
 	fsDiskQuotaVersion  = 1
 	xfsProjectQuotaFlag = 2
-	fieldMaskBHard      = 8
+	fieldMaskISoft      = 1
+	fieldMaskIHard      = 2
 	fieldMaskBSoft      = 4
+	fieldMaskBHard      = 8
 	blockSize           = 512
 
 	fsGetAttr          = 0x801c581f // FS_IOC_FSGETXATTR
@@ -63,10 +63,10 @@ type fsDiskQuota struct {
 	id              uint32  // User, project, or group ID
 	hardLimitBlocks uint64  // Absolute limit on disk blocks
 	softLimitBlocks uint64  // Preferred limit on disk blocks
-	_               uint64  // hardLimitInodes: Maximum allocated inodes
-	_               uint64  // softLimitInodes: Preferred inode limit
+	hardLimitInodes uint64  // Maximum allocated inodes
+	softLimitInodes uint64  // Preferred inode limit
 	blocksCount     uint64  // disk blocks owned by the project/user/group
-	_               uint64  // inodesCount: inodes owned by the project/user/group
+	inodesCount     uint64  // inodes owned by the project/user/group
 	_               int32   // inodeTimer: Zero if within inode limits, If not, we refuse service
 	_               int32   // blocksTimer: Similar to above; for disk blocks
 	_               uint16  // inodeWarnings: warnings issued with respect to number of inodes
@@ -90,17 +90,11 @@ type fsXAttr struct {
 	_         [8]byte // fsXPad
 }
 
-func getProjectIDHash(id string) uint32 {
-	hash := sha256.Sum256([]byte(id))
-	return binary.LittleEndian.Uint32(hash[:8])
-}
-
-func getQuota(device, volumeID string) (*Quota, error) {
+func getQuota(device string, projectID uint32) (*Quota, error) {
 	deviceNamePtr, err := syscall.BytePtrFromString(device)
 	if err != nil {
 		return nil, err
 	}
-	projectID := int(getProjectIDHash(volumeID))
 
 	result := &fsDiskQuota{}
 	_, _, errno := syscall.RawSyscall6(
@@ -117,12 +111,36 @@ func getQuota(device, volumeID string) (*Quota, error) {
 	}
 
 	return &Quota{
-		HardLimit:    result.hardLimitBlocks * blockSize,
-		SoftLimit:    result.softLimitBlocks * blockSize,
-		CurrentSpace: result.blocksCount * blockSize,
+		HardLimit:      result.hardLimitBlocks * blockSize,
+		SoftLimit:      result.softLimitBlocks * blockSize,
+		CurrentSpace:   result.blocksCount * blockSize,
+		InodeHardLimit: result.hardLimitInodes,
+		InodeSoftLimit: result.softLimitInodes,
+		CurrentInodes:  result.inodesCount,
 	}, nil
 }
 
+func getProjectID(path string) (uint32, error) {
+	dir, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer dir.Close()
+
+	var fsx fsXAttr
+	_, _, errno := syscall.Syscall(
+		syscall.SYS_IOCTL,
+		dir.Fd(),
+		fsGetAttr,
+		uintptr(unsafe.Pointer(&fsx)),
+	)
+	if errno != 0 {
+		return 0, os.NewSyscallError("FS_IOC_FSGETXATTR", errno)
+	}
+
+	return fsx.fsXProjID, nil
+}
+
 func setProjectID(path string, projectID uint32) error {
 	targetDir, err := os.Open(path)
 	if err != nil {
@@ -163,10 +181,12 @@ func setProjectQuota(device string, projectID uint32, quota Quota) error {
 	fsQuota := &fsDiskQuota{
 		version:         int8(fsDiskQuotaVersion),
 		flags:           int8(xfsProjectQuotaFlag),
-		fieldmask:       uint16(fieldMaskBHard | fieldMaskBSoft),
+		fieldmask:       uint16(fieldMaskBHard | fieldMaskBSoft | fieldMaskIHard | fieldMaskISoft),
 		id:              projectID,
 		hardLimitBlocks: hardLimitBlocks,
 		softLimitBlocks: softLimitBlocks,
+		hardLimitInodes: quota.InodeHardLimit,
+		softLimitInodes: quota.InodeSoftLimit,
 	}
 
 	deviceNamePtr, err := syscall.BytePtrFromString(device)
@@ -190,19 +210,23 @@ func setProjectQuota(device string, projectID uint32, quota Quota) error {
 	return nil
 }
 
-func setQuota(device, path, volumeID string, quota Quota, update bool) error {
-	projectID := getProjectIDHash(volumeID)
-
+func setQuota(device, path string, projectID uint32, quota Quota, update bool) error {
 	if !update {
-		if info, err := getQuota(device, volumeID); err == nil {
-			if info.HardLimit == quota.HardLimit {
+		// Project ID may be reused from a deleted volume; hence project ID is always set on the path.
+		if err := setProjectID(path, projectID); err != nil {
+			klog.ErrorS(err, "unable to set project ID", "Device", device, "Path", path)
+			return err
+		}
+
+		if info, err := getQuota(device, projectID); err == nil {
+			if info.HardLimit == quota.HardLimit && info.InodeHardLimit == quota.InodeHardLimit {
 				klog.V(3).InfoS(
 					"Quota is already set",
 					"Device", device,
 					"Path", path,
-					"VolumeID", volumeID,
 					"ProjectID", projectID,
 					"HardLimit", info.HardLimit,
+					"InodeHardLimit", info.InodeHardLimit,
 				)
 				return nil
 			}
@@ -211,14 +235,12 @@ func setQuota(device, path, volumeID string, quota Quota, update bool) error {
 				"Quota differs",
 				"Device", device,
 				"Path", path,
-				"VolumeID", volumeID,
 				"ProjectID", projectID,
 				"HardLimitSet", info.HardLimit,
 				"HardLimit", quota.HardLimit,
+				"InodeHardLimitSet", info.InodeHardLimit,
+				"InodeHardLimit", quota.InodeHardLimit,
 			)
-		} else if err := setProjectID(path, projectID); err != nil {
-			klog.ErrorS(err, "unable to set project ID", "Device", device, "Path", path)
-			return err
 		}
 	}
 
@@ -231,9 +253,9 @@ func setQuota(device, path, volumeID string, quota Quota, update bool) error {
 		"SetQuota succeeded",
 		"Device", device,
 		"Path", path,
-		"VolumeID", volumeID,
 		"ProjectID", projectID,
 		"HardLimit", quota.HardLimit,
+		"InodeHardLimit", quota.InodeHardLimit,
 	)
 	return nil
 }
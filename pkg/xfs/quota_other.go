@@ -23,10 +23,14 @@ import (
 	"runtime"
 )
 
-func getQuota(device, volumeID string) (*Quota, error) {
+func getQuota(device string, projectID uint32) (*Quota, error) {
 	return nil, fmt.Errorf("unsupported operating system %v", runtime.GOOS)
 }
 
-func setQuota(device, path, volumeID string, quota Quota, update bool) error {
+func getProjectID(path string) (uint32, error) {
+	return 0, fmt.Errorf("unsupported operating system %v", runtime.GOOS)
+}
+
+func setQuota(device, path string, projectID uint32, quota Quota, update bool) error {
 	return fmt.Errorf("unsupported operating system %v", runtime.GOOS)
 }
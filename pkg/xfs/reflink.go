@@ -0,0 +1,43 @@
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package xfs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrReflinkNotSupported denotes filesystem does not support reflink.
+var ErrReflinkNotSupported = errors.New("reflink not supported by filesystem")
+
+// Reflink clones source directory tree to target directory by sharing data
+// blocks. Target directory must not exist. Error satisfying os.ErrNotExist is
+// returned if source directory does not exist.
+func Reflink(ctx context.Context, source, target string) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- reflink(ctx, source, target)
+	}()
+
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("%w; %v", ErrCanceled, ctx.Err())
+	case err := <-errCh:
+		return err
+	}
+}
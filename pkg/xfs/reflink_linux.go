@@ -0,0 +1,62 @@
+//go:build linux
+
+// This file is part of MinIO DirectPV
+// Copyright (c) 2024 MinIO, Inc.
+//
+// This program is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Affero General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// This program is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+// GNU Affero General Public License for more details.
+//
+// You should have received a copy of the GNU Affero General Public License
+// along with this program.  If not, see <http://www.gnu.org/licenses/>.
+
+package xfs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Refer https://man7.org/linux/man-pages/man2/ioctl_ficlone.2.html
+const ficlone = 0x40049409
+
+func cloneFile(source, target string, mode os.FileMode) error {
+	src, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode.Perm())
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, dst.Fd(), ficlone, src.Fd()); errno != 0 {
+		if errno == syscall.EOPNOTSUPP || errno == syscall.EXDEV || errno == syscall.EINVAL {
+			return fmt.Errorf("%w; %v", ErrReflinkNotSupported, errno)
+		}
+		return &os.PathError{Op: "ficlone", Path: target, Err: errno}
+	}
+
+	return nil
+}
+
+func reflink(ctx context.Context, source, target string) error {
+	if _, err := os.Stat(source); err != nil {
+		return err
+	}
+
+	return copyTree(ctx, source, target, func(source, target string, info os.FileInfo) error {
+		return cloneFile(source, target, info.Mode())
+	})
+}